@@ -0,0 +1,122 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Command goasm assembles a source file into a binary image and an optional symbol table, or an object file.
+//
+// Usage:
+//
+//	goasm [-o file.bin] [-sym file.sym] file.s
+//	goasm -c [-o file.o] file.s
+//
+// The binary defaults to the source file name with the extension replaced by .bin.
+// The symbol table is written by asm.WriteSymbols.
+// With -c, the object file defaults to the source file name with the extension replaced by .o, and contains the
+// symbol table.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bantling/goprocessor/pkg/asm"
+	"github.com/bantling/goprocessor/pkg/obj"
+)
+
+// run assembles the source named by args, and returns any error
+func run(args []string) error {
+	var (
+		fs     = flag.NewFlagSet("goasm", flag.ContinueOnError)
+		object = fs.Bool("c", false, "write an object file")
+		out    = fs.String("o", "", "output `file` (default source with .bin or .o extension)")
+		syms   = fs.String("sym", "", "symbol table output `file`")
+	)
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("exactly one source file is required")
+	}
+
+	if *object && (*syms != "") {
+		return fmt.Errorf("an object file contains the symbol table, -sym cannot be used with -c")
+	}
+
+	src := fs.Arg(0)
+	if *out == "" {
+		ext := ".bin"
+		if *object {
+			ext = ".o"
+		}
+
+		*out = strings.TrimSuffix(src, filepath.Ext(src)) + ext
+	}
+
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if *object {
+		return assembleObject(src, f, *out)
+	}
+
+	prog, err := asm.Assemble(src, f)
+	if err != nil {
+		return err
+	}
+
+	if err := ioutil.WriteFile(*out, prog.Code, 0644); err != nil {
+		return err
+	}
+
+	if *syms != "" {
+		sf, err := os.Create(*syms)
+		if err != nil {
+			return err
+		}
+		defer sf.Close()
+
+		if err := asm.WriteSymbols(sf, prog.Symbols); err != nil {
+			return err
+		}
+
+		return sf.Close()
+	}
+
+	return nil
+}
+
+// assembleObject assembles the source read from f into an object file named out
+func assembleObject(src string, f io.Reader, out string) error {
+	file, err := asm.AssembleObject(src, f)
+	if err != nil {
+		return err
+	}
+
+	of, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+	defer of.Close()
+
+	if err := obj.Write(of, file); err != nil {
+		return err
+	}
+
+	return of.Close()
+}
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,566 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Command godbg debugs a binary image interactively.
+//
+// Usage:
+//
+//	godbg [-cp address] [-size 8|16|32|64] [-sym file.sym] [-gdb network:address] file.bin
+//
+// The image is loaded at the CP address and starts at PC 0. Labels in the symbol table, as written by goasm, are
+// relative to CP, and can be used wherever an address is expected. Other addresses are absolute.
+// Commands are read from standard input:
+//
+//	step [n]                              execute n instructions (s)
+//	next                                  step over a JSR or JSA (n)
+//	finish                                run until the current subroutine returns (out)
+//	continue                              run until a breakpoint or watchpoint (c)
+//	break addr [if register op value]     set a breakpoint, optionally only when a register condition is true (b)
+//	delete addr                           delete a breakpoint
+//	watch addr [len] [r|w|c]              watch len bytes for reads, writes, or changes, default 1 byte and writes
+//	unwatch addr                          delete the watchpoints at an address
+//	info                                  list breakpoints and watchpoints
+//	regs                                  show the registers
+//	x addr [len]                          show len bytes of memory, default 16
+//	record [n]                            record the changes of the last n instructions, default 100000, 0 stops
+//	back [n]                              undo the last n recorded instructions
+//	backto addr                           undo recorded instructions up to the last write to addr
+//	rewind cycle                          undo recorded instructions back to a cycle
+//	save file                             save a snapshot of the registers, execution state, and memory
+//	load file                             restore a snapshot, discarding any recorded instructions
+//	quit                                  exit (q)
+//
+// Registers in conditions are the fields of register.Registers or ST, and op is one of == != < <= > >=.
+// After every step or stop, the next instruction and the decoded status register are shown.
+// An interrupt stops continue, next, and finish.
+//
+// With -gdb, commands are not read, instead clients of the GDB Remote Serial Protocol are served one at a time on a
+// tcp or unix socket, EG -gdb tcp:localhost:1234 or -gdb unix:/tmp/godbg.sock.
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+
+	"github.com/bantling/goprocessor/pkg/asm"
+	"github.com/bantling/goprocessor/pkg/debug"
+	"github.com/bantling/goprocessor/pkg/disasm"
+	"github.com/bantling/goprocessor/pkg/gdb"
+	"github.com/bantling/goprocessor/pkg/memory"
+	"github.com/bantling/goprocessor/pkg/register"
+	"github.com/bantling/goprocessor/pkg/snapshot"
+)
+
+// watchKinds maps the kind argument of watch to a kind of watchpoint
+var watchKinds = map[string]debug.WatchKind{
+	"r": debug.WatchRead,
+	"w": debug.WatchWrite,
+	"c": debug.WatchChange,
+}
+
+// session is the state of an interactive debugging session
+type session struct {
+	dbg     *debug.Debugger
+	ram     *memory.RAM
+	names   disasm.Names      // CP relative labels by value
+	symbols map[string]uint32 // CP relative labels by name
+	out     io.Writer
+}
+
+// readSymbols reads the labels of a symbol table
+func (s *session) readSymbols(file string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	syms, err := asm.ReadSymbols(f)
+	if err != nil {
+		return err
+	}
+
+	for _, sym := range syms {
+		if sym.Label {
+			s.names[uint32(sym.Value)] = sym.Name
+			s.symbols[sym.Name] = uint32(sym.Value)
+		}
+	}
+
+	return nil
+}
+
+// addr parses an absolute address or a label
+func (s *session) addr(arg string) (uint32, error) {
+	if val, ok := s.symbols[arg]; ok {
+		return s.dbg.Processor().Registers().CP + val, nil
+	}
+
+	val, err := strconv.ParseUint(arg, 0, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid address %s", arg)
+	}
+
+	return uint32(val), nil
+}
+
+// count parses an optional count argument
+func count(args []string, i int, def uint64) (uint64, error) {
+	if len(args) <= i {
+		return def, nil
+	}
+
+	val, err := strconv.ParseUint(args[i], 0, 32)
+	if (err != nil) || (val == 0) {
+		return 0, fmt.Errorf("invalid count %s", args[i])
+	}
+
+	return val, nil
+}
+
+// show shows why execution stopped, the next instruction, and the status register
+func (s *session) show(stop debug.Stop) {
+	switch stop.Reason {
+	case debug.StopBreakpoint:
+		fmt.Fprintf(s.out, "breakpoint %08X\n", stop.Breakpoint.Addr)
+	case debug.StopWatchpoint:
+		a := stop.Access
+		if a.Write {
+			fmt.Fprintf(s.out, "watchpoint %s %08X: write %d bytes at %08X: %X -> %X\n", stop.Watchpoint.Kind,
+				stop.Watchpoint.Addr, a.Size, a.Addr, a.Old, a.New)
+		} else {
+			fmt.Fprintf(s.out, "watchpoint %s %08X: read %d bytes at %08X: %X\n", stop.Watchpoint.Kind,
+				stop.Watchpoint.Addr, a.Size, a.Addr, a.New)
+		}
+	}
+
+	regs := s.dbg.Processor().Registers()
+	fmt.Fprintf(s.out, "%08X+%08X  %s\n", regs.CP, regs.PC, s.dbg.Instruction().Text(s.names))
+	fmt.Fprintf(s.out, "ST %s\n", regs.ST())
+}
+
+// regs shows the registers
+func (s *session) regs() {
+	r := s.dbg.Processor().Registers()
+	fmt.Fprintf(s.out, "R0   %016X  R0c  %016X  R1   %016X  R1c  %016X\n", r.R0, r.R1, r.R2, r.R3)
+	fmt.Fprintf(s.out, "DP0  %08X  DP1  %08X  PTR0 %08X  PTR1 %08X\n", r.DP0, r.DP1, r.PTR0, r.PTR1)
+	fmt.Fprintf(s.out, "OFS0 %04X  OFS1 %04X  IX0  %04X  IX1  %04X  IS0  %04X  IS1  %04X\n",
+		r.OFS0, r.OFS1, r.IX0, r.IX1, r.IS0, r.IS1)
+	fmt.Fprintf(s.out, "CTR0 %08X  CTR1 %08X  CS0  %04X  CS1  %04X\n", r.CTR0, r.CTR1, r.CS0, r.CS1)
+	fmt.Fprintf(s.out, "TMR  %08X %08X %08X %08X  TPTR %08X %08X %08X %08X\n",
+		r.TMR0, r.TMR1, r.TMR2, r.TMR3, r.TPTR0, r.TPTR1, r.TPTR2, r.TPTR3)
+	fmt.Fprintf(s.out, "CP   %08X  PC   %08X  SB   %08X  SL   %04X  SP   %04X\n", r.CP, r.PC, r.SB, r.SL, r.SP)
+	fmt.Fprintf(s.out, "ST   %s\n", r.ST())
+	fmt.Fprintf(s.out, "CYCLES %d  INSTRUCTIONS %d\n", s.dbg.Processor().Cycles(), s.dbg.Processor().Instructions())
+}
+
+// dump shows size bytes of memory at addr, 16 bytes per line
+func (s *session) dump(addr uint32, size uint64) error {
+	buf := make([]byte, size)
+	if err := memory.ReadBytes(s.dbg.Bus(), addr, buf); err != nil {
+		return err
+	}
+
+	for i := 0; i < len(buf); i += 16 {
+		line := buf[i:]
+		if len(line) > 16 {
+			line = line[:16]
+		}
+
+		fmt.Fprintf(s.out, "%08X  % X\n", addr+uint32(i), line)
+	}
+
+	return nil
+}
+
+// interruptible returns a context that is cancelled by an interrupt, and a func to stop listening for interrupts
+func interruptible() (context.Context, func()) {
+	var (
+		ctx, cancel = context.WithCancel(context.Background())
+		sig         = make(chan os.Signal, 1)
+		done        = make(chan struct{})
+	)
+
+	signal.Notify(sig, os.Interrupt)
+	go func() {
+		select {
+		case <-sig:
+			cancel()
+		case <-done:
+		}
+	}()
+
+	return ctx, func() {
+		signal.Stop(sig)
+		close(done)
+		cancel()
+	}
+}
+
+// machine returns the machine that snapshots save and restore
+func (s *session) machine() snapshot.Machine {
+	return snapshot.Machine{Processor: s.dbg.Processor(), RAM: s.ram}
+}
+
+// save saves a snapshot to a file
+func (s *session) save(file string) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+
+	if err := snapshot.Save(f, s.machine()); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// load restores a snapshot from a file
+func (s *session) load(file string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := snapshot.Restore(f, s.machine()); err != nil {
+		return fmt.Errorf("%s: %w", file, err)
+	}
+
+	s.dbg.Forget()
+	return nil
+}
+
+// execute executes one command, and returns true if the session is over
+func (s *session) execute(fields []string) (bool, error) {
+	var (
+		cmd, args = fields[0], fields[1:]
+		stop      debug.Stop
+		err       error
+	)
+
+	switch cmd {
+	case "step", "s":
+		var n uint64
+		if n, err = count(args, 0, 1); err != nil {
+			return false, err
+		}
+
+		for i := uint64(0); (i < n) && (err == nil) && (stop.Reason == debug.StopStep); i++ {
+			stop, err = s.dbg.Step()
+		}
+
+	case "next", "n", "finish", "out", "continue", "c":
+		ctx, release := interruptible()
+		switch cmd {
+		case "next", "n":
+			stop, err = s.dbg.StepOver(ctx)
+		case "finish", "out":
+			stop, err = s.dbg.StepOut(ctx)
+		default:
+			stop, err = s.dbg.Continue(ctx)
+		}
+		release()
+
+	case "break", "b":
+		if (len(args) != 1) && ((len(args) != 5) || (args[1] != "if")) {
+			return false, fmt.Errorf("usage: break addr [if register op value]")
+		}
+
+		addr, err := s.addr(args[0])
+		if err != nil {
+			return false, err
+		}
+
+		var cond *debug.Condition
+		if len(args) == 5 {
+			if cond, err = debug.ParseCondition(strings.Join(args[2:], " ")); err != nil {
+				return false, err
+			}
+		}
+
+		s.dbg.Break(addr, cond)
+		return false, nil
+
+	case "delete":
+		if len(args) != 1 {
+			return false, fmt.Errorf("usage: delete addr")
+		}
+
+		addr, err := s.addr(args[0])
+		if err != nil {
+			return false, err
+		}
+
+		if !s.dbg.Delete(addr) {
+			return false, fmt.Errorf("no breakpoint at %08X", addr)
+		}
+
+		return false, nil
+
+	case "watch":
+		if (len(args) == 0) || (len(args) > 3) {
+			return false, fmt.Errorf("usage: watch addr [len] [r|w|c]")
+		}
+
+		w := debug.Watchpoint{Size: 1, Kind: debug.WatchWrite}
+		if w.Addr, err = s.addr(args[0]); err != nil {
+			return false, err
+		}
+
+		if kind, ok := watchKinds[args[len(args)-1]]; ok && (len(args) > 1) {
+			w.Kind, args = kind, args[:len(args)-1]
+		}
+
+		size, err := count(args, 1, 1)
+		if err != nil {
+			return false, err
+		}
+
+		w.Size = uint32(size)
+		s.dbg.Watch(w)
+		return false, nil
+
+	case "unwatch":
+		if len(args) != 1 {
+			return false, fmt.Errorf("usage: unwatch addr")
+		}
+
+		addr, err := s.addr(args[0])
+		if err != nil {
+			return false, err
+		}
+
+		if !s.dbg.Unwatch(addr) {
+			return false, fmt.Errorf("no watchpoint at %08X", addr)
+		}
+
+		return false, nil
+
+	case "info":
+		for _, b := range s.dbg.Breakpoints() {
+			if b.Cond != nil {
+				fmt.Fprintf(s.out, "break %08X if %s\n", b.Addr, b.Cond)
+			} else {
+				fmt.Fprintf(s.out, "break %08X\n", b.Addr)
+			}
+		}
+
+		for _, w := range s.dbg.Watchpoints() {
+			fmt.Fprintf(s.out, "watch %08X %d %s\n", w.Addr, w.Size, w.Kind)
+		}
+
+		return false, nil
+
+	case "regs":
+		s.regs()
+		return false, nil
+
+	case "x":
+		if (len(args) == 0) || (len(args) > 2) {
+			return false, fmt.Errorf("usage: x addr [len]")
+		}
+
+		addr, err := s.addr(args[0])
+		if err != nil {
+			return false, err
+		}
+
+		size, err := count(args, 1, 16)
+		if err != nil {
+			return false, err
+		}
+
+		return false, s.dump(addr, size)
+
+	case "record":
+		n := uint64(100000)
+		if len(args) > 0 {
+			if n, err = strconv.ParseUint(args[0], 0, 32); err != nil {
+				return false, fmt.Errorf("invalid count %s", args[0])
+			}
+		}
+
+		s.dbg.Record(int(n))
+		return false, nil
+
+	case "back":
+		var n uint64
+		if n, err = count(args, 0, 1); err != nil {
+			return false, err
+		}
+
+		for i := uint64(0); (i < n) && (err == nil); i++ {
+			err = s.dbg.StepBack()
+		}
+
+	case "backto":
+		if len(args) != 1 {
+			return false, fmt.Errorf("usage: backto addr")
+		}
+
+		var addr uint32
+		if addr, err = s.addr(args[0]); err != nil {
+			return false, err
+		}
+
+		err = s.dbg.BackToWrite(addr)
+
+	case "rewind":
+		if len(args) != 1 {
+			return false, fmt.Errorf("usage: rewind cycle")
+		}
+
+		cycle, perr := strconv.ParseUint(args[0], 0, 64)
+		if perr != nil {
+			return false, fmt.Errorf("invalid cycle %s", args[0])
+		}
+
+		err = s.dbg.Rewind(cycle)
+
+	case "save", "load":
+		if len(args) != 1 {
+			return false, fmt.Errorf("usage: %s file", cmd)
+		}
+
+		if cmd == "save" {
+			return false, s.save(args[0])
+		}
+
+		if err = s.load(args[0]); err != nil {
+			return false, err
+		}
+
+	case "quit", "q":
+		return true, nil
+
+	case "help", "h":
+		fmt.Fprintln(s.out, "step [n], next, finish, continue, break addr [if register op value], delete addr,")
+		fmt.Fprintln(s.out, "watch addr [len] [r|w|c], unwatch addr, info, regs, x addr [len], record [n], back [n],")
+		fmt.Fprintln(s.out, "backto addr, rewind cycle, save file, load file, quit")
+		return false, nil
+
+	default:
+		return false, fmt.Errorf("unknown command %s, try help", cmd)
+	}
+
+	// Show where execution is even if the instruction failed
+	s.show(stop)
+	return false, err
+}
+
+// run debugs the binary named by args, and returns any error
+func run(args []string) error {
+	var (
+		fs    = flag.NewFlagSet("godbg", flag.ContinueOnError)
+		cp    = fs.String("cp", "0", "CP `address` to load the image at")
+		size  = fs.Int("size", 8, "initial operand `size`")
+		syms  = fs.String("sym", "", "symbol table `file`")
+		serve = fs.String("gdb", "", "serve the GDB remote protocol on `network:address`")
+	)
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("exactly one binary file is required")
+	}
+
+	addr, err := strconv.ParseUint(*cp, 0, 32)
+	if err != nil {
+		return fmt.Errorf("invalid -cp %s", *cp)
+	}
+
+	operandSize, ok := register.OperandSizeOfBits(*size)
+	if !ok {
+		return fmt.Errorf("-size must be 8, 16, 32, or 64")
+	}
+
+	code, err := ioutil.ReadFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	ram := memory.NewRAM()
+	if err := memory.WriteBytes(ram, uint32(addr), code); err != nil {
+		return err
+	}
+
+	s := &session{
+		dbg:     debug.New(ram),
+		ram:     ram,
+		names:   disasm.Names{},
+		symbols: map[string]uint32{},
+		out:     os.Stdout,
+	}
+
+	if *syms != "" {
+		if err := s.readSymbols(*syms); err != nil {
+			return err
+		}
+	}
+
+	regs := s.dbg.Processor().Registers()
+	regs.CP = uint32(addr)
+	st := regs.ST()
+	st.SelectOperandSize(operandSize)
+	regs.SetST(st)
+
+	if *serve != "" {
+		parts := strings.SplitN(*serve, ":", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("invalid -gdb %s", *serve)
+		}
+
+		l, err := net.Listen(parts[0], parts[1])
+		if err != nil {
+			return err
+		}
+		defer l.Close()
+
+		fmt.Fprintf(s.out, "serving %s\n", l.Addr())
+		return gdb.NewServer(s.dbg).Serve(l)
+	}
+
+	s.show(debug.Stop{})
+	for in := bufio.NewScanner(os.Stdin); ; {
+		fmt.Fprint(s.out, "(godbg) ")
+		if !in.Scan() {
+			fmt.Fprintln(s.out)
+			return in.Err()
+		}
+
+		fields := strings.Fields(in.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		quit, err := s.execute(fields)
+		if err != nil {
+			fmt.Fprintln(s.out, err)
+		}
+
+		if quit {
+			return nil
+		}
+	}
+}
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
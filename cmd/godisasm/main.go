@@ -0,0 +1,102 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Command godisasm disassembles a binary image into a listing that goasm assembles back to the same bytes.
+//
+// Usage:
+//
+//	godisasm [-org address] [-size 8|16|32|64] [-sym file.sym] file.bin
+//
+// The listing is written to standard output. Labels in the symbol table, as written by goasm, name branch and jump
+// targets.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+
+	"github.com/bantling/goprocessor/pkg/asm"
+	"github.com/bantling/goprocessor/pkg/disasm"
+	"github.com/bantling/goprocessor/pkg/register"
+)
+
+// readNames reads the labels of a symbol table
+func readNames(file string) (disasm.Names, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	syms, err := asm.ReadSymbols(f)
+	if err != nil {
+		return nil, err
+	}
+
+	names := disasm.Names{}
+	for _, sym := range syms {
+		if sym.Label {
+			names[uint32(sym.Value)] = sym.Name
+		}
+	}
+
+	return names, nil
+}
+
+// run disassembles the binary named by args, and returns any error
+func run(args []string) error {
+	var (
+		fs   = flag.NewFlagSet("godisasm", flag.ContinueOnError)
+		org  = fs.String("org", "0", "`address` of the first byte")
+		size = fs.Int("size", 8, "initial operand `size`")
+		syms = fs.String("sym", "", "symbol table `file`")
+	)
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("exactly one binary file is required")
+	}
+
+	origin, err := strconv.ParseUint(*org, 0, 32)
+	if err != nil {
+		return fmt.Errorf("invalid -org %s", *org)
+	}
+
+	operandSize, ok := register.OperandSizeOfBits(*size)
+	if !ok {
+		return fmt.Errorf("-size must be 8, 16, 32, or 64")
+	}
+
+	var names disasm.Names
+	if *syms != "" {
+		if names, err = readNames(*syms); err != nil {
+			return err
+		}
+	}
+
+	code, err := ioutil.ReadFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(os.Stdout)
+	if err := disasm.Write(w, disasm.Disassemble(code, uint32(origin), operandSize), names); err != nil {
+		return err
+	}
+
+	return w.Flush()
+}
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,135 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Command golink links object files written by goasm -c into a binary image and an optional map file.
+//
+// Usage:
+//
+//	golink -T script [-o file.bin] [-os file.bin] [-map file.map] file.o ...
+//
+// The script is read by link.ReadScript, and names the object files of OS routines, which are read relative to the
+// current directory.
+// The binary of the program defaults to the first object file name with the extension replaced by .bin, and begins
+// at the lowest address of the code and data. The binary of the OS routines defaults to the program binary name with
+// the extension replaced by .os.bin, and is only written if the script has OS routines or a reset symbol.
+// The map file is written by link.WriteMap.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bantling/goprocessor/pkg/link"
+	"github.com/bantling/goprocessor/pkg/obj"
+)
+
+// readObjects reads object files
+func readObjects(files []string) ([]link.Object, error) {
+	var objects []link.Object
+	for _, file := range files {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, err
+		}
+
+		o, err := obj.Read(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+
+		objects = append(objects, link.Object{Name: file, File: o})
+	}
+
+	return objects, nil
+}
+
+// run links the objects named by args, and returns any error
+func run(args []string) error {
+	var (
+		fs     = flag.NewFlagSet("golink", flag.ContinueOnError)
+		script = fs.String("T", "", "linker script `file`")
+		out    = fs.String("o", "", "program binary output `file` (default first object with .bin extension)")
+		osOut  = fs.String("os", "", "OS routines binary output `file` (default program binary with .os.bin extension)")
+		mapOut = fs.String("map", "", "map output `file`")
+	)
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if (*script == "") || (fs.NArg() == 0) {
+		fs.Usage()
+		return fmt.Errorf("a script and at least one object file are required")
+	}
+
+	if *out == "" {
+		*out = strings.TrimSuffix(fs.Arg(0), filepath.Ext(fs.Arg(0))) + ".bin"
+	}
+
+	if *osOut == "" {
+		*osOut = strings.TrimSuffix(*out, filepath.Ext(*out)) + ".os.bin"
+	}
+
+	sf, err := os.Open(*script)
+	if err != nil {
+		return err
+	}
+	defer sf.Close()
+
+	s, err := link.ReadScript(sf)
+	if err != nil {
+		return fmt.Errorf("%s: %w", *script, err)
+	}
+
+	program, err := readObjects(fs.Args())
+	if err != nil {
+		return err
+	}
+
+	routines, err := readObjects(s.OS)
+	if err != nil {
+		return err
+	}
+
+	img, err := link.Link(s, program, routines)
+	if err != nil {
+		return err
+	}
+
+	if err := ioutil.WriteFile(*out, img.Program.Bytes, 0644); err != nil {
+		return err
+	}
+
+	if len(img.OS.Bytes) > 0 {
+		if err := ioutil.WriteFile(*osOut, img.OS.Bytes, 0644); err != nil {
+			return err
+		}
+	}
+
+	if *mapOut != "" {
+		mf, err := os.Create(*mapOut)
+		if err != nil {
+			return err
+		}
+		defer mf.Close()
+
+		if err := link.WriteMap(mf, img); err != nil {
+			return err
+		}
+
+		return mf.Close()
+	}
+
+	return nil
+}
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,369 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Command gotrace writes, filters, and compares execution traces.
+//
+// Usage:
+//
+//	gotrace run [-cp address] [-size 8|16|32|64] [-n count] [-binary] [-o file] file.bin
+//	gotrace filter [-from cycle] [-to cycle] [-addr address[-address]] [-op mnemonic] [-reg name] [-binary] [-o file] trace
+//	gotrace diff trace trace
+//
+// Run loads a binary image at the CP address, and traces it from PC 0 until an instruction fails, count instructions
+// have executed, or it is interrupted.
+// Filter writes the records of a trace that match every filter: cycles in a range, addresses (CP + PC) in a range,
+// a mnemonic, or a change to a register named by a field of register.Registers.
+// Traces are written as JSON Lines by default, or the binary format with -binary, to standard output by default.
+// Either format can be read.
+// Diff writes the first records at which two traces diverge as JSON Lines, prefixed by < for the first trace and > for
+// the second, and exits with status 1. A trace that ended is shown as < end or > end.
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+
+	"github.com/bantling/goprocessor/pkg/cpu"
+	"github.com/bantling/goprocessor/pkg/memory"
+	"github.com/bantling/goprocessor/pkg/register"
+	"github.com/bantling/goprocessor/pkg/trace"
+)
+
+// errDiverge is returned by diff when the traces diverge
+var errDiverge = errors.New("traces diverge")
+
+// output opens the output file, or returns standard output if file is empty, and a func to close it
+func output(file string) (io.Writer, func() error, error) {
+	if file == "" {
+		return os.Stdout, func() error { return nil }, nil
+	}
+
+	f, err := os.Create(file)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return f, f.Close, nil
+}
+
+// writer constructs a trace writer of the selected format
+func writer(w io.Writer, binary bool) (trace.Writer, error) {
+	if binary {
+		return trace.NewBinaryWriter(w)
+	}
+
+	return trace.NewJSONWriter(w), nil
+}
+
+// reader opens a trace
+func reader(file string) (trace.Reader, func() error, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r, err := trace.NewReader(f)
+	if err != nil {
+		f.Close()
+		return nil, nil, fmt.Errorf("%s: %w", file, err)
+	}
+
+	return r, f.Close, nil
+}
+
+// runTrace traces a binary image
+func runTrace(args []string) error {
+	var (
+		fs     = flag.NewFlagSet("gotrace run", flag.ContinueOnError)
+		cp     = fs.String("cp", "0", "CP `address` to load the image at")
+		size   = fs.Int("size", 8, "initial operand `size`")
+		count  = fs.Uint64("n", 0, "maximum `count` of instructions, 0 for no limit")
+		binary = fs.Bool("binary", false, "write the binary format")
+		out    = fs.String("o", "", "output `file` (default standard output)")
+	)
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("exactly one binary file is required")
+	}
+
+	addr, err := strconv.ParseUint(*cp, 0, 32)
+	if err != nil {
+		return fmt.Errorf("invalid -cp %s", *cp)
+	}
+
+	operandSize, ok := register.OperandSizeOfBits(*size)
+	if !ok {
+		return fmt.Errorf("-size must be 8, 16, 32, or 64")
+	}
+
+	code, err := ioutil.ReadFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	ram := memory.NewRAM()
+	if err := memory.WriteBytes(ram, uint32(addr), code); err != nil {
+		return err
+	}
+
+	p := cpu.New(ram)
+	regs := p.Registers()
+	regs.CP = uint32(addr)
+	st := regs.ST()
+	st.SelectOperandSize(operandSize)
+	regs.SetST(st)
+
+	o, closer, err := output(*out)
+	if err != nil {
+		return err
+	}
+	defer closer()
+
+	w, err := writer(o, *binary)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt)
+	go func() {
+		<-sig
+		cancel()
+	}()
+
+	var (
+		t    = trace.NewTracer(p, w)
+		done = ctx.Done()
+	)
+
+steps:
+	for n := uint64(0); (*count == 0) || (n < *count); n++ {
+		select {
+		case <-done:
+			break steps
+		default:
+		}
+
+		if err = t.Step(); err != nil {
+			break
+		}
+	}
+
+	// An instruction that fails ends the program
+	var insErr *cpu.InstructionError
+	if errors.As(err, &insErr) {
+		fmt.Fprintln(os.Stderr, err)
+		err = nil
+	}
+
+	if ferr := w.Flush(); err == nil {
+		err = ferr
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return closer()
+}
+
+// filterTrace writes the records of a trace that match the filters
+func filterTrace(args []string) error {
+	var (
+		fs     = flag.NewFlagSet("gotrace filter", flag.ContinueOnError)
+		from   = fs.Uint64("from", 0, "lowest `cycle`")
+		to     = fs.Uint64("to", 1<<64-1, "highest `cycle`")
+		addrs  = fs.String("addr", "", "`address` or range of addresses low-high")
+		op     = fs.String("op", "", "`mnemonic`")
+		reg    = fs.String("reg", "", "`register` that changes")
+		binary = fs.Bool("binary", false, "write the binary format")
+		out    = fs.String("o", "", "output `file` (default standard output)")
+	)
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("exactly one trace is required")
+	}
+
+	var lo, hi uint64 = 0, 1<<32 - 1
+	if *addrs != "" {
+		var (
+			parts = strings.SplitN(*addrs, "-", 2)
+			err   error
+		)
+
+		if lo, err = strconv.ParseUint(parts[0], 0, 32); err != nil {
+			return fmt.Errorf("invalid -addr %s", *addrs)
+		}
+
+		hi = lo
+		if len(parts) == 2 {
+			if hi, err = strconv.ParseUint(parts[1], 0, 32); err != nil {
+				return fmt.Errorf("invalid -addr %s", *addrs)
+			}
+		}
+	}
+
+	mnemonic := strings.ToUpper(*op)
+	match := func(r trace.Record) bool {
+		if (r.Cycle < *from) || (r.Cycle > *to) || (uint64(r.Addr()) < lo) || (uint64(r.Addr()) > hi) {
+			return false
+		}
+
+		if (mnemonic != "") && (strings.Fields(r.Text + " ")[0] != mnemonic) {
+			return false
+		}
+
+		if *reg == "" {
+			return true
+		}
+
+		for _, c := range r.Registers {
+			if strings.EqualFold(c.Name, *reg) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	r, rcloser, err := reader(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer rcloser()
+
+	o, closer, err := output(*out)
+	if err != nil {
+		return err
+	}
+	defer closer()
+
+	w, err := writer(o, *binary)
+	if err != nil {
+		return err
+	}
+
+	for {
+		rec, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if match(rec) {
+			if err := w.Write(rec); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	return closer()
+}
+
+// diffTraces writes the first records at which two traces diverge
+func diffTraces(args []string) error {
+	fs := flag.NewFlagSet("gotrace diff", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return fmt.Errorf("exactly two traces are required")
+	}
+
+	a, acloser, err := reader(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer acloser()
+
+	b, bcloser, err := reader(fs.Arg(1))
+	if err != nil {
+		return err
+	}
+	defer bcloser()
+
+	d, err := trace.Diff(a, b)
+	if (err != nil) || (d == nil) {
+		return err
+	}
+
+	fmt.Printf("traces diverge after %d records\n", d.Index)
+	for _, side := range []struct {
+		prefix string
+		rec    *trace.Record
+	}{
+		{"<", d.A},
+		{">", d.B},
+	} {
+		if side.rec == nil {
+			fmt.Println(side.prefix, "end")
+			continue
+		}
+
+		var buf bytes.Buffer
+		w := trace.NewJSONWriter(&buf)
+		if err := w.Write(*side.rec); err != nil {
+			return err
+		}
+
+		w.Flush()
+		fmt.Print(side.prefix, " ", buf.String())
+	}
+
+	return errDiverge
+}
+
+// run runs the subcommand named by args[0], and returns any error
+func run(args []string) error {
+	commands := map[string]func([]string) error{
+		"run":    runTrace,
+		"filter": filterTrace,
+		"diff":   diffTraces,
+	}
+
+	if len(args) > 0 {
+		if cmd, ok := commands[args[0]]; ok {
+			return cmd(args[1:])
+		}
+	}
+
+	return fmt.Errorf("usage: gotrace run|filter|diff [flags] file ...")
+}
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		if err != errDiverge {
+			fmt.Fprintln(os.Stderr, err)
+		}
+
+		os.Exit(1)
+	}
+}
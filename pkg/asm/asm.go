@@ -0,0 +1,986 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package asm
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/bantling/goprocessor/pkg/isa"
+	"github.com/bantling/goprocessor/pkg/obj"
+	"github.com/bantling/goprocessor/pkg/register"
+)
+
+const (
+	// longBranchBytes is the length of an inverted branch over a JMP S16
+	longBranchBytes = 5
+
+	// longJumpBytes is the length of a JMA U32 or JSA U32
+	longJumpBytes = 5
+)
+
+// Symbol is a label or constant defined by the source
+type Symbol struct {
+	Name  string
+	Value int64
+	Label bool // true for a label, false for a .equ constant
+	Line  int
+}
+
+// Program is the result of assembling source
+type Program struct {
+	// Origin is the address of the first byte of Code
+	Origin uint32
+
+	// Code is the assembled bytes
+	Code []byte
+
+	// Symbols are sorted by value and name
+	Symbols []Symbol
+}
+
+// aliases maps alternate mnemonics to the mnemonics of the instruction set
+var aliases = map[string]string{
+	"LD":    "MOV",
+	"RD":    "MOV",
+	"WD":    "MOV",
+	"BLTU":  "BCC",
+	"BNANC": "BCC",
+	"BGEU":  "BCS",
+	"BNANS": "BCS",
+	"BLTS":  "BVC",
+	"BDZC":  "BVC",
+	"BGES":  "BVS",
+	"BDZS":  "BVS",
+}
+
+// inverse maps each branch to the branch with the opposite condition
+var inverse = map[string]string{
+	"BCC": "BCS",
+	"BCS": "BCC",
+	"BVC": "BVS",
+	"BVS": "BVC",
+	"BEQ": "BNE",
+	"BNE": "BEQ",
+	"BMI": "BPL",
+	"BPL": "BMI",
+}
+
+// longJumps maps relative jumps to the absolute jump used when the target is out of range
+var longJumps = map[string]string{
+	"JMP": "JMA",
+	"JSR": "JSA",
+}
+
+// hasLongForm is true if a relative branch or jump has a long form, which LOOP does not.
+// A LOOP target that is out of range is an error.
+func hasLongForm(mnemonic string) bool {
+	_, branch := inverse[mnemonic]
+	_, jump := longJumps[mnemonic]
+
+	return branch || jump
+}
+
+// operandSizes maps the instructions that select an operand size to the size
+var operandSizes = map[string]uint8{
+	"SOS8":  register.Operand8,
+	"SOS16": register.Operand16,
+	"SOS32": register.Operand32,
+	"SOS64": register.Operand64,
+}
+
+// mathModes maps the instructions that select a math mode to the mode
+var mathModes = map[string]uint8{
+	"SMMI": register.MathInteger,
+	"SMMR": register.MathFractional,
+	"SMMX": register.MathFixed,
+	"SMMF": register.MathFloat,
+}
+
+// sizeValues maps the values of the .size directive to an operand size
+var sizeValues = map[int64]uint8{
+	8:  register.Operand8,
+	16: register.Operand16,
+	32: register.Operand32,
+	64: register.Operand64,
+}
+
+// sectionDirectives maps the directives that select a section to the section
+var sectionDirectives = map[string]obj.Section{
+	".code": obj.SectionCode,
+	".data": obj.SectionData,
+	".bss":  obj.SectionBSS,
+}
+
+// byMnemonic indexes opcodes by mnemonic
+var byMnemonic = func() map[string][]isa.Opcode {
+	m := map[string][]isa.Opcode{}
+	for _, op := range isa.Opcodes() {
+		m[op.Mnemonic] = append(m[op.Mnemonic], op)
+	}
+
+	return m
+}()
+
+// item is a statement and its layout
+type item struct {
+	statement
+	section obj.Section
+	addr    int64
+	size    uint8 // the effective operand size when the statement executes
+	op      isa.Opcode
+	long    bool // true if a relative branch or jump needs the long form
+	bytes   int
+}
+
+// position is a line and column of the source
+type position struct {
+	line int
+	col  int
+}
+
+// assembler holds the state of assembling one source
+type assembler struct {
+	file        string
+	relocatable bool // true if assembling an object file
+	items       []*item
+	symbols     map[string]*Symbol
+	errs        ErrorList
+
+	section  obj.Section            // the section being laid out
+	sections map[string]obj.Section // the section of each label, or SectionUndefined for an external symbol
+	globals  map[string]position    // the symbols declared global, and where
+	code     [obj.SectionBSS + 1][]byte
+	relocs   []obj.Reloc
+	lines    []obj.Line
+}
+
+// Assemble assembles source read from src, where file is the name used in error messages.
+// If there are any errors, the error is an ErrorList.
+func Assemble(file string, src io.Reader) (*Program, error) {
+	return (&assembler{file: file}).assemble(src)
+}
+
+// AssembleObject assembles source read from src into an object file, where file is the name used in error messages
+// and the Source of the object file.
+// If there are any errors, the error is an ErrorList.
+func AssembleObject(file string, src io.Reader) (*obj.File, error) {
+	a := &assembler{file: file, relocatable: true}
+	if _, err := a.assemble(src); err != nil {
+		return nil, err
+	}
+
+	return a.objectFile(), nil
+}
+
+// assemble assembles source read from src
+func (a *assembler) assemble(src io.Reader) (*Program, error) {
+	scanner := bufio.NewScanner(src)
+	for n := 1; scanner.Scan(); n++ {
+		stmt, err := parseLine(scanner.Text(), n)
+		if err != nil {
+			a.addError(n, err)
+			continue
+		}
+
+		a.items = append(a.items, &item{statement: stmt})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	a.match()
+	a.layout()
+	prog := a.emit()
+	a.checkGlobals()
+
+	if len(a.errs) > 0 {
+		sort.SliceStable(a.errs, func(i, j int) bool {
+			return (a.errs[i].Line < a.errs[j].Line) ||
+				((a.errs[i].Line == a.errs[j].Line) && (a.errs[i].Column < a.errs[j].Column))
+		})
+
+		return nil, a.errs
+	}
+
+	return prog, nil
+}
+
+// addError adds an error that occurred on a line.
+// If err is not an *Error, it is reported at column 1.
+func (a *assembler) addError(line int, err error) {
+	e, ok := err.(*Error)
+	if !ok {
+		e = &Error{Column: 1, Msg: err.Error()}
+	}
+
+	e.File, e.Line = a.file, line
+	a.errs = append(a.errs, e)
+}
+
+// errorf adds an error at a line and column
+func (a *assembler) errorf(line, col int, format string, args ...interface{}) {
+	a.addError(line, &Error{Column: col, Msg: fmt.Sprintf(format, args...)})
+}
+
+// lookup returns the value of a symbol
+func (a *assembler) lookup(name string) (int64, bool) {
+	if sym, ok := a.symbols[name]; ok {
+		return sym.Value, true
+	}
+
+	return 0, false
+}
+
+// base returns the base of a symbol, which is the name of the section of a label, the name of an external symbol,
+// or "" for a constant
+func (a *assembler) base(name string) string {
+	section, ok := a.sections[name]
+	switch {
+	case !ok:
+		return ""
+	case section == obj.SectionUndefined:
+		return name
+	}
+
+	return section.String()
+}
+
+// relocation returns the base of an argument when assembling an object file, or "" if it is absolute
+func (a *assembler) relocation(it *item, ar arg) (string, error) {
+	if !a.relocatable || (ar.x == nil) {
+		return "", nil
+	}
+
+	return ar.x.base(a.base, it.section.String())
+}
+
+// setOf returns the register set of an operand, or -1 if it does not belong to a set
+func setOf(o isa.Operand) int {
+	switch o {
+	case isa.OperandR0, isa.OperandR0c, isa.OperandOFS0, isa.OperandIX0, isa.OperandPTR0, isa.OperandDP0,
+		isa.OperandCTR0, isa.OperandCS0, isa.OperandIS0, isa.OperandIndirectPTR0:
+		return 0
+	case isa.OperandR1, isa.OperandR1c, isa.OperandOFS1, isa.OperandIX1, isa.OperandPTR1, isa.OperandDP1,
+		isa.OperandCTR1, isa.OperandCS1, isa.OperandIS1, isa.OperandIndirectPTR1:
+		return 1
+	}
+
+	return -1
+}
+
+// matches is true if an argument can be encoded as an operand kind
+func matches(ar arg, o isa.Operand) bool {
+	switch ar.kind {
+	case argRegister, argIndirect:
+		return ar.reg == o
+	case argStack:
+		return o == isa.OperandStack
+	case argMemory:
+		return o == isa.OperandMemory
+	case argImmediate:
+		return o.IsImmediate() && (o != isa.OperandStack) && (o != isa.OperandMemory)
+	}
+
+	return false
+}
+
+// match chooses the opcode of each instruction
+func (a *assembler) match() {
+	for _, it := range a.items {
+		if (it.name == "") || (it.name[0] == '.') {
+			continue
+		}
+
+		mnemonic := it.name
+		if alias, ok := aliases[mnemonic]; ok {
+			mnemonic = alias
+		}
+
+		candidates, ok := byMnemonic[mnemonic]
+		if !ok {
+			a.errorf(it.line, it.col, "unknown mnemonic %s", it.name)
+			continue
+		}
+
+		// Registers without a set take the set of the other operands
+		set := 0
+		for _, ar := range it.args {
+			if ((ar.kind == argRegister) || (ar.kind == argIndirect)) && !ar.setless && (setOf(ar.reg) >= 0) {
+				set = setOf(ar.reg)
+			}
+		}
+
+		for i := range it.args {
+			if it.args[i].setless {
+				it.args[i].reg += isa.Operand(set)
+			}
+		}
+
+	candidate:
+		for _, op := range candidates {
+			if len(op.Operands) != len(it.args) {
+				continue
+			}
+
+			for i, o := range op.Operands {
+				if !matches(it.args[i], o) {
+					continue candidate
+				}
+			}
+
+			it.op = op
+			break
+		}
+
+		if !it.op.IsValid() {
+			a.errorf(it.line, it.col, "invalid operands for %s", it.name)
+		}
+	}
+}
+
+// layout assigns an address to every statement and defines symbols.
+// The layout is repeated until every relative branch and jump that needs a long form has one.
+func (a *assembler) layout() {
+	errs := a.errs
+	for {
+		a.errs = append(ErrorList(nil), errs...)
+		a.symbols = map[string]*Symbol{}
+		a.layoutOnce()
+
+		changed := false
+		for _, it := range a.items {
+			if !it.op.IsValid() || !it.op.IsRelative() || !hasLongForm(it.op.Mnemonic) || it.long {
+				continue
+			}
+
+			// A jump to another section or an external symbol is absolute. A jump out of range is only absolute in an
+			// object file, where the linker relocates the target, since a flat program does not know its CP.
+			_, ok := a.offset(it, it.addr+int64(it.bytes))
+			_, jump := longJumps[it.op.Mnemonic]
+			if (!ok && (!jump || a.relocatable)) || (jump && !a.inSection(it)) {
+				it.long = true
+				changed = true
+			}
+		}
+
+		if !changed {
+			return
+		}
+	}
+}
+
+// layoutOnce assigns an address to every statement and defines symbols.
+// Each section has its own addresses, which start at 0 when assembling an object file.
+func (a *assembler) layoutOnce() {
+	var (
+		addrs [obj.SectionBSS + 1]int64
+		size  = register.Operand8
+		mode  = register.MathInteger
+	)
+
+	a.section = obj.SectionCode
+	a.sections = map[string]obj.Section{}
+	a.globals = map[string]position{}
+
+	for _, it := range a.items {
+		it.section, it.addr, it.size, it.bytes = a.section, addrs[a.section], register.EffectiveOperandSize(size, mode), 0
+
+		if it.label != "" {
+			a.define(it.line, it.labelCol, it.label, it.addr, true)
+		}
+
+		switch {
+		case it.name == "":
+
+		case it.name[0] == '.':
+			a.directive(it, &addrs[it.section], &size)
+
+		case it.op.IsValid():
+			it.bytes = it.op.Bytes(it.size)
+			if it.long {
+				it.bytes = longBranchBytes
+				if _, ok := longJumps[it.op.Mnemonic]; ok {
+					it.bytes = longJumpBytes
+				}
+			}
+
+			if s, ok := operandSizes[it.op.Mnemonic]; ok {
+				size = s
+			}
+
+			if m, ok := mathModes[it.op.Mnemonic]; ok {
+				mode = m
+			}
+		}
+
+		addrs[it.section] += int64(it.bytes)
+	}
+}
+
+// define defines a symbol, and returns true if it was not already defined.
+// A label is in the section being laid out.
+func (a *assembler) define(line, col int, name string, val int64, label bool) bool {
+	if _, isReg := registers[strings.ToUpper(name)]; isReg {
+		a.errorf(line, col, "%s is a register name", name)
+		return false
+	}
+
+	if sym, ok := a.symbols[name]; ok {
+		a.errorf(line, col, "%s is already defined on line %d", name, sym.Line)
+		return false
+	}
+
+	a.symbols[name] = &Symbol{Name: name, Value: val, Label: label, Line: line}
+	if label {
+		a.sections[name] = a.section
+	}
+
+	return true
+}
+
+// argCount adds an error if a directive does not have between min and max arguments, where max < 0 means no maximum
+func (a *assembler) argCount(it *item, min, max int) bool {
+	if (len(it.args) < min) || ((max >= 0) && (len(it.args) > max)) {
+		a.errorf(it.line, it.col, "wrong number of operands for %s", it.name)
+		return false
+	}
+
+	return true
+}
+
+// immediateArg evaluates an immediate argument during layout, which must only refer to symbols already defined
+func (a *assembler) immediateArg(it *item, ar arg) (int64, bool) {
+	if ar.kind != argImmediate {
+		a.errorf(it.line, ar.col, "%s requires an expression", it.name)
+		return 0, false
+	}
+
+	val, err := ar.x.eval(a.lookup, it.addr)
+	if err != nil {
+		a.addError(it.line, err)
+		return 0, false
+	}
+
+	if !a.absolute(it, ar) {
+		return 0, false
+	}
+
+	return val, true
+}
+
+// absolute adds an error and returns false if an argument is an address that must be relocated
+func (a *assembler) absolute(it *item, ar arg) bool {
+	base, err := a.relocation(it, ar)
+	if err != nil {
+		a.addError(it.line, err)
+		return false
+	}
+
+	if base != "" {
+		a.errorf(it.line, ar.x.column(), "%s requires a value that is not relative to %s", it.name, base)
+		return false
+	}
+
+	return true
+}
+
+// symbolName returns the name of a symbol argument, and true if the argument is a symbol other than $
+func symbolName(ar arg) (string, bool) {
+	if sym, isSym := ar.x.(symbol); (ar.kind == argImmediate) && isSym && (sym.name != "$") {
+		return sym.name, true
+	}
+
+	return "", false
+}
+
+// objectOnly adds an error and returns false if a directive is not assembling an object file
+func (a *assembler) objectOnly(it *item) bool {
+	if !a.relocatable {
+		a.errorf(it.line, it.col, "%s requires an object file", it.name)
+	}
+
+	return a.relocatable
+}
+
+// dataWidths is the number of bytes of each value of the data directives
+var dataWidths = map[string]int{
+	".u8":  1,
+	".u16": 2,
+	".u32": 4,
+	".u64": 8,
+}
+
+// directive lays out a directive
+func (a *assembler) directive(it *item, addr *int64, size *uint8) {
+	switch it.name {
+	case ".org":
+		if a.argCount(it, 1, 1) {
+			if val, ok := a.immediateArg(it, it.args[0]); ok {
+				*addr = val
+			}
+		}
+
+	case ".equ":
+		if a.argCount(it, 2, 2) {
+			if name, ok := symbolName(it.args[0]); !ok {
+				a.errorf(it.line, it.args[0].col, ".equ requires a name")
+			} else if val, ok := a.immediateArg(it, it.args[1]); ok {
+				a.define(it.line, it.args[0].col, name, val, false)
+			}
+		}
+
+	case ".code", ".data", ".bss":
+		if a.objectOnly(it) && a.argCount(it, 0, 0) {
+			a.section = sectionDirectives[it.name]
+		}
+
+	case ".global", ".extern":
+		if a.objectOnly(it) && a.argCount(it, 1, -1) {
+			for _, ar := range it.args {
+				name, ok := symbolName(ar)
+				switch {
+				case !ok:
+					a.errorf(it.line, ar.col, "%s requires a name", it.name)
+				case it.name == ".global":
+					a.globals[name] = position{line: it.line, col: ar.col}
+				case a.define(it.line, ar.col, name, 0, false):
+					a.sections[name] = obj.SectionUndefined
+				}
+			}
+		}
+
+	case ".space":
+		if a.argCount(it, 1, 1) {
+			if val, ok := a.immediateArg(it, it.args[0]); ok {
+				if val < 0 {
+					a.errorf(it.line, it.args[0].col, ".space must not be negative")
+				} else {
+					it.bytes = int(val)
+				}
+			}
+		}
+
+	case ".size":
+		if a.argCount(it, 1, 1) {
+			if val, ok := a.immediateArg(it, it.args[0]); ok {
+				if s, ok := sizeValues[val]; ok {
+					*size = s
+				} else {
+					a.errorf(it.line, it.args[0].col, ".size must be 8, 16, 32, or 64")
+				}
+			}
+		}
+
+	case ".u8", ".u16", ".u32", ".u64":
+		if a.argCount(it, 1, -1) {
+			it.bytes = len(it.args) * dataWidths[it.name]
+		}
+
+	case ".ascii":
+		if a.argCount(it, 1, -1) {
+			for _, ar := range it.args {
+				if ar.kind != argString {
+					a.errorf(it.line, ar.col, ".ascii requires a string")
+				}
+
+				it.bytes += len(ar.str)
+			}
+		}
+
+	default:
+		a.errorf(it.line, it.col, "unknown directive %s", it.name)
+	}
+}
+
+// target evaluates the target of a relative branch or jump
+func (a *assembler) target(it *item) (int64, error) {
+	for _, ar := range it.args {
+		if ar.kind == argImmediate {
+			return ar.x.eval(a.lookup, it.addr)
+		}
+	}
+
+	return 0, nil
+}
+
+// targetBase returns the base of the target of a relative branch or jump
+func (a *assembler) targetBase(it *item) (string, error) {
+	for _, ar := range it.args {
+		if ar.kind == argImmediate {
+			return a.relocation(it, ar)
+		}
+	}
+
+	return "", nil
+}
+
+// inSection is true if the target of a relative branch or jump is in the same section when assembling an object
+// file. An invalid target is considered in the section, the error is reported when the instruction is emitted.
+func (a *assembler) inSection(it *item) bool {
+	base, err := a.targetBase(it)
+	return !a.relocatable || (err != nil) || (base == it.section.String())
+}
+
+// targetInSection adds an error and returns false if the target of a relative branch or jump is not in the section
+// of the instruction when assembling an object file
+func (a *assembler) targetInSection(it *item, ar arg) bool {
+	base, err := a.relocation(it, ar)
+	if err != nil {
+		a.addError(it.line, err)
+		return false
+	}
+
+	if a.relocatable && (base != it.section.String()) {
+		a.errorf(it.line, ar.col, "branch target must be in %s", it.section)
+		return false
+	}
+
+	return true
+}
+
+// offset returns the offset from next to the target of a relative branch or jump, and true if it is in range.
+// An undefined target is considered in range, the error is reported when the instruction is emitted.
+func (a *assembler) offset(it *item, next int64) (int64, bool) {
+	imm, _ := it.op.Immediate()
+	if imm != isa.OperandS8 && imm != isa.OperandS16 {
+		return 0, true
+	}
+
+	target, err := a.target(it)
+	if err != nil {
+		return 0, true
+	}
+
+	off := displacement(target, next)
+	return off, fits(off, imm, it.size)
+}
+
+// displacement returns target - next, where addresses wrap around at 32 bits
+func displacement(target, next int64) int64 {
+	return int64(int32(uint32(target - next)))
+}
+
+// fits is true if a value can be encoded as an immediate operand.
+// Unsigned and current operand size immediates accept signed values, as the bits are the same.
+func fits(val int64, o isa.Operand, size uint8) bool {
+	bits := uint(o.Bytes(size) * 8)
+	if bits == 64 {
+		return true
+	}
+
+	if o.IsSigned() {
+		return (val >= -(1 << (bits - 1))) && (val < (1 << (bits - 1)))
+	}
+
+	return (val >= -(1 << (bits - 1))) && (val < (1 << bits))
+}
+
+// appendValue appends the lowest width bytes of a value, highest byte first
+func appendValue(code []byte, val int64, width int) []byte {
+	for i := width - 1; i >= 0; i-- {
+		code = append(code, byte(val>>(uint(i)*8)))
+	}
+
+	return code
+}
+
+// emit generates the code of every statement into the code of its section, and returns a Program of the code
+// section. Each section begins at the address of its first statement, or at 0 when assembling an object file.
+func (a *assembler) emit() *Program {
+	var (
+		origins [obj.SectionBSS + 1]uint32
+		started = [obj.SectionBSS + 1]bool{a.relocatable, a.relocatable, a.relocatable}
+	)
+
+	for _, it := range a.items {
+		if it.bytes == 0 {
+			continue
+		}
+
+		s := it.section
+		if !started[s] {
+			origins[s], started[s] = uint32(it.addr), true
+		}
+
+		end := int64(origins[s]) + int64(len(a.code[s]))
+		if it.addr < end {
+			a.errorf(it.line, it.col, "address %08X overlaps previous code", it.addr)
+			continue
+		}
+
+		// Fill any gap left by .org
+		code := append(a.code[s], make([]byte, it.addr-end)...)
+		start := len(code)
+
+		switch {
+		case (s == obj.SectionBSS) && (it.name != ".space"):
+			a.errorf(it.line, it.col, "%s is not allowed in %s", it.name, s)
+		case it.name[0] == '.':
+			code = a.emitData(code, it)
+		default:
+			code = a.emitInstruction(code, it)
+		}
+
+		// Keep the layout if an error left the statement short
+		a.code[s] = append(code, make([]byte, start+it.bytes-len(code))...)
+
+		if a.relocatable && (s != obj.SectionBSS) {
+			a.lines = append(a.lines, obj.Line{Section: s, Offset: uint32(it.addr), Line: uint32(it.line)})
+		}
+	}
+
+	prog := &Program{Origin: origins[obj.SectionCode], Code: a.code[obj.SectionCode]}
+	for _, sym := range a.symbols {
+		prog.Symbols = append(prog.Symbols, *sym)
+	}
+
+	sort.Slice(prog.Symbols, func(i, j int) bool {
+		return (prog.Symbols[i].Value < prog.Symbols[j].Value) ||
+			((prog.Symbols[i].Value == prog.Symbols[j].Value) && (prog.Symbols[i].Name < prog.Symbols[j].Name))
+	})
+
+	return prog
+}
+
+// checkGlobals adds an error for each global symbol that is not defined by the source
+func (a *assembler) checkGlobals() {
+	for name, pos := range a.globals {
+		if _, ok := a.symbols[name]; !ok || (a.sections[name] == obj.SectionUndefined) {
+			a.errorf(pos.line, pos.col, "global symbol %s is not defined", name)
+		}
+	}
+}
+
+// objectFile returns the object file of the assembled sections
+func (a *assembler) objectFile() *obj.File {
+	f := &obj.File{
+		Source:  a.file,
+		Code:    a.code[obj.SectionCode],
+		Data:    a.code[obj.SectionData],
+		BSSSize: uint32(len(a.code[obj.SectionBSS])),
+		Relocs:  a.relocs,
+		Lines:   a.lines,
+	}
+
+	for _, sym := range a.symbols {
+		section, ok := a.sections[sym.Name]
+		if !ok {
+			section = obj.SectionAbsolute
+		}
+
+		_, global := a.globals[sym.Name]
+		f.Symbols = append(f.Symbols, obj.Symbol{Name: sym.Name, Section: section, Value: sym.Value, Global: global})
+	}
+
+	sort.Slice(f.Symbols, func(i, j int) bool {
+		si, sj := f.Symbols[i], f.Symbols[j]
+		return (si.Section < sj.Section) ||
+			((si.Section == sj.Section) && ((si.Value < sj.Value) || ((si.Value == sj.Value) && (si.Name < sj.Name))))
+	})
+
+	return f
+}
+
+// emitData appends the bytes of a data directive.
+// The zero bytes of .space are appended by emit.
+func (a *assembler) emitData(code []byte, it *item) []byte {
+	if it.name == ".space" {
+		return code
+	}
+
+	if it.name == ".ascii" {
+		for _, ar := range it.args {
+			code = append(code, ar.str...)
+		}
+
+		return code
+	}
+
+	var (
+		width = dataWidths[it.name]
+		kind  = isa.OperandO
+		size  = uint8(0)
+	)
+
+	for width > 1<<size {
+		size++
+	}
+
+	for _, ar := range it.args {
+		val, ok := a.field(code, it, ar, kind, size, obj.RelocAbsolute)
+		if !ok && (ar.kind != argImmediate) {
+			a.errorf(it.line, ar.col, "%s requires an expression", it.name)
+		}
+
+		code = appendValue(code, val, width)
+	}
+
+	return code
+}
+
+// value evaluates an argument and checks it fits in an operand kind
+func (a *assembler) value(it *item, ar arg, o isa.Operand, size uint8) (int64, bool) {
+	if ar.x == nil {
+		return 0, false
+	}
+
+	val, err := ar.x.eval(a.lookup, it.addr)
+	if err != nil {
+		a.addError(it.line, err)
+		return 0, false
+	}
+
+	if !fits(val, o, size) {
+		a.errorf(it.line, ar.x.column(), "value %d out of range for %s", val, o)
+		return 0, false
+	}
+
+	if !a.absolute(it, ar) {
+		return 0, false
+	}
+
+	return val, true
+}
+
+// field evaluates an argument that is a field of an instruction or data directive, which is appended to code next.
+// When assembling an object file, a value that is relative to a section or external symbol is relocated by kind:
+// the field is 0, and the value is the addend of a relocation.
+func (a *assembler) field(code []byte, it *item, ar arg, o isa.Operand, size uint8, kind obj.RelocKind) (int64, bool) {
+	base, err := a.relocation(it, ar)
+	if err != nil {
+		a.addError(it.line, err)
+		return 0, false
+	}
+
+	if base == "" {
+		return a.value(it, ar, o, size)
+	}
+
+	val, err := ar.x.eval(a.lookup, it.addr)
+	if err != nil {
+		a.addError(it.line, err)
+		return 0, false
+	}
+
+	a.relocs = append(a.relocs, obj.Reloc{
+		Section: it.section,
+		Offset:  uint32(len(code)),
+		Size:    uint8(o.Bytes(size)),
+		Kind:    kind,
+		Symbol:  base,
+		Addend:  val,
+	})
+
+	return 0, true
+}
+
+// relocKind returns how an immediate operand of an instruction that is an address is relocated.
+// The targets of JMA and JSA and the data pointers are absolute, every other address is relative.
+func relocKind(op isa.Opcode) obj.RelocKind {
+	switch {
+	case (op.Mnemonic == "JMA") || (op.Mnemonic == "JSA"):
+		return obj.RelocAbsolute
+	case (op.Operands[0] == isa.OperandDP0) || (op.Operands[0] == isa.OperandDP1):
+		return obj.RelocAbsolute
+	}
+
+	return obj.RelocRelative
+}
+
+// emitInstruction appends the bytes of an instruction
+func (a *assembler) emitInstruction(code []byte, it *item) []byte {
+	if !it.op.IsValid() {
+		return code
+	}
+
+	if it.long {
+		return a.emitLong(code, it)
+	}
+
+	code = appendOpcode(code, it.op)
+	for i, o := range it.op.Operands {
+		if !o.IsImmediate() {
+			continue
+		}
+
+		var (
+			ar  = it.args[i]
+			val int64
+			ok  bool
+		)
+
+		if it.op.IsRelative() {
+			if _, err := a.target(it); err != nil {
+				a.addError(it.line, err)
+			} else if !a.targetInSection(it, ar) {
+				// The error has been added
+			} else if val, ok = a.offset(it, it.addr+int64(it.bytes)); !ok {
+				kind := "branch"
+				if _, jump := longJumps[it.op.Mnemonic]; jump {
+					kind = "jump"
+				}
+
+				a.errorf(it.line, ar.col, "%s target out of range", kind)
+			}
+		} else if o == isa.OperandStack {
+			val, ok = a.value(it, ar, isa.OperandU8, it.size)
+			if ok && (val < 0) {
+				a.errorf(it.line, ar.x.column(), "value %d out of range for U8", val)
+			}
+		} else {
+			val, _ = a.field(code, it, ar, o, it.size, relocKind(it.op))
+		}
+
+		code = appendValue(code, val, o.Bytes(it.size))
+	}
+
+	return code
+}
+
+// appendOpcode appends an opcode, preceded by EXT for page 1
+func appendOpcode(code []byte, op isa.Opcode) []byte {
+	if op.Page == isa.Page1 {
+		code = append(code, isa.EXT)
+	}
+
+	return append(code, op.Code)
+}
+
+// emitLong appends the long form of a relative branch or jump
+func (a *assembler) emitLong(code []byte, it *item) []byte {
+	target, err := a.target(it)
+	if err != nil {
+		a.addError(it.line, err)
+		return code
+	}
+
+	if abs, ok := longJumps[it.op.Mnemonic]; ok {
+		// JMP S16 -> JMA U32, JSR S16 -> JSA U32, with a relocation the linker resolves to CP + target
+		op, _ := isa.Find(abs, isa.OperandU32)
+		val, _ := a.field(appendOpcode(code, op), it, it.args[0], isa.OperandU32, it.size, obj.RelocAbsolute)
+		return appendValue(appendOpcode(code, op), val, 4)
+	}
+
+	if !a.targetInSection(it, it.args[0]) {
+		return code
+	}
+
+	// Bcc S8 -> B!cc +3, JMP S16
+	var (
+		skip, _ = isa.Find(inverse[it.op.Mnemonic], isa.OperandS8)
+		jmp, _  = isa.Find("JMP", isa.OperandS16)
+		off     = displacement(target, it.addr+longBranchBytes)
+	)
+
+	if !fits(off, isa.OperandS16, it.size) {
+		a.errorf(it.line, it.args[0].col, "branch target out of range")
+	}
+
+	code = appendValue(appendOpcode(code, skip), 3, 1)
+	return appendValue(appendOpcode(code, jmp), off, 2)
+}
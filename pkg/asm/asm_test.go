@@ -0,0 +1,402 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package asm
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bantling/goprocessor/pkg/cpu"
+	"github.com/bantling/goprocessor/pkg/memory"
+	"github.com/bantling/goprocessor/pkg/obj"
+	"github.com/stretchr/testify/assert"
+)
+
+// assemble assembles src, failing if there are any errors
+func assemble(t *testing.T, src string) *Program {
+	prog, err := Assemble("test.s", strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return prog
+}
+
+// sumArray is the doc example that sums an array of 10 16-bit ints, using instructions the processor implements
+const sumArray = `
+        LD    DP0,  0x010000    ; Initialize DP0
+        SOS16                   ; Set operand size = 16 bits
+        SDAM*[]                 ; *(PTR + IX) addressing mode
+        LD    PTR0, 0x0200      ; Load pointer with address DP + 0x0200 = 0x010200
+        LD    IX0, (10 - 1) * 2 ; IX0 = index of first byte of last 16-bit value
+        LD    R0, 0             ; R0 = 0
+SUM:    LD    R0c, *PTR         ; R0c = *(PTR0 + IX0)
+        ADD   R0,R0c            ; R0 = R0 + R0c
+        SUB   IX0, 2            ; IX0 = IX0 - operand size
+        BPL   SUM               ; LOOP again if IX0 >= 0
+`
+
+func TestAssemble(t *testing.T) {
+	prog := assemble(t, sumArray)
+	assert.Equal(t, uint32(0), prog.Origin)
+	assert.Equal(
+		t,
+		[]byte{
+			0xFF, 0x2F, 0x00, 0x01, 0x00, 0x00, // MOV DP0,U32
+			0xD1,                         // SOS16
+			0xC2,                         // SDAM*[]
+			0x6D, 0x00, 0x00, 0x02, 0x00, // MOV PTR0,U32
+			0x6F, 0x00, 0x12, // MOV IX0,U16
+			0xFF, 0x43, 0x00, 0x00, // MOV R0,O
+			0xFF, 0x47, // MOV R0c,*PTR0
+			0x02,             // ADD R0,R0c
+			0x26, 0x00, 0x02, // SUB IX0,U16
+			0x31, 0xF8, // BPL S8
+		},
+		prog.Code,
+	)
+	assert.Equal(t, []Symbol{{Name: "SUM", Value: 20, Label: true, Line: 8}}, prog.Symbols)
+
+	// Execute it
+	var (
+		ram = memory.NewRAM()
+		p   = cpu.New(ram)
+	)
+
+	p.Registers().CP = 0x00020000
+	memory.WriteBytes(ram, p.Registers().CP, prog.Code)
+	for i := uint16(0); i < 10; i++ {
+		ram.Write16(0x00010200+uint32(i)*2, i+1)
+	}
+
+	for p.Registers().PC < uint32(len(prog.Code)) {
+		if err := p.Step(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	assert.Equal(t, uint64(55), p.Registers().R0)
+}
+
+func TestAssembleOperands(t *testing.T) {
+	prog := assemble(t, `
+        .equ  FIELD, 0x10
+        .org  0x1000
+START:  MOV   R1, *PTR          ; set of R1
+        MOV   R0c, M[FIELD * 2] ; page 1 M
+        MOV   R0, *SP[8]
+        PSH   ptr               ; set 0 by default
+        SOS64
+        ADD   R0, -1            ; O is 8 bytes
+        SOS8
+        ADD   R0, 255
+        JMP   START
+        sdam*()
+`)
+	assert.Equal(t, uint32(0x1000), prog.Origin)
+	assert.Equal(
+		t,
+		[]byte{
+			0x85,                               // MOV R1,*PTR1
+			0xFF, 0x4B, 0x00, 0x00, 0x00, 0x20, // MOV R0c,M
+			0x8B, 0x08, // MOV R0,*SP[U8]
+			0xA8,                                                       // PSH PTR0
+			0xD3,                                                       // SOS64
+			0xFF, 0x00, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, // ADD R0,O
+			0xD0,             // SOS8
+			0xFF, 0x00, 0xFF, // ADD R0,O
+			0x35, 0xFF, 0xE4, // JMP S16
+			0xC1, // SDAM*()
+		},
+		prog.Code,
+	)
+	assert.Equal(
+		t,
+		[]Symbol{
+			{Name: "FIELD", Value: 0x10, Line: 2},
+			{Name: "START", Value: 0x1000, Label: true, Line: 4},
+		},
+		prog.Symbols,
+	)
+}
+
+func TestAssembleData(t *testing.T) {
+	prog := assemble(t, `
+        .u8    1, 'A', -1
+        .u16   0x1234
+        .org   8
+        .u32   $
+        .u64   -2
+        .ascii "hi\n", "!"
+        .size  32
+        MOV    R0, 0x12345678
+`)
+	assert.Equal(
+		t,
+		[]byte{
+			0x01, 0x41, 0xFF,
+			0x12, 0x34,
+			0x00, 0x00, 0x00,
+			0x00, 0x00, 0x00, 0x08,
+			0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFE,
+			'h', 'i', '\n', '!',
+			0xFF, 0x43, 0x12, 0x34, 0x56, 0x78,
+		},
+		prog.Code,
+	)
+}
+
+func TestAssembleMathMode(t *testing.T) {
+	// Math modes other than integer promote 8 bit O immediates to 16 bits
+	prog := assemble(t, `
+        SMMX
+        MOV    R0, 200
+        SMMI
+        MOV    R0, 1
+`)
+	assert.Equal(t, []byte{0xD6, 0xFF, 0x43, 0x00, 0xC8, 0xD4, 0xFF, 0x43, 0x01}, prog.Code)
+}
+
+func TestAssembleLongForms(t *testing.T) {
+	// A branch out of S8 range becomes the opposite branch over a JMP S16
+	prog := assemble(t, `
+        BEQ   FAR
+        BNE   NEAR
+NEAR:   .org  $ + 200
+FAR:    NOP
+`)
+	assert.Equal(t, []byte{0x2F, 0x03, 0x35, 0x00, 0xCA, 0x2F, 0x00}, prog.Code[:7])
+	assert.Equal(t, 208, len(prog.Code))
+	assert.Equal(t, byte(0xFE), prog.Code[207])
+
+	// A jump out of S16 range becomes an absolute jump with a relocation in an object file
+	f, err := AssembleObject("test.s", strings.NewReader(`
+        JSR   FAR
+        JMP   NEAR
+NEAR:   .space 0x8000
+FAR:    RTS
+`))
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{0x37, 0x00, 0x00, 0x00, 0x00, 0x35, 0x00, 0x00}, f.Code[:8])
+	assert.Equal(
+		t,
+		[]obj.Reloc{{Section: obj.SectionCode, Offset: 1, Size: 4, Kind: obj.RelocAbsolute, Symbol: ".code", Addend: 0x8008}},
+		f.Relocs,
+	)
+
+	// A flat program does not know its CP, so a jump out of S16 range is an error
+	_, err = Assemble("test.s", strings.NewReader(`
+        JSR   FAR
+        .org  $ + 0x8000
+FAR:    RTS
+`))
+	assert.Equal(t, ErrorList{{File: "test.s", Line: 2, Column: 15, Msg: "jump target out of range"}}, err)
+}
+
+func TestAssembleCounters(t *testing.T) {
+	prog := assemble(t, `
+        MOV   CTR1, 3
+        MOV   CS, -1            ; set 0 by default
+TOP:    STEP  IX1
+        LOOP  CTR, TOP          ; set 0 by default
+        LOOP  CTR1, TOP
+`)
+	assert.Equal(
+		t,
+		[]byte{
+			0xFF, 0x6A, 0x00, 0x00, 0x00, 0x03, // MOV CTR1,U32
+			0xFF, 0x6B, 0xFF, 0xFF, // MOV CS0,S16
+			0xFF, 0x76, // STEP IX1
+			0xFF, 0x77, 0xFB, // LOOP CTR0,S8
+			0xFF, 0x78, 0xF8, // LOOP CTR1,S8
+		},
+		prog.Code,
+	)
+
+	// LOOP has no long form
+	_, err := Assemble("test.s", strings.NewReader(`
+TOP:    .org  $ + 200
+        LOOP  CTR0, TOP
+`))
+	assert.Equal(t, ErrorList{{File: "test.s", Line: 3, Column: 21, Msg: "branch target out of range"}}, err)
+}
+
+func TestAssembleErrors(t *testing.T) {
+	_, err := Assemble("test.s", strings.NewReader(`
+FOO:    NOP
+FOO:    NOP
+        BAR   R0
+        ADC   R0, R1
+        MOV   R0, UNDEFINED
+        .org  LATER
+        BEQ   0x100000
+        SOS8
+        MOV   R0, 256
+        MOV   R0, *SP[-1]
+        .bogus
+        .size 12
+        .ascii 1
+        .org  0
+SP:     NOP
+        MOV   R0, (1 + 
+LATER:
+`))
+	assert.Equal(
+		t,
+		ErrorList{
+			{File: "test.s", Line: 3, Column: 1, Msg: "FOO is already defined on line 2"},
+			{File: "test.s", Line: 4, Column: 9, Msg: "unknown mnemonic BAR"},
+			{File: "test.s", Line: 5, Column: 9, Msg: "invalid operands for ADC"},
+			{File: "test.s", Line: 6, Column: 19, Msg: "undefined symbol UNDEFINED"},
+			{File: "test.s", Line: 7, Column: 15, Msg: "undefined symbol LATER"},
+			{File: "test.s", Line: 8, Column: 15, Msg: "branch target out of range"},
+			{File: "test.s", Line: 10, Column: 19, Msg: "value 256 out of range for O"},
+			{File: "test.s", Line: 11, Column: 23, Msg: "value -1 out of range for U8"},
+			{File: "test.s", Line: 12, Column: 9, Msg: "unknown directive .bogus"},
+			{File: "test.s", Line: 13, Column: 15, Msg: ".size must be 8, 16, 32, or 64"},
+			{File: "test.s", Line: 14, Column: 16, Msg: ".ascii requires a string"},
+			{File: "test.s", Line: 16, Column: 1, Msg: "SP is a register name"},
+			{File: "test.s", Line: 16, Column: 9, Msg: "address 00000000 overlaps previous code"},
+			{File: "test.s", Line: 17, Column: 23, Msg: "missing operand"},
+		},
+		err,
+	)
+	assert.Equal(
+		t,
+		"test.s:3:1: FOO is already defined on line 2\ntest.s:4:9: unknown mnemonic BAR",
+		err.(ErrorList)[:2].Error(),
+	)
+}
+
+// linkable is a module that refers to an external routine, and to its own code, data, and bss
+const linkable = `
+        .extern print
+        .global main, count
+        .equ    N, 2
+main:   LD      DP0, count
+        LD      PTR0, buf + N
+        LD      R0, M[count]
+        JSR     print
+        JSR     done
+        BEQ     main
+done:   RTS
+        .data
+count:  .u16    5
+table:  .u32    main, count + N
+        .bss
+buf:    .space  16
+end:
+`
+
+func TestAssembleObject(t *testing.T) {
+	f, err := AssembleObject("test.s", strings.NewReader(linkable))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "test.s", f.Source)
+	assert.Equal(
+		t,
+		[]byte{
+			0xFF, 0x2F, 0x00, 0x00, 0x00, 0x00, // MOV DP0,U32
+			0x6D, 0x00, 0x00, 0x00, 0x00, // MOV PTR0,U32
+			0x8F, 0x00, 0x00, 0x00, 0x00, // MOV R0,M
+			0x37, 0x00, 0x00, 0x00, 0x00, // JSA U32, the long form of JSR to an external symbol
+			0x39, 0x00, 0x02, // JSR S16
+			0x2E, 0xE6, // BEQ S8
+			0x3A, // RTS
+		},
+		f.Code,
+	)
+	assert.Equal(t, []byte{0x00, 0x05, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, f.Data)
+	assert.Equal(t, uint32(16), f.BSSSize)
+
+	assert.Equal(
+		t,
+		[]obj.Symbol{
+			{Name: "main", Section: obj.SectionCode, Value: 0, Global: true},
+			{Name: "done", Section: obj.SectionCode, Value: 26},
+			{Name: "count", Section: obj.SectionData, Value: 0, Global: true},
+			{Name: "table", Section: obj.SectionData, Value: 2},
+			{Name: "buf", Section: obj.SectionBSS, Value: 0},
+			{Name: "end", Section: obj.SectionBSS, Value: 16},
+			{Name: "N", Section: obj.SectionAbsolute, Value: 2},
+			{Name: "print", Section: obj.SectionUndefined},
+		},
+		f.Symbols,
+	)
+
+	assert.Equal(
+		t,
+		[]obj.Reloc{
+			{Section: obj.SectionCode, Offset: 2, Size: 4, Kind: obj.RelocAbsolute, Symbol: ".data"},
+			{Section: obj.SectionCode, Offset: 7, Size: 4, Kind: obj.RelocRelative, Symbol: ".bss", Addend: 2},
+			{Section: obj.SectionCode, Offset: 12, Size: 4, Kind: obj.RelocRelative, Symbol: ".data"},
+			{Section: obj.SectionCode, Offset: 17, Size: 4, Kind: obj.RelocAbsolute, Symbol: "print"},
+			{Section: obj.SectionData, Offset: 2, Size: 4, Kind: obj.RelocAbsolute, Symbol: ".code"},
+			{Section: obj.SectionData, Offset: 6, Size: 4, Kind: obj.RelocAbsolute, Symbol: ".data", Addend: 2},
+		},
+		f.Relocs,
+	)
+
+	assert.Equal(
+		t,
+		[]obj.Line{
+			{Section: obj.SectionCode, Offset: 0, Line: 5},
+			{Section: obj.SectionCode, Offset: 6, Line: 6},
+			{Section: obj.SectionCode, Offset: 11, Line: 7},
+			{Section: obj.SectionCode, Offset: 16, Line: 8},
+			{Section: obj.SectionCode, Offset: 21, Line: 9},
+			{Section: obj.SectionCode, Offset: 24, Line: 10},
+			{Section: obj.SectionCode, Offset: 26, Line: 11},
+			{Section: obj.SectionData, Offset: 0, Line: 13},
+			{Section: obj.SectionData, Offset: 2, Line: 14},
+		},
+		f.Lines,
+	)
+}
+
+func TestAssembleObjectErrors(t *testing.T) {
+	_, err := AssembleObject("test.s", strings.NewReader(`
+        .extern EXT
+        .global MISSING, EXT
+START:  BEQ     EXT
+        LOOP    CTR0, DATA
+        MOV     R0, *SP[START]
+        MOV     R0, START * 2
+        MOV     R0, START + EXT
+        .org    START
+        .data
+DATA:   NOP
+        .bss
+        .u8     1
+`))
+	assert.Equal(
+		t,
+		ErrorList{
+			{File: "test.s", Line: 3, Column: 17, Msg: "global symbol MISSING is not defined"},
+			{File: "test.s", Line: 3, Column: 26, Msg: "global symbol EXT is not defined"},
+			{File: "test.s", Line: 4, Column: 17, Msg: "branch target must be in .code"},
+			{File: "test.s", Line: 5, Column: 23, Msg: "branch target must be in .code"},
+			{File: "test.s", Line: 6, Column: 25, Msg: "MOV requires a value that is not relative to .code"},
+			{File: "test.s", Line: 7, Column: 27, Msg: "* cannot be applied to an address relative to .code"},
+			{File: "test.s", Line: 8, Column: 27, Msg: "+ cannot be applied to addresses relative to .code and EXT"},
+			{File: "test.s", Line: 9, Column: 17, Msg: ".org requires a value that is not relative to .code"},
+			{File: "test.s", Line: 13, Column: 9, Msg: ".u8 is not allowed in .bss"},
+		},
+		err,
+	)
+
+	// Sections and external symbols require an object file
+	_, err = Assemble("test.s", strings.NewReader(`
+        .data
+        .extern EXT
+`))
+	assert.Equal(
+		t,
+		ErrorList{
+			{File: "test.s", Line: 2, Column: 9, Msg: ".data requires an object file"},
+			{File: "test.s", Line: 3, Column: 9, Msg: ".extern requires an object file"},
+		},
+		err,
+	)
+}
@@ -0,0 +1,45 @@
+// Package asm assembles source written in the mnemonic syntax of the instruction set into bytes.
+//
+// Each line is an optional label followed by a colon, an optional mnemonic or directive and operands, and an optional
+// comment that begins with a semicolon. Labels and constants are case sensitive, mnemonics and registers are not.
+//
+// Operands are registers, *PTR0, *PTR1, *SP[expr], M[expr] (an address relative to DP), or a constant expression.
+// The registers PTR, OFS, IX, DP, and *PTR may be written without a set, in which case the set is that of the other
+// operands, or set 0.
+//
+// The mnemonics LD, RD, and WD used by the examples of doc/InstructionSet.html are aliases of MOV, and the alternate
+// branch names such as BLTU and BNANC are aliases of the branch they describe.
+//
+// Expressions are integers (decimal, 0x hex, 0o octal, 0b binary, or a 'c' character), symbols, $ (the address of the
+// current statement), and the Go operators + - ~ * / % << >> & | ^ with parentheses.
+//
+// The directives are:
+//
+//	.org expr           set the address of the next statement
+//	.equ name, expr     define a constant
+//	.size 8|16|32|64    declare the operand size, as if a SOSn instruction had executed
+//	.u8 expr, ...       emit 8 bit values, and likewise for .u16, .u32, and .u64
+//	.ascii "text", ...  emit the bytes of Go quoted strings
+//	.space expr         emit expr zero bytes
+//
+// AssembleObject assembles an object file of package obj, which accepts these additional directives:
+//
+//	.code               assemble the following statements into the code section, which is the default
+//	.data               assemble the following statements into the data section
+//	.bss                assemble the following statements into the bss section, which only allows .space
+//	.global name, ...   make symbols visible to other object files
+//	.extern name, ...   declare symbols defined by other object files
+//
+// The addresses of each section start at 0. Expressions that refer to a label or external symbol produce a
+// relocation, and may only add or subtract absolute values. Relative branches and jumps must be to the same section;
+// JMP and JSR to another section or an external symbol are replaced by JMA and JSA.
+//
+// The operand size used for O operands is tracked by following SOSn instructions and .size directives in order, and
+// is promoted from 8 to 16 bits after an SMMR, SMMX, or SMMF instruction selects a math mode other than integer.
+//
+// Relative branches (S8) and jumps (S16) whose target is out of range are replaced by long forms: a branch becomes the
+// opposite branch over a JMP S16. In an object file, JMP or JSR becomes JMA or JSA with a relocation of the target,
+// which the linker resolves to the absolute address CP + target. A flat program does not know its CP, so a JMP or JSR
+// whose target is out of range is an error.
+// SPDX-License-Identifier: Apache-2.0
+package asm
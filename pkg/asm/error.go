@@ -0,0 +1,32 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package asm
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Error describes a problem with the source at a line and column, both of which start at 1
+type Error struct {
+	File   string
+	Line   int
+	Column int
+	Msg    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s:%d:%d: %s", e.File, e.Line, e.Column, e.Msg)
+}
+
+// ErrorList is every Error found in the source, in order of line and column
+type ErrorList []*Error
+
+func (e ErrorList) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+
+	return strings.Join(msgs, "\n")
+}
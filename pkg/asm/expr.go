@@ -0,0 +1,393 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package asm
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// symbolFunc returns the value of a symbol, and true if it is defined
+type symbolFunc func(name string) (int64, bool)
+
+// baseFunc returns the base of a symbol: the name of the section of a label, the name of an external symbol, or ""
+// for a constant or undefined symbol
+type baseFunc func(name string) string
+
+// expr is a constant expression that may refer to symbols
+type expr interface {
+	// column returns the column the expression starts at
+	column() int
+
+	// eval evaluates the expression, where here is the address of the current statement
+	eval(syms symbolFunc, here int64) (int64, error)
+
+	// base returns what the value of the expression is relative to when assembling an object file, where here is
+	// the base of $. The base is "" if the value is absolute. An expression can only be relative to one base,
+	// and only be added to or have an absolute value subtracted from it.
+	base(bases baseFunc, here string) (string, error)
+}
+
+// number is a literal value
+type number struct {
+	col int
+	val int64
+}
+
+func (n number) column() int {
+	return n.col
+}
+
+func (n number) eval(symbolFunc, int64) (int64, error) {
+	return n.val, nil
+}
+
+func (n number) base(baseFunc, string) (string, error) {
+	return "", nil
+}
+
+// symbol is a reference to a label or constant, or $ for the address of the current statement
+type symbol struct {
+	col  int
+	name string
+}
+
+func (s symbol) column() int {
+	return s.col
+}
+
+func (s symbol) eval(syms symbolFunc, here int64) (int64, error) {
+	if s.name == "$" {
+		return here, nil
+	}
+
+	if val, ok := syms(s.name); ok {
+		return val, nil
+	}
+
+	return 0, &Error{Column: s.col, Msg: fmt.Sprintf("undefined symbol %s", s.name)}
+}
+
+func (s symbol) base(bases baseFunc, here string) (string, error) {
+	if s.name == "$" {
+		return here, nil
+	}
+
+	return bases(s.name), nil
+}
+
+// unary is a unary operator applied to an expression
+type unary struct {
+	col int
+	op  byte
+	x   expr
+}
+
+func (u unary) column() int {
+	return u.col
+}
+
+func (u unary) eval(syms symbolFunc, here int64) (int64, error) {
+	x, err := u.x.eval(syms, here)
+	if err != nil {
+		return 0, err
+	}
+
+	switch u.op {
+	case '-':
+		return -x, nil
+	case '~':
+		return ^x, nil
+	}
+
+	return x, nil
+}
+
+func (u unary) base(bases baseFunc, here string) (string, error) {
+	x, err := u.x.base(bases, here)
+	if (err == nil) && (x != "") && (u.op != '+') {
+		err = &Error{Column: u.col, Msg: fmt.Sprintf("%c cannot be applied to an address relative to %s", u.op, x)}
+	}
+
+	return x, err
+}
+
+// binary is a binary operator applied to two expressions
+type binary struct {
+	col  int
+	op   string
+	x, y expr
+}
+
+func (b binary) column() int {
+	return b.x.column()
+}
+
+func (b binary) eval(syms symbolFunc, here int64) (int64, error) {
+	x, err := b.x.eval(syms, here)
+	if err != nil {
+		return 0, err
+	}
+
+	y, err := b.y.eval(syms, here)
+	if err != nil {
+		return 0, err
+	}
+
+	switch b.op {
+	case "+":
+		return x + y, nil
+	case "-":
+		return x - y, nil
+	case "*":
+		return x * y, nil
+	case "/", "%":
+		if y == 0 {
+			return 0, &Error{Column: b.col, Msg: "division by zero"}
+		}
+
+		if b.op == "/" {
+			return x / y, nil
+		}
+
+		return x % y, nil
+	case "&":
+		return x & y, nil
+	case "|":
+		return x | y, nil
+	case "^":
+		return x ^ y, nil
+	case "<<":
+		return x << uint64(y), nil
+	}
+
+	// >>
+	return x >> uint64(y), nil
+}
+
+func (b binary) base(bases baseFunc, here string) (string, error) {
+	x, err := b.x.base(bases, here)
+	if err != nil {
+		return "", err
+	}
+
+	y, err := b.y.base(bases, here)
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case (x == "") && (y == ""):
+		return "", nil
+	case (b.op == "+") && ((x == "") || (y == "")):
+		return x + y, nil
+	case (b.op == "-") && (y == ""):
+		return x, nil
+	case (b.op == "-") && (x == y):
+		// The difference of two addresses relative to the same base is absolute
+		return "", nil
+	case (x != "") && (y != ""):
+		return "", &Error{Column: b.col, Msg: fmt.Sprintf("%s cannot be applied to addresses relative to %s and %s", b.op, x, y)}
+	}
+
+	return "", &Error{Column: b.col, Msg: fmt.Sprintf("%s cannot be applied to an address relative to %s", b.op, x+y)}
+}
+
+// exprParser parses an expression with Go operator precedence:
+//
+//	unary:          + - ~
+//	multiplicative: * / % << >> &
+//	additive:       + - | ^
+type exprParser struct {
+	src string
+	pos int
+	col int // column of src[0]
+}
+
+// parseExpr parses all of src as an expression, where src begins at column col
+func parseExpr(src string, col int) (expr, error) {
+	p := &exprParser{src: src, col: col}
+
+	e, err := p.additive()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.skipSpace(); p.pos < len(p.src) {
+		return nil, p.errorf("unexpected %q", p.src[p.pos:])
+	}
+
+	return e, nil
+}
+
+// errorf returns an Error at the current position
+func (p *exprParser) errorf(format string, args ...interface{}) error {
+	return &Error{Column: p.col + p.pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *exprParser) skipSpace() {
+	for (p.pos < len(p.src)) && ((p.src[p.pos] == ' ') || (p.src[p.pos] == '\t')) {
+		p.pos++
+	}
+}
+
+// operator consumes and returns the first of ops at the current position, if any
+func (p *exprParser) operator(ops ...string) string {
+	p.skipSpace()
+	for _, op := range ops {
+		if strings.HasPrefix(p.src[p.pos:], op) {
+			p.pos += len(op)
+			return op
+		}
+	}
+
+	return ""
+}
+
+func (p *exprParser) additive() (expr, error) {
+	x, err := p.multiplicative()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		p.skipSpace()
+		col := p.col + p.pos
+		op := p.operator("+", "-", "|", "^")
+		if op == "" {
+			return x, nil
+		}
+
+		y, err := p.multiplicative()
+		if err != nil {
+			return nil, err
+		}
+
+		x = binary{col: col, op: op, x: x, y: y}
+	}
+}
+
+func (p *exprParser) multiplicative() (expr, error) {
+	x, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		p.skipSpace()
+		col := p.col + p.pos
+		op := p.operator("*", "/", "%", "<<", ">>", "&")
+		if op == "" {
+			return x, nil
+		}
+
+		y, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+
+		x = binary{col: col, op: op, x: x, y: y}
+	}
+}
+
+func (p *exprParser) unary() (expr, error) {
+	p.skipSpace()
+	col := p.col + p.pos
+	if op := p.operator("+", "-", "~"); op != "" {
+		x, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+
+		return unary{col: col, op: op[0], x: x}, nil
+	}
+
+	return p.primary()
+}
+
+func (p *exprParser) primary() (expr, error) {
+	p.skipSpace()
+	if p.pos >= len(p.src) {
+		return nil, p.errorf("missing operand")
+	}
+
+	var (
+		start = p.pos
+		col   = p.col + p.pos
+		c     = p.src[p.pos]
+	)
+
+	switch {
+	case c == '(':
+		p.pos++
+		x, err := p.additive()
+		if err != nil {
+			return nil, err
+		}
+
+		if p.operator(")") == "" {
+			return nil, p.errorf("missing )")
+		}
+
+		return x, nil
+
+	case c == '$':
+		p.pos++
+		return symbol{col: col, name: "$"}, nil
+
+	case c == '\'':
+		end := strings.IndexByte(p.src[p.pos+1:], '\'')
+		if end < 0 {
+			return nil, p.errorf("unterminated character")
+		}
+
+		p.pos += end + 2
+		val, _, tail, err := strconv.UnquoteChar(p.src[start+1:p.pos-1], '\'')
+		if (err != nil) || (tail != "") {
+			return nil, &Error{Column: col, Msg: fmt.Sprintf("invalid character %s", p.src[start:p.pos])}
+		}
+
+		return number{col: col, val: int64(val)}, nil
+
+	case isDigit(c):
+		for (p.pos < len(p.src)) && isIdent(p.src[p.pos]) {
+			p.pos++
+		}
+
+		val, err := parseNumber(p.src[start:p.pos])
+		if err != nil {
+			return nil, &Error{Column: col, Msg: fmt.Sprintf("invalid number %s", p.src[start:p.pos])}
+		}
+
+		return number{col: col, val: val}, nil
+
+	case isIdentStart(c):
+		for (p.pos < len(p.src)) && isIdent(p.src[p.pos]) {
+			p.pos++
+		}
+
+		return symbol{col: col, name: p.src[start:p.pos]}, nil
+	}
+
+	return nil, p.errorf("unexpected %q", c)
+}
+
+// parseNumber parses a decimal, 0x hex, 0o octal, or 0b binary number.
+// Numbers up to 64 bits are accepted, where numbers >= 2^63 wrap to negative values.
+func parseNumber(s string) (int64, error) {
+	val, err := strconv.ParseUint(s, 0, 64)
+	return int64(val), err
+}
+
+func isDigit(c byte) bool {
+	return (c >= '0') && (c <= '9')
+}
+
+func isIdentStart(c byte) bool {
+	return ((c >= 'A') && (c <= 'Z')) || ((c >= 'a') && (c <= 'z')) || (c == '_')
+}
+
+func isIdent(c byte) bool {
+	return isIdentStart(c) || isDigit(c)
+}
@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package asm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpr(t *testing.T) {
+	syms := func(name string) (int64, bool) {
+		if name == "ROW" {
+			return 40, true
+		}
+
+		return 0, false
+	}
+
+	for src, val := range map[string]int64{
+		"0":                  0,
+		"0x0200":             0x200,
+		"0b101":              5,
+		"0o17":               15,
+		"1_000":              1000,
+		"'A'":                65,
+		"'\\n'":              10,
+		"0xFFFFFFFFFFFFFFFF": -1,
+		"(10 - 1) * 2":       18,
+		"(5 - 1) * (10 * 4)": 160,
+		"9 * 4":              36,
+		"1 + 2 * 3":          7,
+		"1 << 4 | 1":         17,
+		"-ROW / 3":           -13,
+		"~0 & 0xFF":          0xFF,
+		"7 % 4 ^ 1":          2,
+		"$ + 2":              0x102,
+		"- - 1":              1,
+		"256 >> 4":           16,
+	} {
+		x, err := parseExpr(src, 1)
+		assert.Nil(t, err, src)
+
+		v, err := x.eval(syms, 0x100)
+		assert.Nil(t, err, src)
+		assert.Equal(t, val, v, src)
+	}
+
+	// Parse errors have the column they occur at
+	for src, msg := range map[string]string{
+		"":        "1:1:1: missing operand",
+		"1 +":     "1:1:4: missing operand",
+		"(1 + 2":  "1:1:7: missing )",
+		"1 2":     "1:1:3: unexpected \"2\"",
+		"0x":      "1:1:1: invalid number 0x",
+		"'ab'":    "1:1:1: invalid character 'ab'",
+		"'a":      "1:1:1: unterminated character",
+		"1 + #":   "1:1:5: unexpected '#'",
+		"12a + 1": "1:1:1: invalid number 12a",
+	} {
+		_, err := parseExpr(src, 1)
+		if assert.NotNil(t, err, src) {
+			e := err.(*Error)
+			e.Line = 1
+			e.File = "1"
+			assert.Equal(t, msg, e.Error(), src)
+		}
+	}
+
+	// Evaluation errors
+	x, _ := parseExpr("1 + FOO", 5)
+	_, err := x.eval(syms, 0)
+	assert.Equal(t, &Error{Column: 9, Msg: "undefined symbol FOO"}, err)
+
+	x, _ = parseExpr("1 / (ROW - 40)", 1)
+	_, err = x.eval(syms, 0)
+	assert.Equal(t, &Error{Column: 3, Msg: "division by zero"}, err)
+}
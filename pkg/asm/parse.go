@@ -0,0 +1,257 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package asm
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bantling/goprocessor/pkg/isa"
+)
+
+// argKind is the syntactic kind of an operand
+type argKind uint8
+
+const (
+	argRegister  argKind = iota // R0, OFS1, ST, ...
+	argIndirect                 // *PTR0, *PTR1, *PTR
+	argStack                    // *SP[expr]
+	argMemory                   // M[expr]
+	argImmediate                // expr
+	argString                   // "text", only valid for directives
+)
+
+// arg is a parsed operand
+type arg struct {
+	col     int
+	kind    argKind
+	reg     isa.Operand // argRegister and argIndirect
+	setless bool        // true if a pointer or counter register is named without a set, reg is the set 0 register
+	x       expr        // argStack, argMemory, and argImmediate
+	str     string      // argString
+}
+
+// statement is a parsed line
+type statement struct {
+	line     int
+	label    string
+	labelCol int
+	name     string // upper case mnemonic, or lower case directive beginning with a dot
+	col      int
+	args     []arg
+}
+
+// registers maps upper case register names to operands
+var registers = map[string]isa.Operand{
+	"R0":   isa.OperandR0,
+	"R0C":  isa.OperandR0c,
+	"R1":   isa.OperandR1,
+	"R1C":  isa.OperandR1c,
+	"OFS0": isa.OperandOFS0,
+	"OFS1": isa.OperandOFS1,
+	"IX0":  isa.OperandIX0,
+	"IX1":  isa.OperandIX1,
+	"PTR0": isa.OperandPTR0,
+	"PTR1": isa.OperandPTR1,
+	"DP0":  isa.OperandDP0,
+	"DP1":  isa.OperandDP1,
+	"SP":   isa.OperandSP,
+	"SB":   isa.OperandSB,
+	"CP":   isa.OperandCP,
+	"ST":   isa.OperandST,
+	"CTR0": isa.OperandCTR0,
+	"CTR1": isa.OperandCTR1,
+	"CS0":  isa.OperandCS0,
+	"CS1":  isa.OperandCS1,
+	"IS0":  isa.OperandIS0,
+	"IS1":  isa.OperandIS1,
+}
+
+// setless maps upper case names of pointer and counter registers without a set to the set 0 register.
+// The set is chosen by the other operands of the instruction.
+var setless = map[string]isa.Operand{
+	"OFS": isa.OperandOFS0,
+	"IX":  isa.OperandIX0,
+	"PTR": isa.OperandPTR0,
+	"DP":  isa.OperandDP0,
+	"CTR": isa.OperandCTR0,
+	"CS":  isa.OperandCS0,
+	"IS":  isa.OperandIS0,
+}
+
+// stripComment removes a comment that begins with a semicolon outside of quotes
+func stripComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case (c == '"') || (c == '\''):
+			quote = c
+		case c == ';':
+			return line[:i]
+		}
+	}
+
+	return line
+}
+
+// splitArgs splits text at commas that are not inside brackets or quotes.
+// Each piece is trimmed of spaces, and returned with the column it starts at.
+func splitArgs(text string, col int) ([]string, []int) {
+	var (
+		pieces []string
+		cols   []int
+		depth  int
+		quote  byte
+		start  int
+	)
+
+	add := func(end int) {
+		piece := text[start:end]
+		trimmed := strings.TrimLeft(piece, " \t")
+		pieces = append(pieces, strings.TrimRight(trimmed, " \t"))
+		cols = append(cols, col+start+len(piece)-len(trimmed))
+	}
+
+	for i := 0; i < len(text); i++ {
+		switch c := text[i]; {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case (c == '"') || (c == '\''):
+			quote = c
+		case (c == '(') || (c == '['):
+			depth++
+		case (c == ')') || (c == ']'):
+			depth--
+		case (c == ',') && (depth == 0):
+			add(i)
+			start = i + 1
+		}
+	}
+	add(len(text))
+
+	return pieces, cols
+}
+
+// parseArg parses an operand that starts at column col
+func parseArg(text string, col int) (arg, error) {
+	var (
+		upper           = strings.ToUpper(text)
+		reg, isReg      = registers[upper]
+		sreg, isSetless = setless[upper]
+		a               = arg{col: col}
+		err             error
+	)
+
+	switch {
+	case text == "":
+		return a, &Error{Column: col, Msg: "missing operand"}
+
+	case isReg:
+		a.kind, a.reg = argRegister, reg
+
+	case isSetless:
+		a.kind, a.reg, a.setless = argRegister, sreg, true
+
+	case (upper == "*PTR0") || (upper == "*PTR1") || (upper == "*PTR"):
+		a.kind, a.reg = argIndirect, isa.OperandIndirectPTR0
+		if upper == "*PTR1" {
+			a.reg = isa.OperandIndirectPTR1
+		}
+		a.setless = upper == "*PTR"
+
+	case strings.HasPrefix(upper, "*SP[") && strings.HasSuffix(upper, "]"):
+		a.kind = argStack
+		a.x, err = parseExpr(text[4:len(text)-1], col+4)
+
+	case strings.HasPrefix(upper, "M[") && strings.HasSuffix(upper, "]"):
+		a.kind = argMemory
+		a.x, err = parseExpr(text[2:len(text)-1], col+2)
+
+	case text[0] == '"':
+		a.kind = argString
+		if a.str, err = strconv.Unquote(text); err != nil {
+			err = &Error{Column: col, Msg: fmt.Sprintf("invalid string %s", text)}
+		}
+
+	default:
+		a.kind = argImmediate
+		a.x, err = parseExpr(text, col)
+	}
+
+	return a, err
+}
+
+// parseLine parses a line of source into a statement.
+// A line with no label and no mnemonic or directive returns a statement with no name.
+func parseLine(line string, n int) (statement, error) {
+	var (
+		text = stripComment(line)
+		pos  = 0
+		stmt = statement{line: n}
+	)
+
+	skipSpace := func() {
+		for (pos < len(text)) && ((text[pos] == ' ') || (text[pos] == '\t')) {
+			pos++
+		}
+	}
+
+	// Optional label
+	skipSpace()
+	if (pos < len(text)) && isIdentStart(text[pos]) {
+		end := pos
+		for (end < len(text)) && isIdent(text[end]) {
+			end++
+		}
+
+		if (end < len(text)) && (text[end] == ':') {
+			stmt.label, stmt.labelCol = text[pos:end], pos+1
+			pos = end + 1
+			skipSpace()
+		}
+	}
+
+	// Optional mnemonic or directive, which ends at the first space
+	if pos == len(text) {
+		return stmt, nil
+	}
+
+	start := pos
+	for (pos < len(text)) && (text[pos] != ' ') && (text[pos] != '\t') {
+		pos++
+	}
+
+	stmt.name, stmt.col = strings.ToUpper(text[start:pos]), start+1
+	if stmt.name[0] == '.' {
+		stmt.name = strings.ToLower(stmt.name)
+	}
+
+	// Optional operands
+	skipSpace()
+	if pos == len(text) {
+		return stmt, nil
+	}
+
+	pieces, cols := splitArgs(text[pos:], pos+1)
+	for i, piece := range pieces {
+		a, err := parseArg(piece, cols[i])
+		if err != nil {
+			return stmt, err
+		}
+
+		stmt.args = append(stmt.args, a)
+	}
+
+	return stmt, nil
+}
@@ -0,0 +1,57 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package asm
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// WriteSymbols writes a symbol table, one symbol per line: the value in hex, L for a label or C for a constant, and
+// the name
+func WriteSymbols(w io.Writer, syms []Symbol) error {
+	for _, sym := range syms {
+		kind := "C"
+		if sym.Label {
+			kind = "L"
+		}
+
+		if _, err := fmt.Fprintf(w, "%08X %s %s\n", uint64(sym.Value), kind, sym.Name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ReadSymbols reads a symbol table written by WriteSymbols.
+// The Line of each symbol is the line of the symbol table it was read from.
+func ReadSymbols(r io.Reader) ([]Symbol, error) {
+	var (
+		syms    []Symbol
+		scanner = bufio.NewScanner(r)
+	)
+
+	for n := 1; scanner.Scan(); n++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		if (len(fields) != 3) || ((fields[1] != "L") && (fields[1] != "C")) {
+			return nil, fmt.Errorf("symbol table line %d: expected value, L or C, and name", n)
+		}
+
+		val, err := strconv.ParseUint(fields[0], 16, 64)
+		if err != nil {
+			return nil, fmt.Errorf("symbol table line %d: invalid value %s", n, fields[0])
+		}
+
+		syms = append(syms, Symbol{Name: fields[2], Value: int64(val), Label: fields[1] == "L", Line: n})
+	}
+
+	return syms, scanner.Err()
+}
@@ -0,0 +1,32 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package asm
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSymbols(t *testing.T) {
+	syms := []Symbol{
+		{Name: "NEG", Value: -2, Line: 1},
+		{Name: "START", Value: 0x1000, Label: true, Line: 2},
+	}
+
+	var buf bytes.Buffer
+	assert.Nil(t, WriteSymbols(&buf, syms))
+	assert.Equal(t, "FFFFFFFFFFFFFFFE C NEG\n00001000 L START\n", buf.String())
+
+	read, err := ReadSymbols(&buf)
+	assert.Nil(t, err)
+	assert.Equal(t, syms, read)
+
+	_, err = ReadSymbols(strings.NewReader("\n00001000 X START\n"))
+	assert.EqualError(t, err, "symbol table line 2: expected value, L or C, and name")
+
+	_, err = ReadSymbols(strings.NewReader("XYZ L START\n"))
+	assert.EqualError(t, err, "symbol table line 1: invalid value XYZ")
+}
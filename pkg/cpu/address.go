@@ -0,0 +1,79 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cpu
+
+import (
+	"github.com/bantling/gofuncs"
+	"github.com/bantling/goprocessor/pkg/memory"
+	"github.com/bantling/goprocessor/pkg/register"
+)
+
+const (
+	// addressOfs is the address mode bit that adds OFS
+	addressOfs uint8 = 0x01
+
+	// addressIx is the address mode bit that adds IX
+	addressIx uint8 = 0x02
+
+	// addressPtr is the address mode bit that dereferences a 32 bit pointer
+	addressPtr uint8 = 0x04
+)
+
+// EffectiveAddressSetErr if the register set is invalid
+const EffectiveAddressSetErr = "Register set must be <= 1"
+
+// EffectiveAddress returns the address that *PTR refers to for register set 0 or 1 in the current address mode.
+//
+// Data address modes are relative to DP, code address modes are relative to CP.
+// The * address modes first read a 32 bit pointer at DP + PTR, even in code address modes, which is then relative to
+// DP or CP.
+// Using data address modes with PTR = 100, OFS = 25, IX = 400, and a pointer at DP + 100 to 200:
+//
+// Ptr         = DP + PTR             = DP + 100
+// PtrOfs      = DP + PTR + OFS       = DP + 125
+// PtrIx       = DP + PTR + IX        = DP + 500
+// PtrIxOfs    = DP + PTR + IX + OFS  = DP + 525
+// PtrPtr      = DP + *PTR            = DP + 200
+// PtrPtrOfs   = DP + *PTR + OFS      = DP + 225
+// PtrPtrIx    = DP + *PTR + IX       = DP + 600
+// PtrPtrIxOfs = DP + *PTR + IX + OFS = DP + 625
+//
+// All arithmetic wraps around the 32 bit address space.
+// Returns an error if the bus faults reading the pointer.
+func EffectiveAddress(regs *register.Registers, set uint8, bus memory.Bus) (uint32, error) {
+	gofuncs.PanicBM(set <= 1, EffectiveAddressSetErr)
+
+	var (
+		st   = regs.ST()
+		mode = st.AddressMode()
+		dp   = *regs.DP(set)
+		ptr  = *regs.PTR(set)
+		ofs  = *regs.OFS(set)
+		ix   = *regs.IX(set)
+	)
+
+	base := dp
+	if st.IsCodeAddressMode() {
+		base = regs.CP
+	}
+
+	addr := base + ptr
+	if (mode & addressPtr) == addressPtr {
+		deref, err := bus.Read32(dp + ptr)
+		if err != nil {
+			return 0, err
+		}
+
+		addr = base + deref
+	}
+
+	if (mode & addressIx) == addressIx {
+		addr += uint32(ix)
+	}
+
+	if (mode & addressOfs) == addressOfs {
+		addr += uint32(ofs)
+	}
+
+	return addr, nil
+}
@@ -0,0 +1,107 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cpu
+
+import (
+	"testing"
+
+	"github.com/bantling/goprocessor/pkg/memory"
+	"github.com/bantling/goprocessor/pkg/register"
+	"github.com/stretchr/testify/assert"
+)
+
+// faultBus is a RAM that faults reading one address
+type faultBus struct {
+	*memory.RAM
+	addr uint32
+}
+
+func (b faultBus) Read32(addr uint32) (uint32, error) {
+	if addr == b.addr {
+		return 0, &memory.Fault{Addr: addr}
+	}
+
+	return b.RAM.Read32(addr)
+}
+
+func TestEffectiveAddress(t *testing.T) {
+	var (
+		ram  = memory.NewRAM()
+		regs = register.OfRegisters()
+		st   = regs.ST()
+	)
+
+	regs.CP = 0x00020000
+	regs.DP0 = 0x00010000
+	regs.PTR0 = 100
+	regs.OFS0 = 25
+	regs.IX0 = 400
+	ram.Write32(0x00010000+100, 200)
+
+	regs.DP1 = 0x00030000
+	regs.PTR1 = 10
+	regs.OFS1 = 2
+	regs.IX1 = 4
+	ram.Write32(0x00030000+10, 20)
+
+	// Code address modes still read pointers at DP + PTR, not these at CP + PTR
+	ram.Write32(0x00020000+100, 300)
+	ram.Write32(0x00020000+10, 30)
+
+	for _, test := range []struct {
+		mode   uint8
+		code   bool
+		set    uint8
+		expect uint32
+	}{
+		{register.Ptr, false, 0, 0x00010000 + 100},
+		{register.PtrOfs, false, 0, 0x00010000 + 125},
+		{register.PtrIx, false, 0, 0x00010000 + 500},
+		{register.PtrIxOfs, false, 0, 0x00010000 + 525},
+		{register.PtrPtr, false, 0, 0x00010000 + 200},
+		{register.PtrPtrOfs, false, 0, 0x00010000 + 225},
+		{register.PtrPtrIx, false, 0, 0x00010000 + 600},
+		{register.PtrPtrIxOfs, false, 0, 0x00010000 + 625},
+		{register.Ptr, true, 0, 0x00020000 + 100},
+		{register.PtrOfs, true, 0, 0x00020000 + 125},
+		{register.PtrIx, true, 0, 0x00020000 + 500},
+		{register.PtrIxOfs, true, 0, 0x00020000 + 525},
+		{register.PtrPtr, true, 0, 0x00020000 + 200},
+		{register.PtrPtrOfs, true, 0, 0x00020000 + 225},
+		{register.PtrPtrIx, true, 0, 0x00020000 + 600},
+		{register.PtrPtrIxOfs, true, 0, 0x00020000 + 625},
+		{register.PtrIxOfs, false, 1, 0x00030000 + 16},
+		{register.PtrPtrIxOfs, false, 1, 0x00030000 + 26},
+		{register.PtrPtrIxOfs, true, 1, 0x00020000 + 26},
+	} {
+		st.SelectAddressMode(test.mode)
+		st.CodeAddressMode(test.code)
+		regs.SetST(st)
+
+		addr, err := EffectiveAddress(&regs, test.set, ram)
+		assert.Equal(t, test.expect, addr)
+		assert.Nil(t, err)
+	}
+
+	// Addresses wrap around
+	regs.DP0 = 0xFFFFFFF0
+	regs.PTR0 = 0x20
+	st.SelectDataAddressMode()
+	st.SelectAddressMode(register.PtrOfs)
+	regs.SetST(st)
+
+	addr, err := EffectiveAddress(&regs, 0, ram)
+	assert.Equal(t, uint32(0x10+25), addr)
+	assert.Nil(t, err)
+
+	// Reading the pointer faults
+	st.SelectAddressMode(register.PtrPtr)
+	regs.SetST(st)
+
+	addr, err = EffectiveAddress(&regs, 0, faultBus{ram, 0x10})
+	assert.Equal(t, uint32(0), addr)
+	assert.Equal(t, &memory.Fault{Addr: 0x10}, err)
+
+	// Invalid register set
+	assert.PanicsWithValue(t, EffectiveAddressSetErr, func() { EffectiveAddress(&regs, 2, ram) })
+}
@@ -0,0 +1,15 @@
+// Package cpu fetches, decodes, and executes instructions.
+//
+// The instruction set names the general registers of each register set R and Rc (complement).
+// They map to Registers as R0 = R0, R0c = R1, R1 = R2, R1c = R3.
+//
+// Interrupts save all registers on the stack except SB and SP, disable further interrupts, and enter a routine with
+// CP = the routine address and PC = 0. RTI pulls the saved registers to resume the interrupted code.
+// Stack overflow, stack underflow, and division by zero execute the reset/error interrupt at *FFFFFFFC with R0 = the
+// error code, when a routine is installed there; otherwise Step returns the error.
+// Devices request interrupts with Raise, and the routine of each IRQ line is at *IRQVector(line).
+//
+// Timers TMR0 thru TMR3 count down milliseconds of a Clock. When a timer reaches 0, it interrupts with the routine
+// at TPTR of the same number. The default VirtualClock derives time from the cycle count, so timers are reproducible.
+// SPDX-License-Identifier: Apache-2.0
+package cpu
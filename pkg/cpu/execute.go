@@ -0,0 +1,1370 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cpu
+
+import (
+	"math"
+
+	"github.com/bantling/goprocessor/pkg/isa"
+	"github.com/bantling/goprocessor/pkg/register"
+)
+
+// General register indexes in instruction set order
+const (
+	r0 = iota
+	r0c
+	r1
+	r1c
+)
+
+// instruction is the implementation of an opcode, given the value of the immediate operand isa.Opcode describes
+type instruction func(p *Processor, imm uint64) error
+
+// aluFunc is an operation on two general registers
+type aluFunc func(w, r *register.GeneralRegister, st *register.StatusRegister) error
+
+// unaryFunc is an operation on one general register
+type unaryFunc func(w *register.GeneralRegister, st *register.StatusRegister) error
+
+// reg16 selects a 16 bit register
+type reg16 func(r *register.Registers) *uint16
+
+// reg32 selects a 32 bit register
+type reg32 func(r *register.Registers) *uint32
+
+// Register selectors
+var (
+	regOFS0 reg16 = func(r *register.Registers) *uint16 { return &r.OFS0 }
+	regOFS1 reg16 = func(r *register.Registers) *uint16 { return &r.OFS1 }
+	regIX0  reg16 = func(r *register.Registers) *uint16 { return &r.IX0 }
+	regIX1  reg16 = func(r *register.Registers) *uint16 { return &r.IX1 }
+	regSP   reg16 = func(r *register.Registers) *uint16 { return &r.SP }
+	regCS0  reg16 = func(r *register.Registers) *uint16 { return &r.CS0 }
+	regCS1  reg16 = func(r *register.Registers) *uint16 { return &r.CS1 }
+	regIS0  reg16 = func(r *register.Registers) *uint16 { return &r.IS0 }
+	regIS1  reg16 = func(r *register.Registers) *uint16 { return &r.IS1 }
+	regCP   reg32 = func(r *register.Registers) *uint32 { return &r.CP }
+	regDP0  reg32 = func(r *register.Registers) *uint32 { return &r.DP0 }
+	regDP1  reg32 = func(r *register.Registers) *uint32 { return &r.DP1 }
+	regPTR0 reg32 = func(r *register.Registers) *uint32 { return &r.PTR0 }
+	regPTR1 reg32 = func(r *register.Registers) *uint32 { return &r.PTR1 }
+	regSB   reg32 = func(r *register.Registers) *uint32 { return &r.SB }
+	regCTR0 reg32 = func(r *register.Registers) *uint32 { return &r.CTR0 }
+	regCTR1 reg32 = func(r *register.Registers) *uint32 { return &r.CTR1 }
+)
+
+// pages contains the implementation of page 0 and page 1 opcodes.
+// A nil instruction is either reserved or not implemented yet, according to isa.Lookup.
+var pages = [2][256]instruction{
+	{
+		0x00: binary(r0, r0c, adc),                     // ADC R0,R0c
+		0x01: binary(r1, r1c, adc),                     // ADC R1,R1c
+		0x02: binary(r0, r0c, add),                     // ADD R0,R0c
+		0x03: binary(r1, r1c, add),                     // ADD R1,R1c
+		0x04: index16(regOFS0, add16),                  // ADD OFS0,U16
+		0x05: index16(regOFS1, add16),                  // ADD OFS1,U16
+		0x06: index16(regIX0, add16),                   // ADD IX0,U16
+		0x07: index16(regIX1, add16),                   // ADD IX1,U16
+		0x08: binary(r0, r0c, and),                     // AND R0,R0c
+		0x09: binary(r1, r1c, and),                     // AND R1,R1c
+		0x0A: binary(r0, r0c, cmp),                     // CMP R0,R0c
+		0x0B: binary(r1, r1c, cmp),                     // CMP R1,R1c
+		0x0C: index16(regOFS0, cmp16),                  // CMP OFS0,U16
+		0x0D: index16(regOFS1, cmp16),                  // CMP OFS1,U16
+		0x0E: index16(regIX0, cmp16),                   // CMP IX0,U16
+		0x0F: index16(regIX1, cmp16),                   // CMP IX1,U16
+		0x10: binary(r0, r0c, divs),                    // DIVS R0,R0c
+		0x11: binary(r1, r1c, divs),                    // DIVS R1,R1c
+		0x12: binary(r0, r0c, divu),                    // DIVU R0,R0c
+		0x13: binary(r1, r1c, divu),                    // DIVU R1,R1c
+		0x14: binary(r0, r0c, muls),                    // MULS R0,R0c
+		0x15: binary(r1, r1c, muls),                    // MULS R1,R1c
+		0x16: binary(r0, r0c, mulu),                    // MULU R0,R0c
+		0x17: binary(r1, r1c, mulu),                    // MULU R1,R1c
+		0x18: binary(r0, r0c, or),                      // OR R0,R0c
+		0x19: binary(r1, r1c, or),                      // OR R1,R1c
+		0x1A: binary(r0, r0c, sha),                     // SHA R0,R0c
+		0x1B: binary(r1, r1c, sha),                     // SHA R1,R1c
+		0x1C: binary(r0, r0c, shl),                     // SHL R0,R0c
+		0x1D: binary(r1, r1c, shl),                     // SHL R1,R1c
+		0x1E: binary(r0, r0c, shr),                     // SHR R0,R0c
+		0x1F: binary(r1, r1c, shr),                     // SHR R1,R1c
+		0x20: binary(r0, r0c, sbb),                     // SBB R0,R0c
+		0x21: binary(r1, r1c, sbb),                     // SBB R1,R1c
+		0x22: binary(r0, r0c, sub),                     // SUB R0,R0c
+		0x23: binary(r1, r1c, sub),                     // SUB R1,R1c
+		0x24: index16(regOFS0, sub16),                  // SUB OFS0,U16
+		0x25: index16(regOFS1, sub16),                  // SUB OFS1,U16
+		0x26: index16(regIX0, sub16),                   // SUB IX0,U16
+		0x27: index16(regIX1, sub16),                   // SUB IX1,U16
+		0x28: binary(r0, r0c, xor),                     // XOR R0,R0c
+		0x29: binary(r1, r1c, xor),                     // XOR R1,R1c
+		0x2A: branch(carryClear),                       // BCC
+		0x2B: branch(carrySet),                         // BCS
+		0x2C: branch(overflowClear),                    // BVC
+		0x2D: branch(overflowSet),                      // BVS
+		0x2E: branch(zeroSet),                          // BEQ
+		0x2F: branch(zeroClear),                        // BNE
+		0x30: branch(negativeSet),                      // BMI
+		0x31: branch(negativeClear),                    // BPL
+		0x32: jumpAbsolute(false, r0),                  // JMA R0
+		0x33: jumpAbsolute(false, -1),                  // JMA U32
+		0x34: jumpRelative(false, r0),                  // JMP R0
+		0x35: jumpRelative(false, -1),                  // JMP S16
+		0x36: jumpAbsolute(true, r0),                   // JSA R0
+		0x37: jumpAbsolute(true, -1),                   // JSA U32
+		0x38: jumpRelative(true, r0),                   // JSR R0
+		0x39: jumpRelative(true, -1),                   // JSR S16
+		0x3A: returnSubroutine(false),                  // RTS
+		0x3B: returnSubroutine(true),                   // RTS U8
+		0x3C: returnInterrupt(),                        // RTI
+		0x3D: unary(r0, fs2f),                          // FS2F R0
+		0x3E: unary(r0c, fs2f),                         // FS2F R0c
+		0x3F: unary(r1, fs2f),                          // FS2F R1
+		0x40: unary(r1c, fs2f),                         // FS2F R1c
+		0x41: unary(r0, floatUnary(math.Abs)),          // FABS R0
+		0x42: unary(r0c, floatUnary(math.Abs)),         // FABS R0c
+		0x43: unary(r1, floatUnary(math.Abs)),          // FABS R1
+		0x44: unary(r1c, floatUnary(math.Abs)),         // FABS R1c
+		0x45: binary(r0, r0c, floatBinary(math.Acos)),  // FACS R0,R0c
+		0x46: binary(r1, r1c, floatBinary(math.Acos)),  // FACS R1,R1c
+		0x47: binary(r0, r0c, floatBinary(math.Asin)),  // FASN R0,R0c
+		0x48: binary(r1, r1c, floatBinary(math.Asin)),  // FASN R1,R1c
+		0x49: binary(r0, r0c, floatBinary(math.Atan)),  // FATN R0,R0c
+		0x4A: binary(r1, r1c, floatBinary(math.Atan)),  // FATN R1,R1c
+		0x4B: unary(r0, floatUnary(math.Ceil)),         // FCEL R0
+		0x4C: unary(r0c, floatUnary(math.Ceil)),        // FCEL R0c
+		0x4D: unary(r1, floatUnary(math.Ceil)),         // FCEL R1
+		0x4E: unary(r1c, floatUnary(math.Ceil)),        // FCEL R1c
+		0x4F: binary(r0, r0c, floatBinary(math.Cos)),   // FCOS R0,R0c
+		0x50: binary(r1, r1c, floatBinary(math.Cos)),   // FCOS R1,R1c
+		0x51: unary(r0, ff2s),                          // FF2S R0
+		0x52: unary(r0c, ff2s),                         // FF2S R0c
+		0x53: unary(r1, ff2s),                          // FF2S R1
+		0x54: unary(r1c, ff2s),                         // FF2S R1c
+		0x55: unary(r0, ff2u),                          // FF2U R0
+		0x56: unary(r0c, ff2u),                         // FF2U R0c
+		0x57: unary(r1, ff2u),                          // FF2U R1
+		0x58: unary(r1c, ff2u),                         // FF2U R1c
+		0x59: unary(r0, floatUnary(math.Floor)),        // FFLR R0
+		0x5A: unary(r0c, floatUnary(math.Floor)),       // FFLR R0c
+		0x5B: unary(r1, floatUnary(math.Floor)),        // FFLR R1
+		0x5C: unary(r1c, floatUnary(math.Floor)),       // FFLR R1c
+		0x5D: binary(r0, r0c, floatBinary(math.Log10)), // FLOG R0,R0c
+		0x5E: binary(r1, r1c, floatBinary(math.Log10)), // FLOG R1,R1c
+		0x5F: binary(r0, r0c, floatBinary(math.Log)),   // FNLG R0,R0c
+		0x60: binary(r1, r1c, floatBinary(math.Log)),   // FNLG R1,R1c
+		0x61: binary(r0, r0c, fpow),                    // FPOW R0,R0c
+		0x62: binary(r1, r1c, fpow),                    // FPOW R1,R1c
+		0x63: binary(r0, r0c, floatBinary(math.Sin)),   // FSIN R0,R0c
+		0x64: binary(r1, r1c, floatBinary(math.Sin)),   // FSIN R1,R1c
+		0x65: binary(r0, r0c, floatBinary(math.Sqrt)),  // FSQR R0,R0c
+		0x66: binary(r1, r1c, floatBinary(math.Sqrt)),  // FSQR R1,R1c
+		0x67: binary(r0, r0c, floatBinary(math.Tan)),   // FTAN R0,R0c
+		0x68: binary(r1, r1c, floatBinary(math.Tan)),   // FTAN R1,R1c
+		0x69: unary(r0, fu2f),                          // FU2F R0
+		0x6A: unary(r0c, fu2f),                         // FU2F R0c
+		0x6B: unary(r1, fu2f),                          // FU2F R1
+		0x6C: unary(r1c, fu2f),                         // FU2F R1c
+		0x6D: load32(regPTR0),                          // MOV PTR0,U32
+		0x6E: load16(regOFS0),                          // MOV OFS0,U16
+		0x6F: load16(regIX0),                           // MOV IX0,U16
+		0x70: load32(regPTR1),                          // MOV PTR1,U32
+		0x71: load16(regOFS1),                          // MOV OFS1,U16
+		0x72: load16(regIX1),                           // MOV IX1,U16
+		0x73: move(r0, r0c),                            // MOV R0,R0c
+		0x74: move(r0, r1),                             // MOV R0,R1
+		0x75: move(r0, r1c),                            // MOV R0,R1c
+		0x76: move(r0c, r0),                            // MOV R0c,R0
+		0x77: move(r0c, r1),                            // MOV R0c,R1
+		0x78: move(r0c, r1c),                           // MOV R0c,R1c
+		0x79: move(r1, r0),                             // MOV R1,R0
+		0x7A: move(r1, r0c),                            // MOV R1,R0c
+		0x7B: move(r1, r1c),                            // MOV R1,R1c
+		0x7C: move(r1c, r0),                            // MOV R1c,R0
+		0x7D: move(r1c, r0c),                           // MOV R1c,R0c
+		0x7E: move(r1c, r1),                            // MOV R1c,R1
+		0x7F: loadIndirect(r0, 0),                      // MOV R0,*PTR0
+		0x80: moveFrom16(r0, regOFS0),                  // MOV R0,OFS0
+		0x81: moveFrom16(r0, regIX0),                   // MOV R0,IX0
+		0x82: storeIndirect(0, r0),                     // MOV *PTR0,R0
+		0x83: moveTo16(regOFS0, r0),                    // MOV OFS0,R0
+		0x84: moveTo16(regIX0, r0),                     // MOV IX0,R0
+		0x85: loadIndirect(r1, 1),                      // MOV R1,*PTR1
+		0x86: moveFrom16(r1, regOFS1),                  // MOV R1,OFS1
+		0x87: moveFrom16(r1, regIX1),                   // MOV R1,IX1
+		0x88: storeIndirect(1, r1),                     // MOV *PTR1,R1
+		0x89: moveTo16(regOFS1, r1),                    // MOV OFS1,R1
+		0x8A: moveTo16(regIX1, r1),                     // MOV IX1,R1
+		0x8B: moveFromStack(r0),                        // MOV R0,*SP[U8]
+		0x8C: moveFromStack(r0c),                       // MOV R0c,*SP[U8]
+		0x8D: moveFromStack(r1),                        // MOV R1,*SP[U8]
+		0x8E: moveFromStack(r1c),                       // MOV R1c,*SP[U8]
+		0x8F: moveFromMemory(r0),                       // MOV R0,M
+		0x90: moveToStack(r0),                          // MOV *SP[U8],R0
+		0x91: moveToStack(r0c),                         // MOV *SP[U8],R0c
+		0x92: moveToStack(r1),                          // MOV *SP[U8],R1
+		0x93: moveToStack(r1c),                         // MOV *SP[U8],R1c
+		0x94: moveToMemory(r0),                         // MOV M,R0
+		0x95: swap(r0, r0c),                            // SWP R0,R0c
+		0x96: swap(r0, r1),                             // SWP R0,R1
+		0x97: swap(r0, r1c),                            // SWP R0,R1c
+		0x98: swap(r0c, r1),                            // SWP R0c,R1
+		0x99: swap(r0c, r1c),                           // SWP R0c,R1c
+		0x9A: swap(r1, r1c),                            // SWP R1,R1c
+		0x9B: swapIndirect(r0, 0),                      // SWP R0,*PTR0
+		0x9C: swap16(r0, regOFS0),                      // SWP R0,OFS0
+		0x9D: swap16(r0, regIX0),                       // SWP R0,IX0
+		0x9E: swapIndirect(r1, 1),                      // SWP R1,*PTR1
+		0x9F: swap16(r1, regOFS1),                      // SWP R1,OFS1
+		0xA0: swap16(r1, regIX1),                       // SWP R1,IX1
+		0xA1: pushRegister32(regCP),                    // PSH CP
+		0xA2: pushST(),                                 // PSH ST
+		0xA3: pushGeneral(r0),                          // PSH R0
+		0xA4: pushGeneral(r0c),                         // PSH R0c
+		0xA5: pushGeneral(r1),                          // PSH R1
+		0xA6: pushGeneral(r1c),                         // PSH R1c
+		0xA7: pushRegister32(regDP0),                   // PSH DP0
+		0xA8: pushRegister32(regPTR0),                  // PSH PTR0
+		0xA9: pushRegister16(regOFS0),                  // PSH OFS0
+		0xAA: pushRegister16(regIX0),                   // PSH IX0
+		0xAB: pushRegister32(regDP1),                   // PSH DP1
+		0xAC: pushRegister32(regPTR1),                  // PSH PTR1
+		0xAD: pushRegister16(regOFS1),                  // PSH OFS1
+		0xAE: pushRegister16(regIX1),                   // PSH IX1
+		0xAF: pullRegister32(regCP, false),             // PUL CP
+		0xB0: pullST(),                                 // PUL ST
+		0xB1: pullGeneral(r0),                          // PUL R0
+		0xB2: pullGeneral(r0c),                         // PUL R0c
+		0xB3: pullGeneral(r1),                          // PUL R1
+		0xB4: pullGeneral(r1c),                         // PUL R1c
+		0xB5: pullRegister32(regDP0, true),             // PUL DP0
+		0xB6: pullRegister32(regPTR0, true),            // PUL PTR0
+		0xB7: pullRegister16(regOFS0),                  // PUL OFS0
+		0xB8: pullRegister16(regIX0),                   // PUL IX0
+		0xB9: pullRegister32(regDP1, true),             // PUL DP1
+		0xBA: pullRegister32(regPTR1, true),            // PUL PTR1
+		0xBB: pullRegister16(regOFS1),                  // PUL OFS1
+		0xBC: pullRegister16(regIX1),                   // PUL IX1
+		0xBD: subtractSP(),                             // SSP U8
+		0xBE: status(clc),                              // CLC
+		0xBF: status(sec),                              // SEC
+		0xC0: addressMode(false, register.Ptr),         // SDAM*
+		0xC1: addressMode(false, register.PtrOfs),      // SDAM*()
+		0xC2: addressMode(false, register.PtrIx),       // SDAM*[]
+		0xC3: addressMode(false, register.PtrIxOfs),    // SDAM*([])
+		0xC4: addressMode(false, register.PtrPtr),      // SDAM**
+		0xC5: addressMode(false, register.PtrPtrOfs),   // SDAM**()
+		0xC6: addressMode(false, register.PtrPtrIx),    // SDAM**[]
+		0xC7: addressMode(false, register.PtrPtrIxOfs), // SDAM**([])
+		0xC8: addressMode(true, register.Ptr),          // SCAM*
+		0xC9: addressMode(true, register.PtrOfs),       // SCAM*()
+		0xCA: addressMode(true, register.PtrIx),        // SCAM*[]
+		0xCB: addressMode(true, register.PtrIxOfs),     // SCAM*([])
+		0xCC: addressMode(true, register.PtrPtr),       // SCAM**
+		0xCD: addressMode(true, register.PtrPtrOfs),    // SCAM*(*)
+		0xCE: addressMode(true, register.PtrPtrIx),     // SCAM*[*]
+		0xCF: addressMode(true, register.PtrPtrIxOfs),  // SCAM*([*])
+		0xD0: operandSize(register.Operand8),           // SOS8
+		0xD1: operandSize(register.Operand16),          // SOS16
+		0xD2: operandSize(register.Operand32),          // SOS32
+		0xD3: operandSize(register.Operand64),          // SOS64
+		0xD4: mathMode(register.MathInteger),           // SMMI
+		0xD5: mathMode(register.MathFractional),        // SMMR
+		0xD6: mathMode(register.MathFixed),             // SMMX
+		0xD7: mathMode(register.MathFloat),             // SMMF
+		0xD8: status(cli),                              // CLI
+		0xD9: status(sei),                              // SEI
+		0xDA: unary(r0, dec),                           // DEC R0
+		0xDB: unary(r1, dec),                           // DEC R1
+		0xDC: unary(r0, inc),                           // INC R0
+		0xDD: unary(r1, inc),                           // INC R1
+		0xDE: unary(r0, neg),                           // NEG R0
+		0xDF: unary(r1, neg),                           // NEG R1
+		0xE0: unary(r0, set(-1)),                       // NG1 R0
+		0xE1: unary(r1, set(-1)),                       // NG1 R1
+		0xE2: unary(r0, not),                           // NOT R0
+		0xE3: unary(r1, not),                           // NOT R1
+		0xE4: unary16(regOFS0, next16),                 // NEXT OFS0
+		0xE5: unary16(regOFS1, next16),                 // NEXT OFS1
+		0xE6: unary16(regIX0, next16),                  // NEXT IX0
+		0xE7: unary16(regIX1, next16),                  // NEXT IX1
+		0xE8: unary(r0, set(1)),                        // ONE R0
+		0xE9: unary(r1, set(1)),                        // ONE R1
+		0xEA: unary16(regOFS0, set16(1)),               // ONE OFS0
+		0xEB: unary16(regOFS1, set16(1)),               // ONE OFS1
+		0xEC: unary16(regIX0, set16(1)),                // ONE IX0
+		0xED: unary16(regIX1, set16(1)),                // ONE IX1
+		0xEE: unary16(regOFS0, prev16),                 // PREV OFS0
+		0xEF: unary16(regOFS1, prev16),                 // PREV OFS1
+		0xF0: unary16(regIX0, prev16),                  // PREV IX0
+		0xF1: unary16(regIX1, prev16),                  // PREV IX1
+		0xF2: unary(r0, sha1),                          // SHA R0
+		0xF3: unary(r1, sha1),                          // SHA R1
+		0xF4: unary(r0, shl1),                          // SHL R0
+		0xF5: unary(r1, shl1),                          // SHL R1
+		0xF6: unary(r0, shr1),                          // SHR R0
+		0xF7: unary(r1, shr1),                          // SHR R1
+		0xF8: unary(r0, set(0)),                        // ZRO R0
+		0xF9: unary(r1, set(0)),                        // ZRO R1
+		0xFA: unary16(regOFS0, set16(0)),               // ZRO OFS0
+		0xFB: unary16(regOFS1, set16(0)),               // ZRO OFS1
+		0xFC: unary16(regIX0, set16(0)),                // ZRO IX0
+		0xFD: unary16(regIX1, set16(0)),                // ZRO IX1
+		0xFE: nop(),                                    // NOP
+		// 0xFF is EXT, which is decoded by Step
+	},
+	{
+		0x00: immediate(r0, isa.OperandO, add),   // ADD R0,O
+		0x01: immediate(r0c, isa.OperandO, add),  // ADD R0c,O
+		0x02: immediate(r1, isa.OperandO, add),   // ADD R1,O
+		0x03: immediate(r1c, isa.OperandO, add),  // ADD R1c,O
+		0x04: indirectImmediate(0, add, true),    // ADD *PTR0,O
+		0x05: indirectImmediate(1, add, true),    // ADD *PTR1,O
+		0x06: toRegister(r0, 0, add),             // ADD R0,*PTR0
+		0x07: toRegister(r0c, 0, add),            // ADD R0c,*PTR0
+		0x08: toRegister(r1, 1, add),             // ADD R1,*PTR1
+		0x09: toRegister(r1c, 1, add),            // ADD R1c,*PTR1
+		0x0A: toIndirect(0, r0, add),             // ADD *PTR0,R0
+		0x0B: toIndirect(0, r0c, add),            // ADD *PTR0,R0c
+		0x0C: toIndirect(1, r1, add),             // ADD *PTR1,R1
+		0x0D: toIndirect(1, r1c, add),            // ADD *PTR1,R1c
+		0x0E: immediate(r0, isa.OperandO, cmp),   // CMP R0,O
+		0x0F: immediate(r0c, isa.OperandO, cmp),  // CMP R0c,O
+		0x10: immediate(r1, isa.OperandO, cmp),   // CMP R1,O
+		0x11: immediate(r1c, isa.OperandO, cmp),  // CMP R1c,O
+		0x12: indirectImmediate(0, cmp, false),   // CMP *PTR0,O
+		0x13: indirectImmediate(1, cmp, false),   // CMP *PTR1,O
+		0x14: immediate(r0, isa.OperandU8, sha),  // SHA R0,U8
+		0x15: immediate(r0c, isa.OperandU8, sha), // SHA R0c,U8
+		0x16: immediate(r1, isa.OperandU8, sha),  // SHA R1,U8
+		0x17: immediate(r1c, isa.OperandU8, sha), // SHA R1c,U8
+		0x18: immediate(r0, isa.OperandU8, shl),  // SHL R0,U8
+		0x19: immediate(r0c, isa.OperandU8, shl), // SHL R0c,U8
+		0x1A: immediate(r1, isa.OperandU8, shl),  // SHL R1,U8
+		0x1B: immediate(r1c, isa.OperandU8, shl), // SHL R1c,U8
+		0x1C: immediate(r0, isa.OperandU8, shr),  // SHR R0,U8
+		0x1D: immediate(r0c, isa.OperandU8, shr), // SHR R0c,U8
+		0x1E: immediate(r1, isa.OperandU8, shr),  // SHR R1,U8
+		0x1F: immediate(r1c, isa.OperandU8, shr), // SHR R1c,U8
+		0x20: immediate(r0, isa.OperandO, sub),   // SUB R0,O
+		0x21: immediate(r0c, isa.OperandO, sub),  // SUB R0c,O
+		0x22: immediate(r1, isa.OperandO, sub),   // SUB R1,O
+		0x23: immediate(r1c, isa.OperandO, sub),  // SUB R1c,O
+		0x24: indirectImmediate(0, sub, true),    // SUB *PTR0,O
+		0x25: indirectImmediate(1, sub, true),    // SUB *PTR1,O
+		0x26: toRegister(r0, 0, sub),             // SUB R0,*PTR0
+		0x27: toRegister(r0c, 0, sub),            // SUB R0c,*PTR0
+		0x28: toRegister(r1, 1, sub),             // SUB R1,*PTR1
+		0x29: toRegister(r1c, 1, sub),            // SUB R1c,*PTR1
+		0x2A: toIndirect(0, r0, sub),             // SUB *PTR0,R0
+		0x2B: toIndirect(0, r0c, sub),            // SUB *PTR0,R0c
+		0x2C: toIndirect(1, r1, sub),             // SUB *PTR1,R1
+		0x2D: toIndirect(1, r1c, sub),            // SUB *PTR1,R1c
+		0x2E: load32(regCP),                      // MOV CP,U32
+		0x2F: load32(regDP0),                     // MOV DP0,U32
+		0x30: load32(regDP1),                     // MOV DP1,U32
+		0x31: load32(regSB),                      // MOV SB,U32
+		0x32: load16(regSP),                      // MOV SP,U16
+		0x33: moveTo32(regCP, r0, false),         // MOV CP,R0
+		0x34: moveToST(),                         // MOV ST,R0
+		0x35: moveTo32(regDP0, r0, true),         // MOV DP0,R0
+		0x36: moveTo32(regPTR0, r0, true),        // MOV PTR0,R0
+		0x37: moveTo32(regDP1, r0, true),         // MOV DP1,R0
+		0x38: moveTo32(regPTR1, r0, true),        // MOV PTR1,R0
+		0x39: moveTo32(regSB, r0, true),          // MOV SB,R0
+		0x3A: moveTo16(regSP, r0),                // MOV SP,R0
+		0x3B: moveFrom32(r0, regCP, false),       // MOV R0,CP
+		0x3C: moveFromST(),                       // MOV R0,ST
+		0x3D: moveFrom32(r0, regDP0, true),       // MOV R0,DP0
+		0x3E: moveFrom32(r0, regPTR0, true),      // MOV R0,PTR0
+		0x3F: moveFrom32(r0, regDP1, true),       // MOV R0,DP1
+		0x40: moveFrom32(r0, regPTR1, true),      // MOV R0,PTR1
+		0x41: moveFrom32(r0, regSB, true),        // MOV R0,SB
+		0x42: moveFrom16(r0, regSP),              // MOV R0,SP
+		0x43: loadImmediate(r0),                  // MOV R0,O
+		0x44: loadImmediate(r0c),                 // MOV R0c,O
+		0x45: loadImmediate(r1),                  // MOV R1,O
+		0x46: loadImmediate(r1c),                 // MOV R1c,O
+		0x47: loadIndirect(r0c, 0),               // MOV R0c,*PTR0
+		0x48: loadIndirect(r1c, 1),               // MOV R1c,*PTR1
+		0x49: storeIndirect(0, r0c),              // MOV *PTR0,R0c
+		0x4A: storeIndirect(1, r1c),              // MOV *PTR1,R1c
+		0x4B: moveFromMemory(r0c),                // MOV R0c,M
+		0x4C: moveFromMemory(r1),                 // MOV R1,M
+		0x4D: moveFromMemory(r1c),                // MOV R1c,M
+		0x4E: moveToMemory(r0c),                  // MOV M,R0c
+		0x4F: moveToMemory(r1),                   // MOV M,R1
+		0x50: moveToMemory(r1c),                  // MOV M,R1c
+		0x51: swapMemory(r0),                     // SWP R0,M
+		0x52: swapMemory(r0c),                    // SWP R0c,M
+		0x53: swapMemory(r1),                     // SWP R1,M
+		0x54: swapMemory(r1c),                    // SWP R1c,M
+		0x55: unary(r0c, dec),                    // DEC R0c
+		0x56: unary(r1c, dec),                    // DEC R1c
+		0x57: unary(r0c, inc),                    // INC R0c
+		0x58: unary(r1c, inc),                    // INC R1c
+		0x59: unary(r0c, neg),                    // NEG R0c
+		0x5A: unary(r1c, neg),                    // NEG R1c
+		0x5B: unary(r0c, set(-1)),                // NG1 R0c
+		0x5C: unary(r1c, set(-1)),                // NG1 R1c
+		0x5D: unary(r0c, not),                    // NOT R0c
+		0x5E: unary(r1c, not),                    // NOT R1c
+		0x5F: unary(r0c, set(1)),                 // ONE R0c
+		0x60: unary(r1c, set(1)),                 // ONE R1c
+		0x61: unary(r0c, sha1),                   // SHA R0c
+		0x62: unary(r1c, sha1),                   // SHA R1c
+		0x63: unary(r0c, shl1),                   // SHL R0c
+		0x64: unary(r1c, shl1),                   // SHL R1c
+		0x65: unary(r0c, shr1),                   // SHR R0c
+		0x66: unary(r1c, shr1),                   // SHR R1c
+		0x67: unary(r0c, set(0)),                 // ZRO R0c
+		0x68: unary(r1c, set(0)),                 // ZRO R1c
+		0x69: load32(regCTR0),                    // MOV CTR0,U32
+		0x6A: load32(regCTR1),                    // MOV CTR1,U32
+		0x6B: load16(regCS0),                     // MOV CS0,S16
+		0x6C: load16(regCS1),                     // MOV CS1,S16
+		0x6D: load16(regIS0),                     // MOV IS0,S16
+		0x6E: load16(regIS1),                     // MOV IS1,S16
+		0x6F: moveFrom32(r0, regCTR0, true),      // MOV R0,CTR0
+		0x70: moveFrom32(r0, regCTR1, true),      // MOV R0,CTR1
+		0x71: moveTo32(regCTR0, r0, true),        // MOV CTR0,R0
+		0x72: moveTo32(regCTR1, r0, true),        // MOV CTR1,R0
+		0x73: stepCounter(0),                     // STEP CTR0
+		0x74: stepCounter(1),                     // STEP CTR1
+		0x75: stepIndex(0),                       // STEP IX0
+		0x76: stepIndex(1),                       // STEP IX1
+		0x77: loop(0),                            // LOOP CTR0,S8
+		0x78: loop(1),                            // LOOP CTR1,S8
+	},
+}
+
+// ==== ALU operations, where the math mode selects how ADC, SBB, MUL, DIV, and CMP operate
+
+func adc(w, r *register.GeneralRegister, st *register.StatusRegister) error {
+	switch st.MathMode() {
+	case register.MathFractional:
+		return w.AddFraction(*r, st)
+	case register.MathFixed:
+		w.AddFixed(*r, st)
+	case register.MathFloat:
+		w.AddFloat(*r, st)
+	default:
+		w.AddInteger(*r, st)
+	}
+
+	return nil
+}
+
+func add(w, r *register.GeneralRegister, st *register.StatusRegister) error {
+	st.ClearCarry()
+	return adc(w, r, st)
+}
+
+func and(w, r *register.GeneralRegister, st *register.StatusRegister) error {
+	w.And(*r, st)
+	return nil
+}
+
+func cmp(w, r *register.GeneralRegister, st *register.StatusRegister) error {
+	switch st.MathMode() {
+	case register.MathFractional:
+		return w.CompareFraction(*r, st)
+	case register.MathFixed:
+		w.CompareFixed(*r, st)
+	case register.MathFloat:
+		w.CompareFloat(*r, st)
+	default:
+		w.Compare(*r, st)
+	}
+
+	return nil
+}
+
+func divs(w, r *register.GeneralRegister, st *register.StatusRegister) error {
+	switch st.MathMode() {
+	case register.MathFractional:
+		return w.DivideFraction(*r, st)
+	case register.MathFixed:
+		return w.DivideFixed(*r, st)
+	case register.MathFloat:
+		w.DivideFloat(*r, st)
+		return nil
+	}
+
+	return w.DivideIntegerSigned(r, st)
+}
+
+// divu is unsigned in integer mode, other math modes are always signed
+func divu(w, r *register.GeneralRegister, st *register.StatusRegister) error {
+	if st.MathMode() == register.MathInteger {
+		return w.DivideIntegerUnsigned(r, st)
+	}
+
+	return divs(w, r, st)
+}
+
+func muls(w, r *register.GeneralRegister, st *register.StatusRegister) error {
+	switch st.MathMode() {
+	case register.MathFractional:
+		return w.MultiplyFraction(*r, st)
+	case register.MathFixed:
+		w.MultiplyFixed(*r, st)
+	case register.MathFloat:
+		w.MultiplyFloat(*r, st)
+	default:
+		w.MultiplyIntegerSigned(r, st)
+	}
+
+	return nil
+}
+
+// mulu is unsigned in integer mode, other math modes are always signed
+func mulu(w, r *register.GeneralRegister, st *register.StatusRegister) error {
+	if st.MathMode() == register.MathInteger {
+		w.MultiplyIntegerUnsigned(r, st)
+		return nil
+	}
+
+	return muls(w, r, st)
+}
+
+func or(w, r *register.GeneralRegister, st *register.StatusRegister) error {
+	w.Or(*r, st)
+	return nil
+}
+
+func sha(w, r *register.GeneralRegister, st *register.StatusRegister) error {
+	w.ShiftRightArithmetic(*r, st)
+	return nil
+}
+
+func shl(w, r *register.GeneralRegister, st *register.StatusRegister) error {
+	w.ShiftLeft(*r, st)
+	return nil
+}
+
+func shr(w, r *register.GeneralRegister, st *register.StatusRegister) error {
+	w.ShiftRight(*r, st)
+	return nil
+}
+
+func sbb(w, r *register.GeneralRegister, st *register.StatusRegister) error {
+	switch st.MathMode() {
+	case register.MathFractional:
+		return w.SubtractFraction(*r, st)
+	case register.MathFixed:
+		w.SubtractFixed(*r, st)
+	case register.MathFloat:
+		w.SubtractFloat(*r, st)
+	default:
+		w.SubtractInteger(*r, st)
+	}
+
+	return nil
+}
+
+func sub(w, r *register.GeneralRegister, st *register.StatusRegister) error {
+	st.ClearCarry()
+	return sbb(w, r, st)
+}
+
+func xor(w, r *register.GeneralRegister, st *register.StatusRegister) error {
+	w.Xor(*r, st)
+	return nil
+}
+
+// ==== Unary ALU operations, which use integer math regardless of the math mode
+
+func dec(w *register.GeneralRegister, st *register.StatusRegister) error {
+	w.Decrement(st)
+	return nil
+}
+
+func inc(w *register.GeneralRegister, st *register.StatusRegister) error {
+	w.Increment(st)
+	return nil
+}
+
+func neg(w *register.GeneralRegister, st *register.StatusRegister) error {
+	w.Negate(st)
+	return nil
+}
+
+func not(w *register.GeneralRegister, st *register.StatusRegister) error {
+	w.Not(st)
+	return nil
+}
+
+// sha1 shifts right arithmetic by 1 bit, where Carry is unchanged
+func sha1(w *register.GeneralRegister, st *register.StatusRegister) error {
+	carry := st.IsCarry()
+	w.ShiftRightArithmetic(1, st)
+	st.Carry(carry)
+	return nil
+}
+
+// shl1 shifts left by 1 bit with carry, which is a rotate through carry
+func shl1(w *register.GeneralRegister, st *register.StatusRegister) error {
+	w.RotateLeftThroughCarry(1, st)
+	return nil
+}
+
+// shr1 shifts right by 1 bit with carry, which is a rotate through carry
+func shr1(w *register.GeneralRegister, st *register.StatusRegister) error {
+	w.RotateRightThroughCarry(1, st)
+	return nil
+}
+
+// set returns a unaryFunc that sets w = val, for ZRO, ONE, and NG1
+func set(val int64) unaryFunc {
+	return func(w *register.GeneralRegister, st *register.StatusRegister) error {
+		w.SetInteger(val, st)
+		return nil
+	}
+}
+
+// binary applies f to general registers w and r
+func binary(w, r int, f aluFunc) instruction {
+	return func(p *Processor, _ uint64) error {
+		st := p.regs.ST()
+		err := f(p.general(w), p.general(r), &st)
+		p.regs.SetST(st)
+		return err
+	}
+}
+
+// immediate applies f to general register w and an immediate.
+// An immediate of the current operand size is sign extended.
+func immediate(w int, kind isa.Operand, f aluFunc) instruction {
+	return func(p *Processor, imm uint64) error {
+		st := p.regs.ST()
+		op := register.GeneralRegister(imm)
+		if kind == isa.OperandO {
+			op.ExtendSign(st)
+		}
+
+		err := f(p.general(w), &op, &st)
+		p.regs.SetST(st)
+		return err
+	}
+}
+
+// unary applies f to general register w
+func unary(w int, f unaryFunc) instruction {
+	return func(p *Processor, _ uint64) error {
+		st := p.regs.ST()
+		err := f(p.general(w), &st)
+		p.regs.SetST(st)
+		return err
+	}
+}
+
+// ==== Floating point operations, which use the precision of the operand size regardless of the math mode
+
+func fs2f(w *register.GeneralRegister, st *register.StatusRegister) error {
+	w.SignedToFloat(st)
+	return nil
+}
+
+func fu2f(w *register.GeneralRegister, st *register.StatusRegister) error {
+	w.UnsignedToFloat(st)
+	return nil
+}
+
+func ff2s(w *register.GeneralRegister, st *register.StatusRegister) error {
+	w.FloatToSigned(st)
+	return nil
+}
+
+func ff2u(w *register.GeneralRegister, st *register.StatusRegister) error {
+	w.FloatToUnsigned(st)
+	return nil
+}
+
+func fpow(w, r *register.GeneralRegister, st *register.StatusRegister) error {
+	w.PowerFloat(*r, st)
+	return nil
+}
+
+// floatUnary returns a unaryFunc that sets w = fn(w)
+func floatUnary(fn func(float64) float64) unaryFunc {
+	return func(w *register.GeneralRegister, st *register.StatusRegister) error {
+		w.FloatFunction(*w, fn, st)
+		return nil
+	}
+}
+
+// floatBinary returns an aluFunc that sets w = fn(r)
+func floatBinary(fn func(float64) float64) aluFunc {
+	return func(w, r *register.GeneralRegister, st *register.StatusRegister) error {
+		w.FloatFunction(*r, fn, st)
+		return nil
+	}
+}
+
+// ==== 16 bit offset and index operations
+
+// add16 sets reg = reg + op, with the following side effects:
+// - Carry is true if unsigned result < original value
+// - Zero is true if result is zero
+// - Negative is true if bit 15 of the result is set
+func add16(reg *uint16, op uint16, st *register.StatusRegister) {
+	old := *reg
+	*reg += op
+
+	st.Carry(*reg < old)
+	st.Zero(*reg == 0)
+	st.Negative(*reg >= 0x8000)
+}
+
+// sub16 sets reg = reg - op, with the following side effects:
+// - Carry is true if unsigned result > original value
+// - Zero is true if result is zero
+// - Negative is true if bit 15 of the result is set
+func sub16(reg *uint16, op uint16, st *register.StatusRegister) {
+	old := *reg
+	*reg -= op
+
+	st.Carry(*reg > old)
+	st.Zero(*reg == 0)
+	st.Negative(*reg >= 0x8000)
+}
+
+// cmp16 compares reg with op, with the following side effects:
+// - Carry is true if reg >= op unsigned
+// - Zero is true if reg == op
+func cmp16(reg *uint16, op uint16, st *register.StatusRegister) {
+	st.Carry(*reg >= op)
+	st.Zero(*reg == op)
+}
+
+// next16 sets reg = reg + the effective operand size in bytes, with side effects as described by add16
+func next16(reg *uint16, st *register.StatusRegister) {
+	add16(reg, 1<<st.EffectiveOperandSize(), st)
+}
+
+// prev16 sets reg = reg - the effective operand size in bytes, with side effects as described by sub16
+func prev16(reg *uint16, st *register.StatusRegister) {
+	sub16(reg, 1<<st.EffectiveOperandSize(), st)
+}
+
+// set16 returns a function that sets reg = val, with the following side effects:
+// - Zero is true if val is zero
+// - Negative is true if bit 15 of val is set
+func set16(val uint16) func(reg *uint16, st *register.StatusRegister) {
+	return func(reg *uint16, st *register.StatusRegister) {
+		*reg = val
+
+		st.Zero(val == 0)
+		st.Negative(val >= 0x8000)
+	}
+}
+
+// index16 applies f to a 16 bit register and a U16 immediate
+func index16(reg reg16, f func(reg *uint16, op uint16, st *register.StatusRegister)) instruction {
+	return func(p *Processor, imm uint64) error {
+		st := p.regs.ST()
+		f(reg(&p.regs), uint16(imm), &st)
+		p.regs.SetST(st)
+		return nil
+	}
+}
+
+// unary16 applies f to a 16 bit register
+func unary16(reg reg16, f func(reg *uint16, st *register.StatusRegister)) instruction {
+	return func(p *Processor, _ uint64) error {
+		st := p.regs.ST()
+		f(reg(&p.regs), &st)
+		p.regs.SetST(st)
+		return nil
+	}
+}
+
+// ==== Counter and index stepping
+
+// stepCounter adds CS to CTR of a counter register set
+func stepCounter(set uint8) instruction {
+	return func(p *Processor, _ uint64) error {
+		p.regs.StepCounter(set)
+		return nil
+	}
+}
+
+// stepIndex adds IS to IX of a pointer register set
+func stepIndex(set uint8) instruction {
+	return func(p *Processor, _ uint64) error {
+		p.regs.StepIndex(set)
+		return nil
+	}
+}
+
+// ==== Branches and jumps
+
+func carryClear(st register.StatusRegister) bool    { return !st.IsCarry() }
+func carrySet(st register.StatusRegister) bool      { return st.IsCarry() }
+func overflowClear(st register.StatusRegister) bool { return !st.IsOverflow() }
+func overflowSet(st register.StatusRegister) bool   { return st.IsOverflow() }
+func zeroSet(st register.StatusRegister) bool       { return st.IsZero() }
+func zeroClear(st register.StatusRegister) bool     { return !st.IsZero() }
+func negativeSet(st register.StatusRegister) bool   { return st.IsNegative() }
+func negativeClear(st register.StatusRegister) bool { return !st.IsNegative() }
+
+// branch adds an S8 to PC if the condition is true
+func branch(cond func(st register.StatusRegister) bool) instruction {
+	return func(p *Processor, imm uint64) error {
+		if cond(p.regs.ST()) {
+			p.regs.PC += uint32(int8(imm))
+		}
+
+		return nil
+	}
+}
+
+// loop decrements CTR of a counter register set, and adds an S8 to PC if CTR is not zero
+func loop(set uint8) instruction {
+	return func(p *Processor, imm uint64) error {
+		if p.regs.DecrementCounter(set) {
+			p.regs.PC += uint32(int8(imm))
+		}
+
+		return nil
+	}
+}
+
+// jumpAbsolute sets CP + PC to the lowest 32 bits of general register r, or a U32 if r < 0.
+// If subroutine is true, PC is pushed first.
+func jumpAbsolute(subroutine bool, r int) instruction {
+	return func(p *Processor, imm uint64) error {
+		if r >= 0 {
+			imm = p.general(r).Uint64()
+		}
+
+		if subroutine {
+			if err := p.stack().Push32(p.regs.PC); err != nil {
+				return err
+			}
+		}
+
+		// PC is relative to CP, so subtract CP to reach the absolute address
+		p.regs.PC = uint32(imm) - p.regs.CP
+		return nil
+	}
+}
+
+// jumpRelative adds the lowest 16 bits of general register r, or an S16 if r < 0, to PC.
+// If subroutine is true, PC is pushed first.
+func jumpRelative(subroutine bool, r int) instruction {
+	return func(p *Processor, imm uint64) error {
+		if r >= 0 {
+			imm = p.general(r).Uint64()
+		}
+
+		if subroutine {
+			if err := p.stack().Push32(p.regs.PC); err != nil {
+				return err
+			}
+		}
+
+		p.regs.PC += uint32(int16(imm))
+		return nil
+	}
+}
+
+// ==== Moves and swaps
+
+// setZN sets Zero and Negative for a value whose highest bit is signBit
+func (p *Processor) setZN(val uint64, signBit uint64) {
+	st := p.regs.ST()
+	st.Zero(val == 0)
+	st.Negative((val & signBit) == signBit)
+	p.regs.SetST(st)
+}
+
+// load16 loads a 16 bit register with a U16
+func load16(reg reg16) instruction {
+	return func(p *Processor, imm uint64) error {
+		*reg(&p.regs) = uint16(imm)
+		p.setZN(imm, register.SignBit16)
+		return nil
+	}
+}
+
+// load32 loads a 32 bit register with a U32
+func load32(reg reg32) instruction {
+	return func(p *Processor, imm uint64) error {
+		*reg(&p.regs) = uint32(imm)
+		p.setZN(imm, register.SignBit32)
+		return nil
+	}
+}
+
+// loadImmediate loads general register w with a sign extended immediate of the current operand size
+func loadImmediate(w int) instruction {
+	return func(p *Processor, imm uint64) error {
+		reg := p.general(w)
+		*reg = register.GeneralRegister(imm)
+		reg.ExtendSign(p.regs.ST())
+		p.setZN(reg.Uint64(), register.SignBit64)
+		return nil
+	}
+}
+
+// move copies general register r into w, extending the sign of the current operand size
+func move(w, r int) instruction {
+	return func(p *Processor, _ uint64) error {
+		reg := p.general(w)
+		*reg = *p.general(r)
+		reg.ExtendSign(p.regs.ST())
+		p.setZN(reg.Uint64(), register.SignBit64)
+		return nil
+	}
+}
+
+// moveFrom16 copies a 16 bit register into general register w
+func moveFrom16(w int, reg reg16) instruction {
+	return func(p *Processor, _ uint64) error {
+		val := uint64(*reg(&p.regs))
+		p.general(w).SetUint64(val)
+		p.setZN(val, register.SignBit64)
+		return nil
+	}
+}
+
+// moveTo16 copies the lowest 16 bits of general register r into a 16 bit register
+func moveTo16(reg reg16, r int) instruction {
+	return func(p *Processor, _ uint64) error {
+		val := p.general(r).Uint16()
+		*reg(&p.regs) = val
+		p.setZN(uint64(val), register.SignBit16)
+		return nil
+	}
+}
+
+// moveFrom32 copies a 32 bit register into general register w, setting flags only if flags is true
+func moveFrom32(w int, reg reg32, flags bool) instruction {
+	return func(p *Processor, _ uint64) error {
+		val := uint64(*reg(&p.regs))
+		p.general(w).SetUint64(val)
+		if flags {
+			p.setZN(val, register.SignBit64)
+		}
+
+		return nil
+	}
+}
+
+// moveTo32 copies the lowest 32 bits of general register r into a 32 bit register, setting flags only if flags is true
+func moveTo32(reg reg32, r int, flags bool) instruction {
+	return func(p *Processor, _ uint64) error {
+		val := p.general(r).Uint32()
+		*reg(&p.regs) = val
+		if flags {
+			p.setZN(uint64(val), register.SignBit32)
+		}
+
+		return nil
+	}
+}
+
+// moveFromST copies ST into R0 without affecting ST
+func moveFromST() instruction {
+	return func(p *Processor, _ uint64) error {
+		p.general(r0).SetUint64(uint64(p.regs.ST()))
+		return nil
+	}
+}
+
+// moveToST copies the lowest 32 bits of R0 into ST
+func moveToST() instruction {
+	return func(p *Processor, _ uint64) error {
+		p.regs.SetST(register.StatusRegister(p.general(r0).Uint32()))
+		return nil
+	}
+}
+
+// stackAddress returns the address of *SP[U8], which is U8 bytes above SP
+func (p *Processor) stackAddress(imm uint64) uint32 {
+	return p.regs.SB + uint32(p.regs.SP) + uint32(imm)
+}
+
+// moveFromStack reads a value of the current operand size at *SP[U8] into general register w
+func moveFromStack(w int) instruction {
+	return func(p *Processor, imm uint64) error {
+		return p.load(w, p.stackAddress(imm))
+	}
+}
+
+// moveToStack writes general register r to *SP[U8] using the current operand size
+func moveToStack(r int) instruction {
+	return func(p *Processor, imm uint64) error {
+		return p.writeOperand(p.stackAddress(imm), *p.general(r))
+	}
+}
+
+// memoryAddress returns the address of M, which is relative to the data pointer of general register r
+func (p *Processor) memoryAddress(r int, imm uint64) uint32 {
+	return *p.regs.DP(uint8(r / 2)) + uint32(imm)
+}
+
+// moveFromMemory reads a value of the current operand size at M into general register w
+func moveFromMemory(w int) instruction {
+	return func(p *Processor, imm uint64) error {
+		return p.load(w, p.memoryAddress(w, imm))
+	}
+}
+
+// moveToMemory writes general register r to M using the current operand size
+func moveToMemory(r int) instruction {
+	return func(p *Processor, imm uint64) error {
+		return p.writeOperand(p.memoryAddress(r, imm), *p.general(r))
+	}
+}
+
+// load reads a value of the current operand size at addr into general register w, setting Zero and Negative
+func (p *Processor) load(w int, addr uint32) error {
+	val, err := p.readOperand(addr)
+	if err != nil {
+		return err
+	}
+
+	*p.general(w) = val
+	p.setZN(val.Uint64(), register.SignBit64)
+	return nil
+}
+
+// swap exchanges general registers a and b, setting flags for the new value of a
+func swap(a, b int) instruction {
+	return func(p *Processor, _ uint64) error {
+		ra, rb := p.general(a), p.general(b)
+		*ra, *rb = *rb, *ra
+		p.setZN(ra.Uint64(), register.SignBit64)
+		return nil
+	}
+}
+
+// swap16 exchanges general register a and a 16 bit register, setting flags for the new value of a
+func swap16(a int, reg reg16) instruction {
+	return func(p *Processor, _ uint64) error {
+		var (
+			ra  = p.general(a)
+			r16 = reg(&p.regs)
+			val = uint64(*r16)
+		)
+
+		*r16 = ra.Uint16()
+		ra.SetUint64(val)
+		p.setZN(val, register.SignBit64)
+		return nil
+	}
+}
+
+// swapMemory exchanges general register a and a value of the current operand size at M
+func swapMemory(a int) instruction {
+	return func(p *Processor, imm uint64) error {
+		return p.swapAt(a, p.memoryAddress(a, imm))
+	}
+}
+
+// ==== Indirect
+
+// effectiveAddress returns the address of *PTR for register set 0 or 1
+func (p *Processor) effectiveAddress(set uint8) (uint32, error) {
+	return EffectiveAddress(&p.regs, set, p.bus)
+}
+
+// loadIndirect reads a value of the current operand size at *PTR into general register w
+func loadIndirect(w int, set uint8) instruction {
+	return func(p *Processor, _ uint64) error {
+		addr, err := p.effectiveAddress(set)
+		if err != nil {
+			return err
+		}
+
+		return p.load(w, addr)
+	}
+}
+
+// storeIndirect writes general register r to *PTR using the current operand size, setting flags for the value written
+func storeIndirect(set uint8, r int) instruction {
+	return func(p *Processor, _ uint64) error {
+		addr, err := p.effectiveAddress(set)
+		if err != nil {
+			return err
+		}
+
+		val := *p.general(r)
+		if err := p.writeOperand(addr, val); err != nil {
+			return err
+		}
+
+		val.ExtendSign(p.regs.ST())
+		p.setZN(val.Uint64(), register.SignBit64)
+		return nil
+	}
+}
+
+// swapIndirect exchanges general register a and a value of the current operand size at *PTR
+func swapIndirect(a int, set uint8) instruction {
+	return func(p *Processor, _ uint64) error {
+		addr, err := p.effectiveAddress(set)
+		if err != nil {
+			return err
+		}
+
+		return p.swapAt(a, addr)
+	}
+}
+
+// swapAt exchanges general register a and a value of the current operand size at addr
+func (p *Processor) swapAt(a int, addr uint32) error {
+	ra := p.general(a)
+
+	val, err := p.readOperand(addr)
+	if err != nil {
+		return err
+	}
+
+	if err := p.writeOperand(addr, *ra); err != nil {
+		return err
+	}
+
+	*ra = val
+	p.setZN(val.Uint64(), register.SignBit64)
+	return nil
+}
+
+// toRegister applies f to general register w and a value of the current operand size at *PTR
+func toRegister(w int, set uint8, f aluFunc) instruction {
+	return func(p *Processor, _ uint64) error {
+		addr, err := p.effectiveAddress(set)
+		if err != nil {
+			return err
+		}
+
+		op, err := p.readOperand(addr)
+		if err != nil {
+			return err
+		}
+
+		st := p.regs.ST()
+		err = f(p.general(w), &op, &st)
+		p.regs.SetST(st)
+		return err
+	}
+}
+
+// toIndirect applies f to a value of the current operand size at *PTR and general register r, writing the result to *PTR
+func toIndirect(set uint8, r int, f aluFunc) instruction {
+	return func(p *Processor, _ uint64) error {
+		op := *p.general(r)
+		return p.modifyIndirect(set, &op, f, true)
+	}
+}
+
+// indirectImmediate applies f to a value of the current operand size at *PTR and a sign extended immediate.
+// The result is only written to *PTR if write is true.
+func indirectImmediate(set uint8, f aluFunc, write bool) instruction {
+	return func(p *Processor, imm uint64) error {
+		op := register.GeneralRegister(imm)
+		op.ExtendSign(p.regs.ST())
+		return p.modifyIndirect(set, &op, f, write)
+	}
+}
+
+// modifyIndirect applies f to a value of the current operand size at *PTR and op.
+// The result is only written to *PTR if write is true.
+func (p *Processor) modifyIndirect(set uint8, op *register.GeneralRegister, f aluFunc, write bool) error {
+	addr, err := p.effectiveAddress(set)
+	if err != nil {
+		return err
+	}
+
+	val, err := p.readOperand(addr)
+	if err != nil {
+		return err
+	}
+
+	st := p.regs.ST()
+	if err := f(&val, op, &st); err != nil {
+		return err
+	}
+
+	if write {
+		if err := p.writeOperand(addr, val); err != nil {
+			return err
+		}
+	}
+
+	p.regs.SetST(st)
+	return nil
+}
+
+// ==== Stack
+
+// pushRegister16 pushes a 16 bit register
+func pushRegister16(reg reg16) instruction {
+	return func(p *Processor, _ uint64) error {
+		return p.stack().Push16(*reg(&p.regs))
+	}
+}
+
+// pushRegister32 pushes a 32 bit register
+func pushRegister32(reg reg32) instruction {
+	return func(p *Processor, _ uint64) error {
+		return p.stack().Push32(*reg(&p.regs))
+	}
+}
+
+// pushST pushes ST
+func pushST() instruction {
+	return func(p *Processor, _ uint64) error {
+		return p.stack().Push32(uint32(p.regs.ST()))
+	}
+}
+
+// pushGeneral pushes all 64 bits of general register r
+func pushGeneral(r int) instruction {
+	return func(p *Processor, _ uint64) error {
+		return p.stack().Push64(p.general(r).Uint64())
+	}
+}
+
+// pullRegister16 pulls a 16 bit register
+func pullRegister16(reg reg16) instruction {
+	return func(p *Processor, _ uint64) error {
+		val, err := p.stack().Pull16()
+		if err != nil {
+			return err
+		}
+
+		*reg(&p.regs) = val
+		p.setZN(uint64(val), register.SignBit16)
+		return nil
+	}
+}
+
+// pullRegister32 pulls a 32 bit register, setting flags only if flags is true
+func pullRegister32(reg reg32, flags bool) instruction {
+	return func(p *Processor, _ uint64) error {
+		val, err := p.stack().Pull32()
+		if err != nil {
+			return err
+		}
+
+		*reg(&p.regs) = val
+		if flags {
+			p.setZN(uint64(val), register.SignBit32)
+		}
+
+		return nil
+	}
+}
+
+// pullST pulls ST, which sets all flags
+func pullST() instruction {
+	return func(p *Processor, _ uint64) error {
+		val, err := p.stack().Pull32()
+		if err != nil {
+			return err
+		}
+
+		p.regs.SetST(register.StatusRegister(val))
+		return nil
+	}
+}
+
+// pullGeneral pulls all 64 bits of general register w
+func pullGeneral(w int) instruction {
+	return func(p *Processor, _ uint64) error {
+		val, err := p.stack().Pull64()
+		if err != nil {
+			return err
+		}
+
+		p.general(w).SetUint64(val)
+		p.setZN(val, register.SignBit64)
+		return nil
+	}
+}
+
+// subtractSP reserves U8 bytes on the stack
+func subtractSP() instruction {
+	return func(p *Processor, imm uint64) error {
+		return p.stack().Reserve(uint16(imm))
+	}
+}
+
+// returnSubroutine pulls PC. If release is true, U8 bytes are discarded from the stack first.
+// SP is unchanged if the return fails.
+func returnSubroutine(release bool) instruction {
+	return func(p *Processor, imm uint64) error {
+		var (
+			s  = p.stack()
+			sp = p.regs.SP
+		)
+
+		if release {
+			if err := s.Release(uint16(imm)); err != nil {
+				return err
+			}
+		}
+
+		pc, err := s.Pull32()
+		if err != nil {
+			p.regs.SP = sp
+			return err
+		}
+
+		p.regs.PC = pc
+		return nil
+	}
+}
+
+// ==== Status
+
+func clc(st *register.StatusRegister) { st.ClearCarry() }
+func sec(st *register.StatusRegister) { st.SetCarry() }
+func cli(st *register.StatusRegister) { st.ClearInterruptDisable() }
+func sei(st *register.StatusRegister) { st.SetInterruptDisable() }
+
+// status applies f to ST
+func status(f func(st *register.StatusRegister)) instruction {
+	return func(p *Processor, _ uint64) error {
+		st := p.regs.ST()
+		f(&st)
+		p.regs.SetST(st)
+		return nil
+	}
+}
+
+// addressMode selects a code or data address mode
+func addressMode(code bool, mode uint8) instruction {
+	return status(func(st *register.StatusRegister) {
+		st.CodeAddressMode(code)
+		st.SelectAddressMode(mode)
+	})
+}
+
+// operandSize selects an operand size
+func operandSize(size uint8) instruction {
+	return status(func(st *register.StatusRegister) {
+		st.SelectOperandSize(size)
+	})
+}
+
+// mathMode selects a math mode
+func mathMode(mode uint8) instruction {
+	return status(func(st *register.StatusRegister) {
+		st.SelectMathMode(mode)
+	})
+}
+
+// ==== Other
+
+// nop does nothing
+func nop() instruction {
+	return func(*Processor, uint64) error {
+		return nil
+	}
+}
@@ -0,0 +1,662 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cpu
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/bantling/goprocessor/pkg/isa"
+	"github.com/bantling/goprocessor/pkg/register"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecuteALU(t *testing.T) {
+	p := newTestProcessor(
+		0xD0,             // SOS8
+		0xFF, 0x43, 0x7F, // MOV R0,0x7F
+		0xFF, 0x44, 0x01, // MOV R0c,0x01
+		0x02,             // ADD R0,R0c
+		0xFF, 0x20, 0x01, // SUB R0,1
+		0x0A,             // CMP R0,R0c
+		0xBF,             // SEC
+		0x00,             // ADC R0,R0c
+		0x14,             // MULS R0,R0c
+		0xFF, 0x14, 0x01, // SHA R0,1
+	)
+	regs := p.Registers()
+
+	steps(t, p, 4)
+	assert.Equal(t, uint64(0xFFFFFFFFFFFFFF80), regs.R0)
+	assert.True(t, regs.ST().IsOverflow())
+	assert.True(t, regs.ST().IsNegative())
+
+	steps(t, p, 1)
+	assert.Equal(t, uint64(0x7F), regs.R0)
+	assert.True(t, regs.ST().IsOverflow())
+	assert.False(t, regs.ST().IsCarry())
+
+	steps(t, p, 1)
+	assert.True(t, regs.ST().IsCarry())
+	assert.True(t, regs.ST().IsOverflow())
+	assert.False(t, regs.ST().IsZero())
+
+	steps(t, p, 2)
+	assert.Equal(t, uint64(0xFFFFFFFFFFFFFF81), regs.R0)
+
+	steps(t, p, 1)
+	assert.Equal(t, uint64(0xFFFFFFFFFFFFFF81), regs.R0)
+
+	steps(t, p, 1)
+	assert.Equal(t, uint64(0xFFFFFFFFFFFFFFC0), regs.R0)
+}
+
+func TestExecuteUnsigned(t *testing.T) {
+	p := newTestProcessor(
+		0xD0,             // SOS8
+		0xFF, 0x43, 0xFF, // MOV R0,0xFF
+		0xFF, 0x44, 0x10, // MOV R0c,0x10
+		0x16,             // MULU R0,R0c
+		0xFF, 0x43, 0xFF, // MOV R0,0xFF
+		0x12, // DIVU R0,R0c
+	)
+	regs := p.Registers()
+
+	steps(t, p, 4)
+	assert.Equal(t, uint64(0x0FF0), regs.R0)
+	assert.False(t, regs.ST().IsNegative())
+
+	steps(t, p, 2)
+	assert.Equal(t, uint64(0x0F), regs.R0)
+	assert.Equal(t, uint64(0x0F), regs.R1)
+}
+
+func TestExecuteFixed(t *testing.T) {
+	p := newTestProcessor(
+		0xD1,                   // SOS16
+		0xD6,                   // SMMX
+		0xFF, 0x43, 0x00, 0x96, // MOV R0,1.50
+		0xFF, 0x44, 0xFF, 0x1F, // MOV R0c,-2.25
+		0x02, // ADD R0,R0c
+		0x14, // MULS R0,R0c
+		0x10, // DIVS R0,R0c
+		0x0A, // CMP R0,R0c
+	)
+	regs := p.Registers()
+
+	steps(t, p, 5)
+	assert.Equal(t, "-0.75", register.GeneralRegister(regs.R0).FormatFixed(register.Operand16))
+	assert.True(t, regs.ST().IsNegative())
+
+	steps(t, p, 1)
+	assert.Equal(t, "1.69", register.GeneralRegister(regs.R0).FormatFixed(register.Operand16))
+	assert.Equal(t, "-2.25", register.GeneralRegister(regs.R1).FormatFixed(register.Operand16))
+
+	steps(t, p, 1)
+	assert.Equal(t, "-0.75", register.GeneralRegister(regs.R0).FormatFixed(register.Operand16))
+
+	steps(t, p, 1)
+	assert.True(t, regs.ST().IsCarry())
+	assert.False(t, regs.ST().IsZero())
+}
+
+func TestExecuteFixed8(t *testing.T) {
+	// 8 bit fixed point values are 16 bits in immediates, registers, and memory
+	p := newTestProcessor(
+		0xD0,                   // SOS8
+		0xD6,                   // SMMX
+		0xFF, 0x43, 0x00, 0xC8, // MOV R0,2.00
+		0x94, 0x00, 0x00, 0x01, 0x00, // MOV M[0x100],R0
+		0xFF, 0x43, 0x00, 0x00, // MOV R0,0.00
+		0x8F, 0x00, 0x00, 0x01, 0x00, // MOV R0,M[0x100]
+	)
+	regs := p.Registers()
+
+	steps(t, p, 5)
+	val16, _ := p.Bus().Read16(0x100)
+	assert.Equal(t, uint16(0x00C8), val16)
+	assert.Equal(t, uint64(0), regs.R0)
+
+	steps(t, p, 1)
+	assert.Equal(t, register.STOperand8, regs.ST().OperandSize())
+	assert.Equal(t, "2.00", register.GeneralRegister(regs.R0).FormatFixed(register.Operand8))
+}
+
+func TestExecuteFloat8(t *testing.T) {
+	// 8 bit floating point values are half precision in immediates, registers, and memory
+	p := newTestProcessor(
+		0xD0,                   // SOS8
+		0xD7,                   // SMMF
+		0xFF, 0x43, 0x3E, 0x00, // MOV R0,1.5
+		0x94, 0x00, 0x00, 0x01, 0x00, // MOV M[0x100],R0
+		0xFF, 0x43, 0x00, 0x00, // MOV R0,0
+		0x8F, 0x00, 0x00, 0x01, 0x00, // MOV R0,M[0x100]
+	)
+	regs := p.Registers()
+
+	steps(t, p, 5)
+	val16, _ := p.Bus().Read16(0x100)
+	assert.Equal(t, register.HalfBits(1.5), val16)
+	assert.Equal(t, uint64(0), regs.R0)
+
+	steps(t, p, 1)
+	assert.Equal(t, 1.5, register.GeneralRegister(regs.R0).Float(register.Operand8))
+}
+
+func TestExecuteFraction(t *testing.T) {
+	p := newTestProcessor(
+		0xD1,                   // SOS16
+		0xD5,                   // SMMR
+		0xFF, 0x43, 0x01, 0x02, // MOV R0,1/2
+		0xFF, 0x44, 0x01, 0x03, // MOV R0c,1/3
+		0x02,                   // ADD R0,R0c
+		0x14,                   // MULS R0,R0c
+		0xFF, 0x44, 0x00, 0x01, // MOV R0c,0/1
+		0x10, // DIVS R0,R0c
+	)
+	regs := p.Registers()
+
+	steps(t, p, 5)
+	assert.Equal(t, uint64(0x0506), regs.R0)
+
+	steps(t, p, 1)
+	assert.Equal(t, uint64(0x0512), regs.R0)
+	assert.False(t, regs.ST().IsOverflow())
+
+	steps(t, p, 1)
+	assert.True(t, errors.Is(p.Step(), register.ErrDivisionByZero))
+	assert.Equal(t, uint64(0x0512), regs.R0)
+}
+
+func TestExecuteFraction8(t *testing.T) {
+	// 8 bit fractions are 16 bits in immediates, registers, and memory
+	p := newTestProcessor(
+		0xD0,                   // SOS8
+		0xD5,                   // SMMR
+		0xFF, 0x43, 0xFF, 0x03, // MOV R0,-1/3
+		0x94, 0x00, 0x00, 0x01, 0x00, // MOV M[0x100],R0
+		0xFF, 0x43, 0x00, 0x01, // MOV R0,0/1
+		0x8F, 0x00, 0x00, 0x01, 0x00, // MOV R0,M[0x100]
+	)
+	regs := p.Registers()
+
+	steps(t, p, 5)
+	val16, _ := p.Bus().Read16(0x100)
+	assert.Equal(t, uint16(0xFF03), val16)
+	assert.Equal(t, uint64(1), regs.R0)
+
+	steps(t, p, 1)
+	num, den := register.GeneralRegister(regs.R0).Fraction(register.Operand8)
+	assert.Equal(t, int64(-1), num)
+	assert.Equal(t, uint64(3), den)
+}
+
+func TestExecuteFloat(t *testing.T) {
+	p := newTestProcessor(
+		0xD1,                   // SOS16
+		0xD7,                   // SMMF
+		0xFF, 0x43, 0x00, 0x03, // MOV R0,3
+		0xFF, 0x44, 0x00, 0x02, // MOV R0c,2
+		0x3D,                   // FS2F R0
+		0x3E,                   // FS2F R0c
+		0x10,                   // DIVS R0,R0c
+		0x61,                   // FPOW R0,R0c
+		0x65,                   // FSQR R0,R0c
+		0x51,                   // FF2S R0
+		0xFF, 0x44, 0x00, 0x00, // MOV R0c,0
+		0x10, // DIVS R0,R0c
+	)
+	regs := p.Registers()
+
+	steps(t, p, 7)
+	assert.Equal(t, 1.5, register.GeneralRegister(regs.R0).Float(register.Operand16))
+	assert.Equal(t, 2.0, register.GeneralRegister(regs.R1).Float(register.Operand16))
+
+	steps(t, p, 1)
+	assert.Equal(t, 2.25, register.GeneralRegister(regs.R0).Float(register.Operand16))
+	assert.False(t, regs.ST().IsOverflow())
+
+	steps(t, p, 1)
+	assert.Equal(t, 1.4140625, register.GeneralRegister(regs.R0).Float(register.Operand16))
+
+	steps(t, p, 1)
+	assert.Equal(t, uint64(1), regs.R0)
+
+	steps(t, p, 2)
+	assert.True(t, math.IsInf(register.GeneralRegister(regs.R0).Float(register.Operand16), 1))
+	assert.True(t, regs.ST().IsOverflow())
+	assert.False(t, regs.ST().IsCarry())
+}
+
+func TestExecuteIndex16(t *testing.T) {
+	p := newTestProcessor(
+		0x6E, 0xFF, 0xFF, // MOV OFS0,0xFFFF
+		0x04, 0x00, 0x02, // ADD OFS0,2
+		0x26, 0x00, 0x01, // SUB IX0,1
+		0x0E, 0xFF, 0xFF, // CMP IX0,0xFFFF
+	)
+	regs := p.Registers()
+
+	steps(t, p, 1)
+	assert.Equal(t, uint16(0xFFFF), regs.OFS0)
+	assert.True(t, regs.ST().IsNegative())
+
+	steps(t, p, 1)
+	assert.Equal(t, uint16(1), regs.OFS0)
+	assert.True(t, regs.ST().IsCarry())
+	assert.False(t, regs.ST().IsNegative())
+
+	steps(t, p, 1)
+	assert.Equal(t, uint16(0xFFFF), regs.IX0)
+	assert.True(t, regs.ST().IsCarry())
+	assert.True(t, regs.ST().IsNegative())
+
+	steps(t, p, 1)
+	assert.True(t, regs.ST().IsCarry())
+	assert.True(t, regs.ST().IsZero())
+}
+
+func TestExecuteUnary(t *testing.T) {
+	p := newTestProcessor(
+		0xD0,       // SOS8
+		0xE8,       // ONE R0
+		0xDA,       // DEC R0
+		0xDA,       // DEC R0
+		0xDC,       // INC R0
+		0xE0,       // NG1 R0
+		0xDE,       // NEG R0
+		0xE2,       // NOT R0
+		0xBE,       // CLC
+		0xE0,       // NG1 R0
+		0xF2,       // SHA R0
+		0xF4,       // SHL R0
+		0xF6,       // SHR R0
+		0xF8,       // ZRO R0
+		0xFF, 0x58, // INC R1c
+	)
+	regs := p.Registers()
+
+	steps(t, p, 2)
+	assert.Equal(t, uint64(1), regs.R0)
+	assert.False(t, regs.ST().IsZero())
+
+	steps(t, p, 1)
+	assert.Equal(t, uint64(0), regs.R0)
+	assert.False(t, regs.ST().IsCarry())
+	assert.True(t, regs.ST().IsZero())
+
+	steps(t, p, 1)
+	assert.Equal(t, uint64(0xFFFFFFFFFFFFFFFF), regs.R0)
+	assert.True(t, regs.ST().IsCarry())
+	assert.True(t, regs.ST().IsNegative())
+
+	steps(t, p, 1)
+	assert.Equal(t, uint64(0), regs.R0)
+	assert.True(t, regs.ST().IsCarry())
+	assert.True(t, regs.ST().IsZero())
+
+	steps(t, p, 1)
+	assert.Equal(t, uint64(0xFFFFFFFFFFFFFFFF), regs.R0)
+	assert.True(t, regs.ST().IsNegative())
+
+	steps(t, p, 1)
+	assert.Equal(t, uint64(1), regs.R0)
+	assert.False(t, regs.ST().IsNegative())
+
+	steps(t, p, 1)
+	assert.Equal(t, uint64(0xFFFFFFFFFFFFFFFE), regs.R0)
+	assert.True(t, regs.ST().IsNegative())
+
+	// SHA does not change Carry, even though a 1 bit is shifted out
+	steps(t, p, 3)
+	assert.Equal(t, uint64(0xFFFFFFFFFFFFFFFF), regs.R0)
+	assert.False(t, regs.ST().IsCarry())
+
+	// SHL and SHR shift through Carry
+	steps(t, p, 1)
+	assert.Equal(t, uint64(0xFFFFFFFFFFFFFFFE), regs.R0)
+	assert.True(t, regs.ST().IsCarry())
+
+	steps(t, p, 1)
+	assert.Equal(t, uint64(0xFFFFFFFFFFFFFFFF), regs.R0)
+	assert.False(t, regs.ST().IsCarry())
+
+	steps(t, p, 1)
+	assert.Equal(t, uint64(0), regs.R0)
+	assert.True(t, regs.ST().IsZero())
+
+	steps(t, p, 1)
+	assert.Equal(t, uint64(1), regs.R3)
+}
+
+func TestExecuteUnary16(t *testing.T) {
+	p := newTestProcessor(
+		0xD1,             // SOS16
+		0x6F, 0x00, 0x04, // MOV IX0,4
+		0xF0, // PREV IX0
+		0xF0, // PREV IX0
+		0xF0, // PREV IX0
+		0xD3, // SOS64
+		0xE4, // NEXT OFS0
+		0xEB, // ONE OFS1
+		0xFD, // ZRO IX1
+	)
+	regs := p.Registers()
+	regs.IX1 = 5
+
+	steps(t, p, 3)
+	assert.Equal(t, uint16(2), regs.IX0)
+	assert.False(t, regs.ST().IsNegative())
+
+	steps(t, p, 1)
+	assert.Equal(t, uint16(0), regs.IX0)
+	assert.True(t, regs.ST().IsZero())
+
+	// A loop over an array ends when the index becomes negative
+	steps(t, p, 1)
+	assert.Equal(t, uint16(0xFFFE), regs.IX0)
+	assert.True(t, regs.ST().IsCarry())
+	assert.True(t, regs.ST().IsNegative())
+
+	steps(t, p, 2)
+	assert.Equal(t, uint16(8), regs.OFS0)
+	assert.False(t, regs.ST().IsCarry())
+	assert.False(t, regs.ST().IsNegative())
+
+	steps(t, p, 1)
+	assert.Equal(t, uint16(1), regs.OFS1)
+	assert.False(t, regs.ST().IsZero())
+
+	steps(t, p, 1)
+	assert.Equal(t, uint16(0), regs.IX1)
+	assert.True(t, regs.ST().IsZero())
+}
+
+func TestExecuteCounter(t *testing.T) {
+	p := newTestProcessor(
+		0xFF, 0x69, 0x00, 0x00, 0x00, 0x03, // MOV CTR0,3
+		0xDC,             // INC R0
+		0xFF, 0x77, 0xFC, // LOOP CTR0,-4
+		0xFF, 0x6C, 0xFF, 0xFE, // MOV CS1,-2
+		0xFF, 0x74, // STEP CTR1
+		0xFF, 0x6D, 0x00, 0x04, // MOV IS0,4
+		0xFF, 0x75, // STEP IX0
+		0xFF, 0x70, // MOV R0,CTR1
+	)
+	regs := p.Registers()
+	regs.CTR1 = 1
+
+	// The loop body runs 3 times
+	steps(t, p, 7)
+	assert.Equal(t, uint64(3), regs.R0)
+	assert.Equal(t, uint32(0), regs.CTR0)
+	assert.Equal(t, uint32(10), regs.PC)
+	assert.True(t, regs.ST().IsZero())
+
+	steps(t, p, 2)
+	assert.Equal(t, uint16(0xFFFE), regs.CS1)
+	assert.Equal(t, uint32(0xFFFFFFFF), regs.CTR1)
+	assert.True(t, regs.ST().IsNegative())
+
+	steps(t, p, 2)
+	assert.Equal(t, uint16(4), regs.IX0)
+	assert.False(t, regs.ST().IsNegative())
+
+	steps(t, p, 1)
+	assert.Equal(t, uint64(0xFFFFFFFF), regs.R0)
+}
+
+func TestExecuteBranchAndJump(t *testing.T) {
+	p := newTestProcessor(
+		0xBE,       // 0000 CLC
+		0x2A, 0x02, // 0001 BCC +2
+		0xFE,       // 0003 NOP
+		0xFE,       // 0004 NOP
+		0x2B, 0x10, // 0005 BCS +16
+		0x35, 0x00, 0x03, // 0007 JMP +3
+		0xFE, 0xFE, 0xFE, // 000A NOP NOP NOP
+		0x39, 0xFF, 0xF0, // 000D JSR -16
+		0x37, 0x00, 0x02, 0x00, 0x20, // 0010 JSA 0x00020020
+	)
+	regs := p.Registers()
+
+	steps(t, p, 2)
+	assert.Equal(t, uint32(0x0005), regs.PC)
+
+	steps(t, p, 1)
+	assert.Equal(t, uint32(0x0007), regs.PC)
+
+	steps(t, p, 1)
+	assert.Equal(t, uint32(0x000D), regs.PC)
+
+	steps(t, p, 1)
+	assert.Equal(t, uint32(0x0000), regs.PC)
+	assert.Equal(t, uint16(0xFFFB), regs.SP)
+	pushed, _ := p.Bus().Read32(regs.SB + 0xFFFC)
+	assert.Equal(t, uint32(0x0010), pushed)
+
+	regs.PC = 0x0010
+	steps(t, p, 1)
+	assert.Equal(t, uint32(0x0020), regs.PC)
+	assert.Equal(t, uint16(0xFFF7), regs.SP)
+	pushed, _ = p.Bus().Read32(regs.SB + 0xFFF8)
+	assert.Equal(t, uint32(0x0015), pushed)
+}
+
+func TestExecuteMove(t *testing.T) {
+	p := newTestProcessor(
+		0xD1,                               // SOS16
+		0xFF, 0x2F, 0x00, 0x01, 0x00, 0x00, // MOV DP0,0x00010000
+		0xFF, 0x43, 0x80, 0x01, // MOV R0,0x8001
+		0x94, 0x00, 0x00, 0x02, 0x00, // MOV M,R0
+		0xFF, 0x4B, 0x00, 0x00, 0x02, 0x00, // MOV R0c,M
+		0x9C,       // SWP R0,OFS0
+		0x77,       // MOV R0c,R1
+		0xA3,       // PSH R0
+		0xD0,       // SOS8
+		0x8D, 0x08, // MOV R1,*SP[8]
+		0xFF, 0x3C, // MOV R0,ST
+		0xFF, 0x34, // MOV ST,R0
+	)
+	regs := p.Registers()
+
+	steps(t, p, 3)
+	assert.Equal(t, uint32(0x00010000), regs.DP0)
+	assert.Equal(t, uint64(0xFFFFFFFFFFFF8001), regs.R0)
+
+	steps(t, p, 1)
+	val16, _ := p.Bus().Read16(0x00010200)
+	assert.Equal(t, uint16(0x8001), val16)
+
+	steps(t, p, 1)
+	assert.Equal(t, uint64(0xFFFFFFFFFFFF8001), regs.R1)
+	assert.True(t, regs.ST().IsNegative())
+
+	steps(t, p, 1)
+	assert.Equal(t, uint64(0), regs.R0)
+	assert.Equal(t, uint16(0x8001), regs.OFS0)
+	assert.True(t, regs.ST().IsZero())
+
+	regs.R2 = 0x1234
+	steps(t, p, 1)
+	assert.Equal(t, uint64(0x1234), regs.R1)
+
+	regs.R0 = 0x0123456789ABCDEF
+	steps(t, p, 3)
+	assert.Equal(t, uint64(0xFFFFFFFFFFFFFFEF), regs.R2)
+
+	st := regs.ST()
+	steps(t, p, 2)
+	assert.Equal(t, uint64(st), regs.R0)
+	assert.Equal(t, st, regs.ST())
+}
+
+func TestExecuteStatus(t *testing.T) {
+	p := newTestProcessor(
+		0xBF, // SEC
+		0xCD, // SCAM*(*)
+		0xD2, // SOS32
+		0xD6, // SMMX
+		0xD9, // SEI
+		0xC3, // SDAM*([])
+		0xD8, // CLI
+	)
+	regs := p.Registers()
+
+	steps(t, p, 5)
+	st := regs.ST()
+	assert.True(t, st.IsCarry())
+	assert.True(t, st.IsCodeAddressMode())
+	assert.Equal(t, register.PtrPtrOfs, st.AddressMode())
+	assert.Equal(t, register.Operand32, st.OperandSize())
+	assert.Equal(t, register.MathFixed, st.MathMode())
+	assert.True(t, st.IsInterruptDisable())
+
+	steps(t, p, 2)
+	st = regs.ST()
+	assert.False(t, st.IsCodeAddressMode())
+	assert.Equal(t, register.PtrIxOfs, st.AddressMode())
+	assert.False(t, st.IsInterruptDisable())
+}
+
+func TestExecuteIndirect(t *testing.T) {
+	p := newTestProcessor(
+		0xD1,                   // SOS16
+		0xC1,                   // SDAM*()
+		0xFF, 0x43, 0x12, 0x34, // MOV R0,0x1234
+		0x82,                   // MOV *PTR0,R0
+		0xFF, 0x04, 0x00, 0x01, // ADD *PTR0,1
+		0xFF, 0x12, 0x12, 0x35, // CMP *PTR0,0x1235
+		0xFF, 0x47, // MOV R0c,*PTR0
+		0xFF, 0x0A, // ADD *PTR0,R0
+		0xFF, 0x06, // ADD R0,*PTR0
+		0x9B, // SWP R0,*PTR0
+	)
+	regs := p.Registers()
+	regs.DP0 = 0x00010000
+	regs.PTR0 = 0x0100
+	regs.OFS0 = 0x0004
+
+	// *PTR0 is DP0 + PTR0 + OFS0
+	steps(t, p, 4)
+	val16, _ := p.Bus().Read16(0x00010104)
+	assert.Equal(t, uint16(0x1234), val16)
+
+	steps(t, p, 1)
+	val16, _ = p.Bus().Read16(0x00010104)
+	assert.Equal(t, uint16(0x1235), val16)
+
+	// CMP does not write back
+	steps(t, p, 1)
+	val16, _ = p.Bus().Read16(0x00010104)
+	assert.Equal(t, uint16(0x1235), val16)
+	assert.True(t, regs.ST().IsZero())
+
+	steps(t, p, 1)
+	assert.Equal(t, uint64(0x1235), regs.R1)
+
+	steps(t, p, 1)
+	val16, _ = p.Bus().Read16(0x00010104)
+	assert.Equal(t, uint16(0x2469), val16)
+
+	steps(t, p, 1)
+	assert.Equal(t, uint64(0x369D), regs.R0)
+
+	steps(t, p, 1)
+	val16, _ = p.Bus().Read16(0x00010104)
+	assert.Equal(t, uint16(0x369D), val16)
+	assert.Equal(t, uint64(0x2469), regs.R0)
+}
+
+func TestExecuteStack(t *testing.T) {
+	p := newTestProcessor(
+		0xFF, 0x43, 0x80, // 0000 MOV R0,0x80
+		0xA3,       // 0003 PSH R0
+		0xB4,       // 0004 PUL R1c
+		0xA8,       // 0005 PSH PTR0
+		0xB9,       // 0006 PUL DP1
+		0xBD, 0x10, // 0007 SSP 0x10
+		0x39, 0x00, 0x01, // 0009 JSR +1
+		0xFE,       // 000C NOP
+		0xBD, 0x04, // 000D SSP 4
+		0x3B, 0x04, // 000F RTS 4
+	)
+	regs := p.Registers()
+
+	steps(t, p, 3)
+	assert.Equal(t, uint64(0xFFFFFFFFFFFFFF80), regs.R3)
+	assert.Equal(t, register.DefaultSP, regs.SP)
+	assert.True(t, regs.ST().IsNegative())
+
+	regs.DP1 = 0x1234
+	steps(t, p, 2)
+	assert.Equal(t, uint32(0), regs.DP1)
+	assert.Equal(t, register.DefaultSP, regs.SP)
+	assert.True(t, regs.ST().IsZero())
+
+	steps(t, p, 1)
+	assert.Equal(t, uint16(0xFFEF), regs.SP)
+
+	steps(t, p, 1)
+	assert.Equal(t, uint32(0x000D), regs.PC)
+	assert.Equal(t, uint16(0xFFEB), regs.SP)
+
+	steps(t, p, 2)
+	assert.Equal(t, uint32(0x000C), regs.PC)
+	assert.Equal(t, uint16(0xFFEF), regs.SP)
+
+	// PUL ST and PUL CP
+	p = newTestProcessor(0xB0, 0xAF)
+	regs = p.Registers()
+	assert.Nil(t, p.stack().Push32(0x00020000))
+	assert.Nil(t, p.stack().Push32(register.STZeroSet))
+	steps(t, p, 1)
+	assert.Equal(t, register.StatusRegister(register.STZeroSet), regs.ST())
+	steps(t, p, 1)
+	assert.Equal(t, uint32(0x00020000), regs.CP)
+	assert.Equal(t, register.StatusRegister(register.STZeroSet), regs.ST())
+	assert.Equal(t, register.DefaultSP, regs.SP)
+
+	// Underflow leaves PC and SP unchanged
+	p = newTestProcessor(0x3A)
+	err := p.Step()
+	assert.True(t, errors.Is(err, register.ErrStackUnderflow))
+	assert.Equal(t, uint32(0), p.Registers().PC)
+
+	p = newTestProcessor(0x3B, 0x02)
+	p.Registers().SP = 0xFFFB
+	err = p.Step()
+	assert.True(t, errors.Is(err, register.ErrStackUnderflow))
+	assert.Equal(t, uint16(0xFFFB), p.Registers().SP)
+
+	p = newTestProcessor(0xB1)
+	assert.True(t, errors.Is(p.Step(), register.ErrStackUnderflow))
+
+	// Overflow
+	p = newTestProcessor(0xBD, 0x05)
+	p.Registers().SP = 4
+	assert.True(t, errors.Is(p.Step(), register.ErrStackOverflow))
+	assert.Equal(t, uint16(4), p.Registers().SP)
+}
+
+func TestExecuteOpcodesAreValid(t *testing.T) {
+	// Every implemented opcode must be described by isa
+	for page := range pages {
+		for code, ins := range pages[page] {
+			if ins != nil {
+				_, ok := isa.Lookup(uint8(page), uint8(code))
+				assert.True(t, ok, "page %d opcode %02X", page, code)
+			}
+		}
+	}
+
+	// Every opcode described by isa must be implemented, except EXT, which is decoded by Step
+	for page := range pages {
+		for code, ins := range pages[page] {
+			if _, ok := isa.Lookup(uint8(page), uint8(code)); ok && !((page == 0) && (code == 0xFF)) {
+				assert.NotNil(t, ins, "page %d opcode %02X", page, code)
+			}
+		}
+	}
+}
@@ -0,0 +1,284 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cpu
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"sync/atomic"
+
+	"github.com/bantling/gofuncs"
+	"github.com/bantling/goprocessor/pkg/memory"
+	"github.com/bantling/goprocessor/pkg/register"
+)
+
+// Reset/error interrupt codes, which the interrupt routine receives in R0
+const (
+	InterruptReset uint64 = iota
+	InterruptStackOverflow
+	InterruptStackUnderflow
+	InterruptDivisionByZero
+)
+
+const (
+	// IRQLines is the number of external interrupt request lines
+	IRQLines uint8 = 8
+
+	// IRQLineErr if an IRQ line is invalid
+	IRQLineErr = "IRQ line must be < 8"
+)
+
+// errorCodes maps errors to the reset/error interrupt code that handles them
+var errorCodes = []struct {
+	err  error
+	code uint64
+}{
+	{register.ErrStackOverflow, InterruptStackOverflow},
+	{register.ErrStackUnderflow, InterruptStackUnderflow},
+	{register.ErrDivisionByZero, InterruptDivisionByZero},
+}
+
+// IRQError describes an error that occurred entering the routine of an IRQ line
+type IRQError struct {
+	Line uint8
+	Err  error
+}
+
+func (e *IRQError) Error() string {
+	return fmt.Sprintf("IRQ %d: %s", e.Line, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *IRQError) Unwrap() error {
+	return e.Err
+}
+
+// IRQVector returns the address of the 32 bit pointer to the routine of an IRQ line.
+// Panics if line >= IRQLines.
+func IRQVector(line uint8) uint32 {
+	gofuncs.PanicBM(line < IRQLines, IRQLineErr)
+
+	return memory.IRQVectors + 4*uint32(line)
+}
+
+// Raise requests an interrupt on an IRQ line, which remains pending until the processor enters its routine.
+// When more than one line is pending, the lowest numbered line has the highest priority.
+// Raise may be called from any goroutine, such as one that simulates a device.
+// Panics if line >= IRQLines.
+func (p *Processor) Raise(line uint8) {
+	gofuncs.PanicBM(line < IRQLines, IRQLineErr)
+
+	for {
+		old := atomic.LoadUint32(&p.irq)
+		if atomic.CompareAndSwapUint32(&p.irq, old, old|(1<<line)) {
+			return
+		}
+	}
+}
+
+// Pending returns true if an interrupt has been requested on an IRQ line that has not been entered yet.
+// Panics if line >= IRQLines.
+func (p *Processor) Pending(line uint8) bool {
+	gofuncs.PanicBM(line < IRQLines, IRQLineErr)
+
+	return atomic.LoadUint32(&p.irq)&(1<<line) != 0
+}
+
+// Reset executes the reset interrupt, which sets R0 = InterruptReset and enters the routine at *FFFFFFFC.
+// No registers are saved, since a reset does not return.
+func (p *Processor) Reset() error {
+	addr, err := p.bus.Read32(memory.ResetVector)
+	if err != nil {
+		return err
+	}
+
+	p.regs.R0 = InterruptReset
+	p.enter(addr)
+	return nil
+}
+
+// enter begins executing an interrupt routine at addr, with interrupts disabled
+func (p *Processor) enter(addr uint32) {
+	st := p.regs.ST()
+	st.SetInterruptDisable()
+	p.regs.SetST(st)
+
+	p.regs.CP, p.regs.PC = addr, 0
+}
+
+// saveRegisters pushes the registers in the reverse of the order RTI pulls them.
+// SP is unchanged if they do not fit on the stack.
+func (p *Processor) saveRegisters() error {
+	var (
+		s  = p.stack()
+		r  = &p.regs
+		sp = r.SP
+	)
+
+	for _, push := range []func() error{
+		func() error { return s.Push32(r.PC) },
+		func() error { return s.Push32(r.CP) },
+		func() error { return s.Push32(uint32(r.ST())) },
+		func() error { return s.Push16(r.OFS1) },
+		func() error { return s.Push16(r.IX1) },
+		func() error { return s.Push32(r.PTR1) },
+		func() error { return s.Push32(r.DP1) },
+		func() error { return s.Push16(r.OFS0) },
+		func() error { return s.Push16(r.IX0) },
+		func() error { return s.Push32(r.PTR0) },
+		func() error { return s.Push32(r.DP0) },
+		func() error { return s.Push64(r.R3) },
+		func() error { return s.Push64(r.R2) },
+		func() error { return s.Push64(r.R1) },
+		func() error { return s.Push64(r.R0) },
+	} {
+		if err := push(); err != nil {
+			r.SP = sp
+			return err
+		}
+	}
+
+	return nil
+}
+
+// interrupt saves the registers and enters the routine at addr.
+// Returns false if the address is 0, which means no routine is installed.
+func (p *Processor) interrupt(addr uint32) (bool, error) {
+	if addr == 0 {
+		return false, nil
+	}
+
+	if err := p.saveRegisters(); err != nil {
+		return false, err
+	}
+
+	p.enter(addr)
+	return true, nil
+}
+
+// errorInterrupt executes the reset/error interrupt for an error that has an interrupt code, with R0 = the code.
+// Returns false if the error has no code, or no routine is installed at *FFFFFFFC.
+//
+// Error interrupts cannot be disabled. If the registers do not fit on the stack, as can happen for a stack overflow,
+// the routine is entered without saving them, and cannot return with RTI.
+func (p *Processor) errorInterrupt(err error) bool {
+	for _, ec := range errorCodes {
+		if !errors.Is(err, ec.err) {
+			continue
+		}
+
+		addr, rerr := p.bus.Read32(memory.ResetVector)
+		if (rerr != nil) || (addr == 0) {
+			return false
+		}
+
+		// The routine is entered even if the registers cannot be saved
+		_ = p.saveRegisters()
+		p.regs.R0 = ec.code
+		p.enter(addr)
+		return true
+	}
+
+	return false
+}
+
+// serviceIRQ enters the routine of the highest priority pending IRQ line, if interrupts are enabled.
+// A line whose pointer is 0 has no routine, and the request is discarded.
+// If the registers do not fit on the stack, a stack overflow error interrupt occurs instead.
+func (p *Processor) serviceIRQ() (bool, error) {
+	if p.regs.ST().IsInterruptDisable() {
+		return false, nil
+	}
+
+	for {
+		pending := atomic.LoadUint32(&p.irq)
+		if pending == 0 {
+			return false, nil
+		}
+
+		line := uint8(bits.TrailingZeros32(pending))
+		if !atomic.CompareAndSwapUint32(&p.irq, pending, pending&^(1<<line)) {
+			continue
+		}
+
+		addr, err := p.bus.Read32(IRQVector(line))
+		if err != nil {
+			return false, &IRQError{Line: line, Err: err}
+		}
+
+		taken, err := p.interrupt(addr)
+		if (err != nil) && !p.errorInterrupt(err) {
+			return false, &IRQError{Line: line, Err: err}
+		}
+
+		if taken || (err != nil) {
+			return true, nil
+		}
+	}
+}
+
+// returnInterrupt pulls the registers saved by an interrupt.
+// The registers are unchanged if the stack underflows.
+func returnInterrupt() instruction {
+	return func(p *Processor, _ uint64) error {
+		var (
+			s     = p.stack()
+			r     = &p.regs
+			saved = *r
+		)
+
+		for _, pull := range []func() error{
+			pull64(s, &r.R0),
+			pull64(s, &r.R1),
+			pull64(s, &r.R2),
+			pull64(s, &r.R3),
+			pull32(s, &r.DP0),
+			pull32(s, &r.PTR0),
+			pull16(s, &r.IX0),
+			pull16(s, &r.OFS0),
+			pull32(s, &r.DP1),
+			pull32(s, &r.PTR1),
+			pull16(s, &r.IX1),
+			pull16(s, &r.OFS1),
+			func() error {
+				st, err := s.Pull32()
+				r.SetST(register.StatusRegister(st))
+				return err
+			},
+			pull32(s, &r.CP),
+			pull32(s, &r.PC),
+		} {
+			if err := pull(); err != nil {
+				*r = saved
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+// pull16 returns a func that pulls a 16 bit value into val
+func pull16(s *register.Stack, val *uint16) func() error {
+	return func() (err error) {
+		*val, err = s.Pull16()
+		return
+	}
+}
+
+// pull32 returns a func that pulls a 32 bit value into val
+func pull32(s *register.Stack, val *uint32) func() error {
+	return func() (err error) {
+		*val, err = s.Pull32()
+		return
+	}
+}
+
+// pull64 returns a func that pulls a 64 bit value into val
+func pull64(s *register.Stack, val *uint64) func() error {
+	return func() (err error) {
+		*val, err = s.Pull64()
+		return
+	}
+}
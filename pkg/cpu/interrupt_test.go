@@ -0,0 +1,152 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cpu
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bantling/goprocessor/pkg/memory"
+	"github.com/bantling/goprocessor/pkg/register"
+	"github.com/stretchr/testify/assert"
+)
+
+// routine writes the code of an interrupt routine at addr, and stores addr in the pointer at vector
+func routine(p *Processor, vector, addr uint32, code ...byte) {
+	memory.WriteBytes(p.Bus(), addr, code)
+	p.Bus().Write32(vector, addr)
+}
+
+func TestInterruptReset(t *testing.T) {
+	p := newTestProcessor()
+	routine(p, memory.ResetVector, 0x00030000)
+	regs := p.Registers()
+	regs.R0 = 0x1234
+
+	assert.Nil(t, p.Reset())
+	assert.Equal(t, InterruptReset, regs.R0)
+	assert.Equal(t, uint32(0x00030000), regs.CP)
+	assert.Equal(t, uint32(0), regs.PC)
+	assert.Equal(t, register.DefaultSP, regs.SP)
+	assert.True(t, regs.ST().IsInterruptDisable())
+}
+
+func TestInterruptError(t *testing.T) {
+	p := newTestProcessor(
+		0x10, // DIVS R0,R0c
+		0xFE, // NOP
+	)
+	routine(p, memory.ResetVector, 0x00030000, 0x3C) // RTI
+	regs := p.Registers()
+	regs.R0, regs.PTR1, regs.OFS0 = 0x1234, 0x5678, 0x9A
+
+	steps(t, p, 1)
+	assert.Equal(t, InterruptDivisionByZero, regs.R0)
+	assert.Equal(t, uint32(0x00030000), regs.CP)
+	assert.Equal(t, uint32(0), regs.PC)
+	assert.Equal(t, uint16(0xFFBB), regs.SP)
+	assert.True(t, regs.ST().IsInterruptDisable())
+	assert.Equal(t, uint64(1), p.Instructions())
+
+	// RTI resumes at the instruction after DIVS
+	steps(t, p, 1)
+	assert.Equal(t, uint64(0x1234), regs.R0)
+	assert.Equal(t, uint32(0x5678), regs.PTR1)
+	assert.Equal(t, uint16(0x9A), regs.OFS0)
+	assert.Equal(t, uint32(0x00020000), regs.CP)
+	assert.Equal(t, uint32(1), regs.PC)
+	assert.Equal(t, register.DefaultSP, regs.SP)
+	assert.False(t, regs.ST().IsInterruptDisable())
+
+	// Stack underflow, which cannot save the registers if the stack overflows
+	p = newTestProcessor(0xB1) // PUL R0
+	routine(p, memory.ResetVector, 0x00030000)
+	steps(t, p, 1)
+	assert.Equal(t, InterruptStackUnderflow, p.Registers().R0)
+	assert.Equal(t, uint16(0xFFBB), p.Registers().SP)
+
+	p = newTestProcessor(0xBD, 0x10) // SSP 0x10
+	routine(p, memory.ResetVector, 0x00030000)
+	p.Registers().SP = 0x000F
+	steps(t, p, 1)
+	assert.Equal(t, InterruptStackOverflow, p.Registers().R0)
+	assert.Equal(t, uint32(0x00030000), p.Registers().CP)
+	assert.Equal(t, uint16(0x000F), p.Registers().SP)
+}
+
+func TestInterruptIRQ(t *testing.T) {
+	p := newTestProcessor(
+		0xFE, // NOP
+		0xFE, // NOP
+		0xFE, // NOP
+	)
+	routine(p, IRQVector(2), 0x00030000, 0xFE, 0x3C) // NOP, RTI
+	routine(p, IRQVector(5), 0x00040000, 0x3C)       // RTI
+	regs := p.Registers()
+
+	// The lowest line has the highest priority, and disables further interrupts
+	p.Raise(5)
+	p.Raise(2)
+	assert.True(t, p.Pending(2))
+	steps(t, p, 1)
+	assert.Equal(t, uint32(0x00030000), regs.CP)
+	assert.Equal(t, uint32(1), regs.PC)
+	assert.False(t, p.Pending(2))
+	assert.True(t, p.Pending(5))
+
+	steps(t, p, 1)
+	assert.Equal(t, uint32(0x00020000), regs.CP)
+	assert.Equal(t, uint32(0), regs.PC)
+
+	// Line 5 is entered once RTI enables interrupts again
+	steps(t, p, 1)
+	assert.Equal(t, uint32(0x00020000), regs.CP)
+	assert.Equal(t, uint32(0), regs.PC)
+	assert.False(t, p.Pending(5))
+	assert.Equal(t, register.DefaultSP, regs.SP)
+
+	// Disabled interrupts remain pending
+	regs.SetST(register.StatusRegister(register.STInterruptDisableSet))
+	p.Raise(2)
+	steps(t, p, 1)
+	assert.Equal(t, uint32(1), regs.PC)
+	assert.True(t, p.Pending(2))
+
+	// A line with no routine is discarded
+	regs.SetST(0)
+	p.Raise(2)
+	memory.WriteBytes(p.Bus(), IRQVector(0), []byte{0, 0, 0, 0})
+	p.Raise(0)
+	steps(t, p, 1)
+	assert.False(t, p.Pending(0))
+	assert.Equal(t, uint32(0x00030000), regs.CP)
+
+	assert.Panics(t, func() { p.Raise(IRQLines) })
+	assert.Panics(t, func() { IRQVector(IRQLines) })
+	assert.Equal(t, memory.ResetVector-4, IRQVector(IRQLines-1))
+}
+
+func TestInterruptIRQErrors(t *testing.T) {
+	// The registers do not fit and there is no error routine
+	p := newTestProcessor(0xFE)
+	routine(p, IRQVector(3), 0x00030000)
+	p.Registers().SP = 0x0010
+	p.Raise(3)
+
+	err := p.Step()
+	assert.Equal(t, &IRQError{Line: 3, Err: register.ErrStackOverflow}, err)
+	assert.True(t, errors.Is(err, register.ErrStackOverflow))
+	assert.EqualError(t, err, "IRQ 3: Stack Overflow")
+	assert.Equal(t, uint16(0x0010), p.Registers().SP)
+
+	// RTI with too few bytes on the stack leaves the registers unchanged
+	p = newTestProcessor(0x3C)
+	regs := p.Registers()
+	regs.SP = 0xFFF0
+	regs.R0 = 0x1234
+	before := *regs
+
+	err = p.Step()
+	assert.True(t, errors.Is(err, register.ErrStackUnderflow))
+	assert.Equal(t, before, *regs)
+}
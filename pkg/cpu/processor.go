@@ -0,0 +1,310 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cpu
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/bantling/goprocessor/pkg/isa"
+	"github.com/bantling/goprocessor/pkg/memory"
+	"github.com/bantling/goprocessor/pkg/register"
+)
+
+// ProcessorError represents an error decoding an instruction
+type ProcessorError string
+
+func (e ProcessorError) Error() string {
+	return string(e)
+}
+
+const (
+	// ErrIllegalOpcode is returned when an opcode is reserved
+	ErrIllegalOpcode = ProcessorError("Illegal Opcode")
+
+	// ErrUnimplementedOpcode is returned when an opcode is defined but cannot be executed yet
+	ErrUnimplementedOpcode = ProcessorError("Unimplemented Opcode")
+)
+
+// InstructionError describes an error that occurred executing the instruction at CP + PC
+type InstructionError struct {
+	CP     uint32
+	PC     uint32
+	Page   uint8
+	Opcode uint8
+	Err    error
+}
+
+func (e *InstructionError) Error() string {
+	return fmt.Sprintf("%08X+%08X: page %d opcode %02X: %s", e.CP, e.PC, e.Page, e.Opcode, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *InstructionError) Unwrap() error {
+	return e.Err
+}
+
+// State is the execution state of a Processor other than its registers, clock, and memory
+type State struct {
+	Cycles       uint64
+	Instructions uint64
+	IRQ          uint32 // pending IRQ lines
+	Millis       uint64 // clock time of the last tick
+	Expired      uint8  // expired timers whose routines have not been entered
+}
+
+// Processor executes instructions read from a memory.Bus
+type Processor struct {
+	regs         register.Registers
+	bus          memory.Bus
+	cycles       uint64
+	instructions uint64
+	irq          uint32 // pending IRQ lines, accessed atomically
+	clock        Clock
+	millis       uint64 // clock time of the last tick
+	expired      uint8  // expired timers whose routines have not been entered
+}
+
+// New constructs a Processor with registers initialized by register.OfRegisters,
+// and timers that count down a VirtualClock of DefaultCyclesPerMilli
+func New(bus memory.Bus) *Processor {
+	return &Processor{
+		regs:  register.OfRegisters(),
+		bus:   bus,
+		clock: NewVirtualClock(DefaultCyclesPerMilli),
+	}
+}
+
+// Registers returns the processor registers, which may be modified
+func (p *Processor) Registers() *register.Registers {
+	return &p.regs
+}
+
+// Bus returns the memory bus
+func (p *Processor) Bus() memory.Bus {
+	return p.bus
+}
+
+// Cycles returns the number of cycles executed, where each byte of an instruction costs one cycle
+func (p *Processor) Cycles() uint64 {
+	return p.cycles
+}
+
+// Instructions returns the number of instructions executed
+func (p *Processor) Instructions() uint64 {
+	return p.instructions
+}
+
+// State returns the execution state
+func (p *Processor) State() State {
+	return State{
+		Cycles:       p.cycles,
+		Instructions: p.instructions,
+		IRQ:          atomic.LoadUint32(&p.irq),
+		Millis:       p.millis,
+		Expired:      p.expired,
+	}
+}
+
+// SetState sets the execution state, which together with the registers and memory returns the processor to an
+// earlier point of execution
+func (p *Processor) SetState(s State) {
+	p.cycles = s.Cycles
+	p.instructions = s.Instructions
+	atomic.StoreUint32(&p.irq, s.IRQ)
+	p.millis = s.Millis
+	p.expired = s.Expired
+}
+
+// Step services interrupts and then executes one instruction, which is Interrupt followed by Execute.
+//
+// The timers count down first. If interrupts are enabled and a timer has expired or an IRQ line is pending, the
+// routine of the timer or line is entered before fetching the instruction.
+// If the instruction fails with an error that has a reset/error interrupt code and a routine is installed at
+// *FFFFFFFC, the routine is entered with the saved PC addressing the next instruction, and Step returns nil.
+// Otherwise, if an error occurs, PC is restored to the address of the instruction.
+func (p *Processor) Step() error {
+	if _, err := p.Interrupt(); err != nil {
+		return err
+	}
+
+	return p.Execute()
+}
+
+// Interrupt counts down the timers, then enters the routine of an expired timer and of a pending IRQ line, if
+// interrupts are enabled. Returns true if a routine was entered, so that CP + PC is the first instruction of the
+// routine.
+func (p *Processor) Interrupt() (bool, error) {
+	p.tick()
+	timer, err := p.serviceTimer()
+	if err != nil {
+		return timer, err
+	}
+
+	irq, err := p.serviceIRQ()
+	return timer || irq, err
+}
+
+// Execute fetches, decodes, and executes one instruction at CP + PC, without servicing interrupts.
+// Errors are handled as described by Step.
+func (p *Processor) Execute() error {
+	var (
+		pc     = p.regs.PC
+		page   uint8
+		opcode uint8
+		err    error
+	)
+
+	if opcode, err = p.fetch8(); err != nil {
+		p.regs.PC = pc
+		return err
+	}
+
+	if opcode == isa.EXT {
+		page = isa.Page1
+		if opcode, err = p.fetch8(); err != nil {
+			p.regs.PC = pc
+			return err
+		}
+	}
+
+	var (
+		op, valid = isa.Lookup(page, opcode)
+		exec      = pages[page][opcode]
+		imm       uint64
+	)
+
+	if !valid {
+		err = ErrIllegalOpcode
+	} else if exec == nil {
+		err = ErrUnimplementedOpcode
+	} else if imm, err = p.fetchImmediate(op); err == nil {
+		if err = exec(p, imm); (err != nil) && p.errorInterrupt(err) {
+			err = nil
+		}
+	}
+
+	if err != nil {
+		p.regs.PC = pc
+		return &InstructionError{CP: p.regs.CP, PC: pc, Page: page, Opcode: opcode, Err: err}
+	}
+
+	p.instructions++
+	return nil
+}
+
+// Run steps until an instruction fails or the context is done
+func (p *Processor) Run(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		if err := p.Step(); err != nil {
+			return err
+		}
+	}
+}
+
+// fetch8 reads the byte at CP + PC, and increments PC
+func (p *Processor) fetch8() (uint8, error) {
+	val, err := p.bus.Read8(p.regs.CP + p.regs.PC)
+	if err != nil {
+		return 0, err
+	}
+
+	p.regs.PC++
+	p.cycles++
+	return val, nil
+}
+
+// fetch reads size bytes highest byte first at CP + PC, and advances PC
+func (p *Processor) fetch(size int) (uint64, error) {
+	var val uint64
+	for i := 0; i < size; i++ {
+		b, err := p.fetch8()
+		if err != nil {
+			return 0, err
+		}
+
+		val = (val << 8) | uint64(b)
+	}
+
+	return val, nil
+}
+
+// fetchImmediate fetches the immediate operand of an instruction, if it has one.
+// O operands are the effective operand size.
+func (p *Processor) fetchImmediate(op isa.Opcode) (uint64, error) {
+	if imm, ok := op.Immediate(); ok {
+		return p.fetch(imm.Bytes(p.regs.ST().EffectiveOperandSize()))
+	}
+
+	return 0, nil
+}
+
+// general returns a general register in instruction set order: R0, R0c, R1, R1c
+func (p *Processor) general(n int) *register.GeneralRegister {
+	return p.regs.General(uint8(n))
+}
+
+// stack returns the stack described by SB and SP
+func (p *Processor) stack() *register.Stack {
+	return register.NewStack(p.bus, &p.regs)
+}
+
+// readOperand reads a value of the effective operand size, extending the sign
+func (p *Processor) readOperand(addr uint32) (register.GeneralRegister, error) {
+	var val register.GeneralRegister
+
+	switch p.regs.ST().EffectiveOperandSize() {
+	case register.STOperand8:
+		v, err := p.bus.Read8(addr)
+		if err != nil {
+			return 0, err
+		}
+		val.SetUint8(v)
+
+	case register.STOperand16:
+		v, err := p.bus.Read16(addr)
+		if err != nil {
+			return 0, err
+		}
+		val.SetUint16(v)
+
+	case register.STOperand32:
+		v, err := p.bus.Read32(addr)
+		if err != nil {
+			return 0, err
+		}
+		val.SetUint32(v)
+
+	default:
+		v, err := p.bus.Read64(addr)
+		if err != nil {
+			return 0, err
+		}
+		val.SetUint64(v)
+	}
+
+	return val, nil
+}
+
+// writeOperand writes the lowest bits of a value of the effective operand size
+func (p *Processor) writeOperand(addr uint32, val register.GeneralRegister) error {
+	switch p.regs.ST().EffectiveOperandSize() {
+	case register.STOperand8:
+		return p.bus.Write8(addr, val.Uint8())
+
+	case register.STOperand16:
+		return p.bus.Write16(addr, val.Uint16())
+
+	case register.STOperand32:
+		return p.bus.Write32(addr, val.Uint32())
+	}
+
+	return p.bus.Write64(addr, val.Uint64())
+}
@@ -0,0 +1,130 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cpu
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bantling/goprocessor/pkg/memory"
+	"github.com/bantling/goprocessor/pkg/register"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestProcessor constructs a Processor with code loaded at CP = 0x00020000 and PC = 0
+func newTestProcessor(code ...byte) *Processor {
+	var (
+		ram = memory.NewRAM()
+		p   = New(ram)
+	)
+
+	p.Registers().CP = 0x00020000
+	memory.WriteBytes(ram, p.Registers().CP, code)
+
+	return p
+}
+
+// steps executes n instructions, failing if any of them fail
+func steps(t *testing.T, p *Processor, n int) {
+	for i := 0; i < n; i++ {
+		if err := p.Step(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestProcessorNew(t *testing.T) {
+	p := New(memory.NewRAM())
+
+	assert.Equal(t, register.OfRegisters(), *p.Registers())
+	assert.NotNil(t, p.Bus())
+	assert.Equal(t, uint64(0), p.Cycles())
+	assert.Equal(t, uint64(0), p.Instructions())
+}
+
+func TestProcessorStep(t *testing.T) {
+	p := newTestProcessor(
+		0xFE,       // NOP
+		0xFF, 0x43, // EXT MOV R0,O
+		0x05,
+		0xFE, // NOP
+	)
+
+	steps(t, p, 1)
+	assert.Equal(t, uint32(1), p.Registers().PC)
+	assert.Equal(t, uint64(1), p.Cycles())
+	assert.Equal(t, uint64(1), p.Instructions())
+
+	steps(t, p, 1)
+	assert.Equal(t, uint32(4), p.Registers().PC)
+	assert.Equal(t, uint64(5), p.Registers().R0)
+	assert.Equal(t, uint64(4), p.Cycles())
+	assert.Equal(t, uint64(2), p.Instructions())
+}
+
+func TestProcessorStepErrors(t *testing.T) {
+	// Reserved page 1 opcode
+	p := newTestProcessor(0xFE, 0xFF, 0xE0)
+	steps(t, p, 1)
+
+	err := p.Step()
+	assert.Equal(
+		t,
+		&InstructionError{CP: 0x00020000, PC: 1, Page: 1, Opcode: 0xE0, Err: ErrIllegalOpcode},
+		err,
+	)
+	assert.True(t, errors.Is(err, ErrIllegalOpcode))
+	assert.EqualError(t, err, "00020000+00000001: page 1 opcode E0: Illegal Opcode")
+	assert.Equal(t, uint32(1), p.Registers().PC)
+	assert.Equal(t, uint64(1), p.Instructions())
+
+	// Defined but not implemented, which no page 0 or page 1 opcode is anymore, so DEC R0 is removed for the test
+	dec := pages[0][0xDA]
+	defer func() { pages[0][0xDA] = dec }()
+	pages[0][0xDA] = nil
+	p = newTestProcessor(0xDA)
+	err = p.Step()
+	assert.True(t, errors.Is(err, ErrUnimplementedOpcode))
+	assert.Equal(t, uint32(0), p.Registers().PC)
+
+	// Division by zero
+	p = newTestProcessor(0x10)
+	err = p.Step()
+	assert.True(t, errors.Is(err, register.ErrDivisionByZero))
+	assert.Equal(t, uint32(0), p.Registers().PC)
+}
+
+func TestProcessorRun(t *testing.T) {
+	// JMP -3 loops forever
+	p := newTestProcessor(0x35, 0xFF, 0xFD)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Equal(t, context.Canceled, p.Run(ctx))
+	assert.Equal(t, uint64(0), p.Instructions())
+
+	// Run stops at the first error
+	p = newTestProcessor(0xFE, 0xFE, 0xFF, 0xFF)
+	assert.True(t, errors.Is(p.Run(context.Background()), ErrIllegalOpcode))
+	assert.Equal(t, uint64(2), p.Instructions())
+	assert.Equal(t, uint32(2), p.Registers().PC)
+}
+
+func TestProcessorState(t *testing.T) {
+	p := newTestProcessor(0xFE, 0xFE)
+	steps(t, p, 2)
+	p.Raise(3)
+
+	state := p.State()
+	assert.Equal(t, State{Cycles: 2, Instructions: 2, IRQ: 1 << 3}, state)
+
+	p.SetState(State{Cycles: 1, Instructions: 1, Millis: 5, Expired: 2})
+	assert.Equal(t, uint64(1), p.Cycles())
+	assert.Equal(t, uint64(1), p.Instructions())
+	assert.False(t, p.Pending(3))
+	assert.True(t, p.Expired(1))
+
+	p.SetState(state)
+	assert.Equal(t, state, p.State())
+}
@@ -0,0 +1,146 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cpu
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bantling/gofuncs"
+)
+
+const (
+	// Timers is the number of timer registers
+	Timers uint8 = 4
+
+	// TimerErr if a timer is invalid
+	TimerErr = "Timer must be < 4"
+
+	// DefaultCyclesPerMilli is the number of cycles per millisecond of the default virtual clock, a 1 MHz processor
+	DefaultCyclesPerMilli uint64 = 1000
+
+	// CyclesPerMilliErr if a virtual clock has no cycles per millisecond
+	CyclesPerMilliErr = "Cycles per millisecond must be > 0"
+)
+
+// Clock is the source of the time that timers count down
+type Clock interface {
+	// Millis returns the number of milliseconds elapsed since an arbitrary start, given the number of cycles executed
+	Millis(cycles uint64) uint64
+}
+
+// VirtualClock is a Clock derived from the number of cycles executed, so that timers are reproducible
+type VirtualClock struct {
+	cyclesPerMilli uint64
+}
+
+// NewVirtualClock constructs a VirtualClock where each millisecond is cyclesPerMilli cycles.
+// Panics if cyclesPerMilli = 0.
+func NewVirtualClock(cyclesPerMilli uint64) VirtualClock {
+	gofuncs.PanicBM(cyclesPerMilli > 0, CyclesPerMilliErr)
+
+	return VirtualClock{cyclesPerMilli: cyclesPerMilli}
+}
+
+// Millis is cycles / cycles per millisecond
+func (c VirtualClock) Millis(cycles uint64) uint64 {
+	return cycles / c.cyclesPerMilli
+}
+
+// WallClock is a Clock that follows real time, regardless of the number of cycles executed
+type WallClock struct {
+	start time.Time
+}
+
+// NewWallClock constructs a WallClock that starts now
+func NewWallClock() WallClock {
+	return WallClock{start: time.Now()}
+}
+
+// Millis is the number of milliseconds since the clock was constructed
+func (c WallClock) Millis(uint64) uint64 {
+	return uint64(time.Since(c.start).Milliseconds())
+}
+
+// TimerError describes an error that occurred entering the routine of a timer
+type TimerError struct {
+	Timer uint8
+	Err   error
+}
+
+func (e *TimerError) Error() string {
+	return fmt.Sprintf("Timer %d: %s", e.Timer, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *TimerError) Unwrap() error {
+	return e.Err
+}
+
+// SetClock selects the clock that timers count down, which is a VirtualClock of DefaultCyclesPerMilli by default.
+// Time elapsed on the new clock before it is selected is not counted.
+func (p *Processor) SetClock(clock Clock) {
+	p.clock, p.millis = clock, clock.Millis(p.cycles)
+}
+
+// Expired returns true if a timer has reached 0, and its routine has not been entered yet.
+// Panics if n >= Timers.
+func (p *Processor) Expired(n uint8) bool {
+	gofuncs.PanicBM(n < Timers, TimerErr)
+
+	return p.expired&(1<<n) != 0
+}
+
+// tick counts down each running timer by the milliseconds elapsed since the last tick.
+// A timer that is 0 is stopped. A timer that reaches 0 expires, and remains expired until its routine is entered.
+func (p *Processor) tick() {
+	now := p.clock.Millis(p.cycles)
+	elapsed := now - p.millis
+	p.millis = now
+
+	if elapsed == 0 {
+		return
+	}
+
+	for n := uint8(0); n < Timers; n++ {
+		tmr := p.regs.TMR(n)
+		switch {
+		case *tmr == 0:
+		case uint64(*tmr) <= elapsed:
+			*tmr = 0
+			p.expired |= 1 << n
+		default:
+			*tmr -= uint32(elapsed)
+		}
+	}
+}
+
+// serviceTimer enters the TPTR routine of the lowest numbered expired timer, if interrupts are enabled.
+// TPTR is an absolute address, like an IRQ vector, so the routine runs with CP = TPTR regardless of the CP interrupted.
+// Timers have a higher priority than IRQ lines. A timer whose TPTR is 0 has no routine, and the expiry is discarded.
+// If the registers do not fit on the stack, a stack overflow error interrupt occurs instead.
+// Returns true if a routine was entered.
+func (p *Processor) serviceTimer() (bool, error) {
+	if p.regs.ST().IsInterruptDisable() {
+		return false, nil
+	}
+
+	for n := uint8(0); n < Timers; n++ {
+		if p.expired&(1<<n) == 0 {
+			continue
+		}
+
+		p.expired &^= 1 << n
+
+		taken, err := p.interrupt(*p.regs.TPTR(n))
+		if (err != nil) && !p.errorInterrupt(err) {
+			return false, &TimerError{Timer: n, Err: err}
+		}
+
+		if taken || (err != nil) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,109 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cpu
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bantling/goprocessor/pkg/memory"
+	"github.com/bantling/goprocessor/pkg/register"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimerClocks(t *testing.T) {
+	assert.Equal(t, uint64(2), NewVirtualClock(10).Millis(29))
+	assert.Equal(t, uint64(3), NewVirtualClock(10).Millis(30))
+	assert.Panics(t, func() { NewVirtualClock(0) })
+
+	assert.Less(t, NewWallClock().Millis(1000000), uint64(1000))
+}
+
+func TestTimer(t *testing.T) {
+	p := newTestProcessor(0xFE, 0xFE, 0xFE, 0xFE, 0xFE, 0xFE, 0xFE, 0xFE)
+	memory.WriteBytes(p.Bus(), 0x00030000, []byte{0xFE, 0x3C}) // NOP, RTI
+	regs := p.Registers()
+
+	// 1 millisecond = 2 NOPs
+	p.SetClock(NewVirtualClock(2))
+	regs.TMR1, regs.TPTR1 = 2, 0x00030000
+	regs.TMR2 = 100
+
+	steps(t, p, 4)
+	assert.Equal(t, uint32(1), regs.TMR1)
+	assert.Equal(t, uint32(99), regs.TMR2)
+	assert.Equal(t, uint32(0x00020000), regs.CP)
+
+	// The timer expires at the start of the fifth step
+	p.Raise(0)
+	routine(p, IRQVector(0), 0x00040000, 0x3C)
+	steps(t, p, 1)
+	assert.Equal(t, uint32(0), regs.TMR1)
+	assert.False(t, p.Expired(1))
+	assert.Equal(t, uint32(0x00030000), regs.CP)
+	assert.Equal(t, uint32(1), regs.PC)
+	assert.True(t, p.Pending(0))
+
+	// RTI returns to the interrupted code, where the IRQ is entered next
+	steps(t, p, 1)
+	assert.Equal(t, uint32(0x00020000), regs.CP)
+	assert.Equal(t, uint32(4), regs.PC)
+
+	steps(t, p, 1)
+	assert.Equal(t, uint32(0x00020000), regs.CP)
+	assert.Equal(t, uint32(4), regs.PC)
+	assert.False(t, p.Pending(0))
+
+	// A stopped timer remains stopped
+	steps(t, p, 2)
+	assert.Equal(t, uint32(0), regs.TMR1)
+	assert.False(t, p.Expired(1))
+
+	// Expired timers wait for interrupts to be enabled, and a timer with no routine is discarded
+	regs.SetST(register.StatusRegister(register.STInterruptDisableSet))
+	regs.TMR0, regs.TMR3, regs.TPTR3 = 1, 1, 0x00030000
+	p.cycles += 10
+	steps(t, p, 1)
+	assert.True(t, p.Expired(0))
+	assert.True(t, p.Expired(3))
+	assert.Equal(t, uint32(91), regs.TMR2)
+
+	regs.SetST(0)
+	steps(t, p, 1)
+	assert.False(t, p.Expired(0))
+	assert.False(t, p.Expired(3))
+	assert.Equal(t, uint32(0x00030000), regs.CP)
+
+	assert.Panics(t, func() { p.Expired(Timers) })
+}
+
+func TestTimerAbsolute(t *testing.T) {
+	// TPTR is absolute, so a routine below CP is entered at TPTR, not CP + TPTR
+	p := newTestProcessor(0xFE, 0xFE)
+	memory.WriteBytes(p.Bus(), 0x00010000, []byte{0xFE, 0x3C}) // NOP, RTI
+	regs := p.Registers()
+
+	p.SetClock(NewVirtualClock(1))
+	regs.TMR0, regs.TPTR0 = 1, 0x00010000
+
+	steps(t, p, 2)
+	assert.Equal(t, uint32(0x00010000), regs.CP)
+	assert.Equal(t, uint32(1), regs.PC)
+
+	steps(t, p, 1)
+	assert.Equal(t, uint32(0x00020000), regs.CP)
+}
+
+func TestTimerErrors(t *testing.T) {
+	p := newTestProcessor(0xFE)
+	p.SetClock(NewVirtualClock(1))
+	regs := p.Registers()
+	regs.TMR1, regs.TPTR1, regs.SP = 1, 0x00030000, 0x0010
+	p.cycles++
+
+	err := p.Step()
+	assert.Equal(t, &TimerError{Timer: 1, Err: register.ErrStackOverflow}, err)
+	assert.True(t, errors.Is(err, register.ErrStackOverflow))
+	assert.EqualError(t, err, "Timer 1: Stack Overflow")
+	assert.Equal(t, uint16(0x0010), regs.SP)
+}
@@ -0,0 +1,89 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package debug
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/bantling/goprocessor/pkg/register"
+)
+
+// RegisterValue returns the value of a register named by a field of register.Registers, or ST, in any case,
+// and true if the register exists
+func RegisterValue(regs *register.Registers, name string) (uint64, bool) {
+	name = strings.ToUpper(name)
+	if name == "ST" {
+		return uint64(regs.ST()), true
+	}
+
+	if f := reflect.ValueOf(regs).Elem().FieldByName(name); f.IsValid() && f.CanInterface() {
+		return f.Uint(), true
+	}
+
+	return 0, false
+}
+
+// comparisons maps the operators of a Condition to a comparison of a register and a value
+var comparisons = map[string]func(reg, val uint64) bool{
+	"==": func(reg, val uint64) bool { return reg == val },
+	"!=": func(reg, val uint64) bool { return reg != val },
+	"<":  func(reg, val uint64) bool { return reg < val },
+	"<=": func(reg, val uint64) bool { return reg <= val },
+	">":  func(reg, val uint64) bool { return reg > val },
+	">=": func(reg, val uint64) bool { return reg >= val },
+}
+
+// Condition compares a register to a value with one of the operators == != < <= > >=, where registers are unsigned
+type Condition struct {
+	Register string
+	Op       string
+	Value    uint64
+}
+
+// ParseCondition parses a condition written as register, operator, and value separated by spaces, EG R0 == 5.
+// The value may be negative, which is compared as the two's complement.
+func ParseCondition(s string) (*Condition, error) {
+	fields := strings.Fields(s)
+	if len(fields) != 3 {
+		return nil, fmt.Errorf("condition %q must be register, operator, and value", s)
+	}
+
+	if _, ok := RegisterValue(&register.Registers{}, fields[0]); !ok {
+		return nil, fmt.Errorf("unknown register %s", fields[0])
+	}
+
+	if _, ok := comparisons[fields[1]]; !ok {
+		return nil, fmt.Errorf("unknown operator %s", fields[1])
+	}
+
+	val, err := strconv.ParseUint(fields[2], 0, 64)
+	if err != nil {
+		sval, serr := strconv.ParseInt(fields[2], 0, 64)
+		if serr != nil {
+			return nil, fmt.Errorf("invalid value %s", fields[2])
+		}
+
+		val = uint64(sval)
+	}
+
+	return &Condition{Register: strings.ToUpper(fields[0]), Op: fields[1], Value: val}, nil
+}
+
+// IsTrue is true if the condition is true for the registers
+func (c *Condition) IsTrue(regs *register.Registers) bool {
+	reg, _ := RegisterValue(regs, c.Register)
+	return comparisons[c.Op](reg, c.Value)
+}
+
+func (c *Condition) String() string {
+	return fmt.Sprintf("%s %s %d", c.Register, c.Op, c.Value)
+}
+
+// Breakpoint stops before executing the instruction at the absolute address Addr, if Cond is nil or true
+type Breakpoint struct {
+	Addr uint32
+	Cond *Condition
+}
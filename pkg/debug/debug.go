@@ -0,0 +1,274 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package debug
+
+import (
+	"context"
+	"sort"
+
+	"github.com/bantling/goprocessor/pkg/cpu"
+	"github.com/bantling/goprocessor/pkg/disasm"
+	"github.com/bantling/goprocessor/pkg/memory"
+	"github.com/bantling/goprocessor/pkg/register"
+)
+
+// maxInstructionSize is the most bytes an instruction can have: EXT, an opcode, and a 64 bit immediate
+const maxInstructionSize = 10
+
+// StopReason is the reason execution stopped
+type StopReason uint8
+
+const (
+	// StopStep means the requested step completed
+	StopStep StopReason = iota
+
+	// StopBreakpoint means a breakpoint was reached, and the instruction at the breakpoint has not been executed
+	StopBreakpoint
+
+	// StopWatchpoint means the instruction that was just executed made an access a watchpoint matches
+	StopWatchpoint
+)
+
+// stopReasonNames are the names of the reasons for stopping
+var stopReasonNames = [...]string{"step", "breakpoint", "watchpoint"}
+
+func (r StopReason) String() string {
+	return stopReasonNames[r]
+}
+
+// Stop describes why execution stopped.
+// Breakpoint is only set for StopBreakpoint, and Watchpoint and Access are only set for StopWatchpoint.
+type Stop struct {
+	Reason     StopReason
+	Breakpoint Breakpoint
+	Watchpoint Watchpoint
+	Access     Access
+}
+
+// Debugger executes a cpu.Processor with breakpoints and watchpoints
+type Debugger struct {
+	proc        *cpu.Processor
+	bus         *watchBus
+	breakpoints map[uint32]Breakpoint
+	watchpoints []Watchpoint
+	history     *history // nil if not recording
+}
+
+// New constructs a Debugger of a new cpu.Processor that accesses bus
+func New(bus memory.Bus) *Debugger {
+	wb := &watchBus{bus: bus}
+
+	return &Debugger{
+		proc:        cpu.New(wb),
+		bus:         wb,
+		breakpoints: map[uint32]Breakpoint{},
+	}
+}
+
+// Processor returns the processor, whose registers may be modified.
+// The processor bus records accesses for watchpoints, so it must only be stepped by the Debugger.
+func (d *Debugger) Processor() *cpu.Processor {
+	return d.proc
+}
+
+// Bus returns the memory bus, which can be accessed without affecting watchpoints
+func (d *Debugger) Bus() memory.Bus {
+	return d.bus.bus
+}
+
+// Break sets a breakpoint at an absolute address, replacing any breakpoint already at the address.
+// If cond is not nil, the breakpoint only stops when the condition is true.
+func (d *Debugger) Break(addr uint32, cond *Condition) {
+	d.breakpoints[addr] = Breakpoint{Addr: addr, Cond: cond}
+}
+
+// Delete deletes the breakpoint at an absolute address, and returns true if there was one
+func (d *Debugger) Delete(addr uint32) bool {
+	_, ok := d.breakpoints[addr]
+	delete(d.breakpoints, addr)
+	return ok
+}
+
+// Breakpoints returns the breakpoints in order of address
+func (d *Debugger) Breakpoints() []Breakpoint {
+	breakpoints := make([]Breakpoint, 0, len(d.breakpoints))
+	for _, b := range d.breakpoints {
+		breakpoints = append(breakpoints, b)
+	}
+
+	sort.Slice(breakpoints, func(i, j int) bool { return breakpoints[i].Addr < breakpoints[j].Addr })
+	return breakpoints
+}
+
+// Watch adds a watchpoint
+func (d *Debugger) Watch(w Watchpoint) {
+	d.watchpoints = append(d.watchpoints, w)
+}
+
+// Unwatch deletes all watchpoints at an address, and returns true if there were any
+func (d *Debugger) Unwatch(addr uint32) bool {
+	var (
+		kept []Watchpoint
+		ok   bool
+	)
+
+	for _, w := range d.watchpoints {
+		if w.Addr == addr {
+			ok = true
+		} else {
+			kept = append(kept, w)
+		}
+	}
+
+	d.watchpoints = kept
+	return ok
+}
+
+// Remove deletes the first watchpoint equal to w, and returns true if there was one
+func (d *Debugger) Remove(w Watchpoint) bool {
+	for i, e := range d.watchpoints {
+		if e == w {
+			d.watchpoints = append(d.watchpoints[:i:i], d.watchpoints[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+// Watchpoints returns the watchpoints in the order they were added
+func (d *Debugger) Watchpoints() []Watchpoint {
+	return append([]Watchpoint(nil), d.watchpoints...)
+}
+
+// Addr returns the absolute address of the next instruction, CP + PC
+func (d *Debugger) Addr() uint32 {
+	regs := d.proc.Registers()
+	return regs.CP + regs.PC
+}
+
+// Fetch reads the instruction at CP + PC from a bus, and decodes it with the effective operand size of the registers.
+// The address of the instruction is PC, as branch targets are relative to CP. Reading stops at a bus fault, so an
+// instruction that is cut short decodes as invalid.
+func Fetch(bus memory.Bus, regs *register.Registers) disasm.Instruction {
+	code := make([]byte, 0, maxInstructionSize)
+	for i := uint32(0); i < maxInstructionSize; i++ {
+		b, err := bus.Read8(regs.CP + regs.PC + i)
+		if err != nil {
+			break
+		}
+
+		code = append(code, b)
+	}
+
+	return disasm.Decode(code, regs.PC, regs.ST().EffectiveOperandSize())
+}
+
+// Instruction decodes the next instruction with the selected operand size, as described by Fetch
+func (d *Debugger) Instruction() disasm.Instruction {
+	return Fetch(d.bus.bus, d.proc.Registers())
+}
+
+// Step executes one instruction, ignoring breakpoints.
+// If recording, the changes are recorded even if the instruction fails, since it may have written to memory.
+func (d *Debugger) Step() (Stop, error) {
+	var (
+		regs   = d.proc.Registers()
+		before = *regs
+		state  = d.proc.State()
+	)
+
+	d.bus.accesses = d.bus.accesses[:0]
+	err := d.proc.Step()
+	if d.history != nil {
+		d.record(state, &before)
+	}
+
+	if err != nil {
+		return Stop{}, err
+	}
+
+	for _, a := range d.bus.accesses {
+		for _, w := range d.watchpoints {
+			if w.Matches(a) {
+				return Stop{Reason: StopWatchpoint, Watchpoint: w, Access: a}, nil
+			}
+		}
+	}
+
+	return Stop{Reason: StopStep}, nil
+}
+
+// breakpoint returns the breakpoint at CP + PC and true if there is one whose condition is true
+func (d *Debugger) breakpoint() (Breakpoint, bool) {
+	b, ok := d.breakpoints[d.Addr()]
+	if ok && (b.Cond != nil) && !b.Cond.IsTrue(d.proc.Registers()) {
+		ok = false
+	}
+
+	return b, ok
+}
+
+// run steps until a breakpoint or watchpoint stops, the context is done, an instruction fails, or done returns true
+// after executing an instruction. Any breakpoint at the first instruction is ignored, so that running can continue
+// from a breakpoint.
+func (d *Debugger) run(ctx context.Context, done func(ins disasm.Instruction) bool) (Stop, error) {
+	for first := true; ; first = false {
+		select {
+		case <-ctx.Done():
+			return Stop{}, ctx.Err()
+		default:
+		}
+
+		if b, ok := d.breakpoint(); ok && !first {
+			return Stop{Reason: StopBreakpoint, Breakpoint: b}, nil
+		}
+
+		ins := d.Instruction()
+		if stop, err := d.Step(); (err != nil) || (stop.Reason == StopWatchpoint) {
+			return stop, err
+		}
+
+		if (done != nil) && done(ins) {
+			return Stop{Reason: StopStep}, nil
+		}
+	}
+}
+
+// Continue runs until a breakpoint or watchpoint stops, the context is done, or an instruction fails
+func (d *Debugger) Continue(ctx context.Context) (Stop, error) {
+	return d.run(ctx, nil)
+}
+
+// StepOver steps one instruction, unless it is a JSR or JSA, in which case it runs until the subroutine returns to
+// the next instruction with the same CP and SP, which allows for recursion
+func (d *Debugger) StepOver(ctx context.Context) (Stop, error) {
+	ins := d.Instruction()
+	if (ins.Op.Mnemonic != "JSR") && (ins.Op.Mnemonic != "JSA") {
+		return d.Step()
+	}
+
+	var (
+		regs = d.proc.Registers()
+		cp   = regs.CP
+		sp   = regs.SP
+		next = regs.PC + uint32(len(ins.Bytes))
+	)
+
+	return d.run(ctx, func(disasm.Instruction) bool {
+		return (regs.PC == next) && (regs.CP == cp) && (regs.SP == sp)
+	})
+}
+
+// StepOut runs until an RTS returns from the current subroutine, which is when the RTS leaves SP above its current
+// value
+func (d *Debugger) StepOut(ctx context.Context) (Stop, error) {
+	var (
+		regs = d.proc.Registers()
+		sp   = regs.SP
+	)
+
+	return d.run(ctx, func(ins disasm.Instruction) bool {
+		return (ins.Op.Mnemonic == "RTS") && (regs.SP > sp)
+	})
+}
@@ -0,0 +1,217 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package debug
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bantling/goprocessor/pkg/internal/testprog"
+	"github.com/bantling/goprocessor/pkg/register"
+	"github.com/stretchr/testify/assert"
+)
+
+// program calls a subroutine that calls another subroutine, and stores the result
+const program = `
+main:   MOV     R0, 1
+        JSR     twice
+        MOV     M[count], R0
+        MOV     M[count], R0
+done:   NOP
+twice:  JSR     inc
+ret:    JSR     inc
+        RTS
+inc:    INC     R0
+        RTS
+count:  .u8     0
+`
+
+// debugger assembles program at CP 0x1000, and returns a Debugger and the absolute addresses of the labels
+func debugger(t *testing.T) (*Debugger, map[string]uint32) {
+	ram, labels := testprog.Load(t, program, 0x1000)
+
+	d := New(ram)
+	d.Processor().Registers().CP = 0x1000
+	d.Processor().Registers().DP0 = 0x1000
+
+	return d, labels
+}
+
+func TestRegisterValue(t *testing.T) {
+	regs := register.OfRegisters()
+	regs.R2 = 5
+	regs.SP = 7
+	regs.SetST(register.StatusRegister(0x1234))
+
+	for name, val := range map[string]uint64{"r2": 5, "SP": 7, "st": 0x1234} {
+		v, ok := RegisterValue(&regs, name)
+		assert.True(t, ok, name)
+		assert.Equal(t, val, v, name)
+	}
+
+	_, ok := RegisterValue(&regs, "R9")
+	assert.False(t, ok)
+}
+
+func TestParseCondition(t *testing.T) {
+	cond, err := ParseCondition("r0 >= 0x10")
+	assert.Nil(t, err)
+	assert.Equal(t, &Condition{Register: "R0", Op: ">=", Value: 16}, cond)
+	assert.Equal(t, "R0 >= 16", cond.String())
+
+	regs := register.OfRegisters()
+	assert.False(t, cond.IsTrue(&regs))
+	regs.R0 = 16
+	assert.True(t, cond.IsTrue(&regs))
+
+	cond, err = ParseCondition("R1 == -1")
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(0xFFFFFFFFFFFFFFFF), cond.Value)
+
+	for s, msg := range map[string]string{
+		"R0 ==":     `condition "R0 ==" must be register, operator, and value`,
+		"R9 == 1":   "unknown register R9",
+		"R0 =~ 1":   "unknown operator =~",
+		"R0 == one": "invalid value one",
+	} {
+		_, err := ParseCondition(s)
+		assert.Equal(t, msg, err.Error(), s)
+	}
+}
+
+func TestWatchpointMatches(t *testing.T) {
+	w := Watchpoint{Addr: 0x10, Size: 4, Kind: WatchRead}
+	assert.True(t, w.Matches(Access{Addr: 0x13, Size: 1}))
+	assert.True(t, w.Matches(Access{Addr: 0x0C, Size: 8}))
+	assert.False(t, w.Matches(Access{Addr: 0x14, Size: 1}))
+	assert.False(t, w.Matches(Access{Addr: 0x0C, Size: 4}))
+	assert.False(t, w.Matches(Access{Addr: 0x10, Size: 1, Write: true}))
+
+	w.Kind = WatchWrite
+	assert.True(t, w.Matches(Access{Addr: 0x10, Size: 1, Write: true}))
+	assert.False(t, w.Matches(Access{Addr: 0x10, Size: 1}))
+
+	w.Kind = WatchChange
+	assert.True(t, w.Matches(Access{Addr: 0x10, Size: 1, Write: true, Old: 1, New: 2}))
+	assert.False(t, w.Matches(Access{Addr: 0x10, Size: 1, Write: true, Old: 2, New: 2}))
+}
+
+func TestBreakpoints(t *testing.T) {
+	d, labels := debugger(t)
+	ctx := context.Background()
+
+	// Breakpoints are ignored by Step, and at the first instruction of Continue
+	d.Break(labels["inc"], &Condition{Register: "R0", Op: "==", Value: 2})
+	d.Break(labels["main"], nil)
+	assert.Equal(
+		t,
+		[]Breakpoint{{Addr: labels["main"]}, {Addr: labels["inc"], Cond: &Condition{Register: "R0", Op: "==", Value: 2}}},
+		d.Breakpoints(),
+	)
+
+	// The conditional breakpoint stops the second time inc is called
+	stop, err := d.Continue(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, StopBreakpoint, stop.Reason)
+	assert.Equal(t, labels["inc"], stop.Breakpoint.Addr)
+	assert.Equal(t, uint64(2), d.Processor().Registers().R0)
+
+	assert.True(t, d.Delete(labels["inc"]))
+	assert.False(t, d.Delete(labels["inc"]))
+	d.Break(labels["done"], nil)
+
+	stop, err = d.Continue(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, Stop{Reason: StopBreakpoint, Breakpoint: Breakpoint{Addr: labels["done"]}}, stop)
+	assert.Equal(t, labels["done"], d.Addr())
+	assert.Equal(t, "NOP", d.Instruction().Text(nil))
+
+	// The context stops an endless loop
+	d.Processor().Registers().PC = labels["inc"] - 0x1000
+	d.Processor().Registers().SP = register.DefaultSP
+	cctx, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = d.Continue(cctx)
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestWatchpoints(t *testing.T) {
+	d, labels := debugger(t)
+	ctx := context.Background()
+
+	d.Watch(Watchpoint{Addr: labels["count"], Size: 1, Kind: WatchChange})
+	d.Watch(Watchpoint{Addr: labels["inc"], Size: 1, Kind: WatchRead})
+	assert.Equal(t, 2, len(d.Watchpoints()))
+
+	// Fetching inc is a read
+	stop, err := d.Continue(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, StopWatchpoint, stop.Reason)
+	assert.Equal(t, Access{Addr: labels["inc"], Size: 1, New: 0xDC}, stop.Access)
+	assert.Equal(t, labels["inc"]+1, d.Addr())
+
+	assert.True(t, d.Unwatch(labels["inc"]))
+	assert.False(t, d.Unwatch(labels["inc"]))
+
+	w := Watchpoint{Addr: labels["count"], Size: 2, Kind: WatchRead}
+	d.Watch(w)
+	assert.False(t, d.Remove(Watchpoint{Addr: labels["count"], Size: 1, Kind: WatchRead}))
+	assert.True(t, d.Remove(w))
+	assert.Equal(t, []Watchpoint{{Addr: labels["count"], Size: 1, Kind: WatchChange}}, d.Watchpoints())
+
+	// The first store changes count, and the second does not
+	stop, err = d.Continue(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, StopWatchpoint, stop.Reason)
+	assert.Equal(t, Watchpoint{Addr: labels["count"], Size: 1, Kind: WatchChange}, stop.Watchpoint)
+	assert.Equal(t, Access{Addr: labels["count"], Size: 1, Write: true, Old: 0, New: 3}, stop.Access)
+
+	stop, err = d.Step()
+	assert.Nil(t, err)
+	assert.Equal(t, Stop{Reason: StopStep}, stop)
+	assert.Equal(t, labels["done"], d.Addr())
+}
+
+func TestStepping(t *testing.T) {
+	d, labels := debugger(t)
+	ctx := context.Background()
+
+	// Step over a non-subroutine is a step
+	stop, err := d.StepOver(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, StopStep, stop.Reason)
+	assert.Equal(t, "JSR    twice", d.Instruction().Text(map[uint32]string{labels["twice"] - 0x1000: "twice"}))
+
+	// Step into twice, then over the first call to inc
+	_, err = d.Step()
+	assert.Nil(t, err)
+	assert.Equal(t, labels["twice"], d.Addr())
+
+	_, err = d.StepOver(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, labels["ret"], d.Addr())
+	assert.Equal(t, uint64(2), d.Processor().Registers().R0)
+
+	// Step into the second call to inc, then out of inc and out of twice
+	_, err = d.Step()
+	assert.Nil(t, err)
+	assert.Equal(t, labels["inc"], d.Addr())
+
+	_, err = d.StepOut(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, labels["ret"]+3, d.Addr())
+
+	_, err = d.StepOut(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, labels["main"]+6, d.Addr())
+	assert.Equal(t, uint64(3), d.Processor().Registers().R0)
+	assert.Equal(t, register.DefaultSP, d.Processor().Registers().SP)
+
+	// A breakpoint in a subroutine stops step over
+	d.Processor().Registers().PC = labels["main"] + 3 - 0x1000
+	d.Break(labels["inc"], nil)
+	stop, err = d.StepOver(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, StopBreakpoint, stop.Reason)
+	assert.Equal(t, labels["inc"], d.Addr())
+}
@@ -0,0 +1,15 @@
+// Package debug controls the execution of a cpu.Processor with breakpoints, watchpoints, and stepping.
+//
+// Breakpoints stop before executing the instruction at an absolute address (CP + PC), optionally only when a
+// condition on a register is true. Watchpoints stop after executing an instruction that reads, writes, or changes
+// memory. Reads include the instruction fetches of the processor.
+//
+// Step over runs a JSR or JSA until the subroutine returns, and step out runs until the RTS of the current
+// subroutine, both of which follow the stack discipline of JSR and RTS: a return pulls the address pushed by the
+// call, leaving SP higher than it was in the subroutine.
+//
+// While recording, the registers, flags, and memory each instruction changes are kept in a ring buffer of the most
+// recent instructions, so that execution can step back, go back to the last write of an address, or rewind to a
+// cycle. Going back undoes the changes, and executing again records them again.
+// SPDX-License-Identifier: Apache-2.0
+package debug
@@ -0,0 +1,254 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package debug
+
+import (
+	"reflect"
+
+	"github.com/bantling/goprocessor/pkg/cpu"
+	"github.com/bantling/goprocessor/pkg/memory"
+	"github.com/bantling/goprocessor/pkg/register"
+)
+
+// DebugError represents an error moving backwards through the history
+type DebugError string
+
+func (e DebugError) Error() string {
+	return string(e)
+}
+
+const (
+	// ErrHistoryEmpty is returned when there is no recorded instruction to step back over
+	ErrHistoryEmpty = DebugError("History Empty")
+
+	// ErrNotRecorded is returned when the point to move back to is older than the history
+	ErrNotRecorded = DebugError("Not Recorded")
+)
+
+// RegisterChange is a register that an instruction changed, named by its field of register.Registers
+type RegisterChange struct {
+	Name string
+	Old  uint64
+	New  uint64
+}
+
+// Delta is what one instruction changed, which is enough to undo it
+type Delta struct {
+	// State is the state of the processor before the instruction, where State.Cycles is the cycle it began at
+	State cpu.State
+
+	// Registers are the registers that changed, other than ST
+	Registers []RegisterChange
+
+	// OldST and NewST are the status register before and after the instruction
+	OldST register.StatusRegister
+	NewST register.StatusRegister
+
+	// Writes are the memory writes in the order they were made
+	Writes []Access
+}
+
+// history is a ring buffer of the most recent deltas
+type history struct {
+	deltas []Delta
+	start  int
+	n      int
+}
+
+// push adds a delta, discarding the oldest if the buffer is full
+func (h *history) push(delta Delta) {
+	if h.n < len(h.deltas) {
+		h.deltas[(h.start+h.n)%len(h.deltas)] = delta
+		h.n++
+		return
+	}
+
+	h.deltas[h.start] = delta
+	h.start = (h.start + 1) % len(h.deltas)
+}
+
+// at returns the ith delta, where 0 is the oldest
+func (h *history) at(i int) Delta {
+	return h.deltas[(h.start+i)%len(h.deltas)]
+}
+
+// pop removes and returns the newest delta
+func (h *history) pop() Delta {
+	h.n--
+	delta := h.at(h.n)
+	h.deltas[(h.start+h.n)%len(h.deltas)] = Delta{}
+	return delta
+}
+
+// RegisterChanges returns the registers other than ST that differ, in the order of the fields of register.Registers
+func RegisterChanges(before, after *register.Registers) []RegisterChange {
+	var (
+		changes []RegisterChange
+		b       = reflect.ValueOf(before).Elem()
+		a       = reflect.ValueOf(after).Elem()
+		typ     = b.Type()
+	)
+
+	for i := 0; i < typ.NumField(); i++ {
+		if f := typ.Field(i); f.PkgPath == "" {
+			if was, is := b.Field(i).Uint(), a.Field(i).Uint(); was != is {
+				changes = append(changes, RegisterChange{Name: f.Name, Old: was, New: is})
+			}
+		}
+	}
+
+	return changes
+}
+
+// write writes a value of size bytes
+func write(bus memory.Bus, addr uint32, size uint32, val uint64) error {
+	switch size {
+	case 1:
+		return bus.Write8(addr, uint8(val))
+	case 2:
+		return bus.Write16(addr, uint16(val))
+	case 4:
+		return bus.Write32(addr, uint32(val))
+	}
+
+	return bus.Write64(addr, val)
+}
+
+// Record starts recording the changes made by each instruction that is executed, keeping the most recent n.
+// Any history already recorded is discarded, and recording stops if n is 0.
+func (d *Debugger) Record(n int) {
+	d.history = nil
+	if n > 0 {
+		d.history = &history{deltas: make([]Delta, n)}
+	}
+}
+
+// Forget discards any history already recorded, and continues recording as many instructions as before.
+// Call it after replacing the state of the processor or memory, which the history cannot undo.
+func (d *Debugger) Forget() {
+	if d.history != nil {
+		d.Record(len(d.history.deltas))
+	}
+}
+
+// History returns the recorded deltas, oldest first
+func (d *Debugger) History() []Delta {
+	if d.history == nil {
+		return nil
+	}
+
+	deltas := make([]Delta, d.history.n)
+	for i := range deltas {
+		deltas[i] = d.history.at(i)
+	}
+
+	return deltas
+}
+
+// record records the changes made by the instruction just executed, given the state and registers before it
+func (d *Debugger) record(state cpu.State, before *register.Registers) {
+	var (
+		after  = d.proc.Registers()
+		writes []Access
+	)
+
+	for _, a := range d.bus.accesses {
+		if a.Write {
+			writes = append(writes, a)
+		}
+	}
+
+	d.history.push(Delta{
+		State:     state,
+		Registers: RegisterChanges(before, after),
+		OldST:     before.ST(),
+		NewST:     after.ST(),
+		Writes:    writes,
+	})
+}
+
+// undo undoes the newest delta
+func (d *Debugger) undo() error {
+	var (
+		delta = d.history.pop()
+		regs  = d.proc.Registers()
+		v     = reflect.ValueOf(regs).Elem()
+	)
+
+	for i := len(delta.Writes) - 1; i >= 0; i-- {
+		w := delta.Writes[i]
+		if err := write(d.bus.bus, w.Addr, w.Size, w.Old); err != nil {
+			return err
+		}
+	}
+
+	for _, c := range delta.Registers {
+		v.FieldByName(c.Name).SetUint(c.Old)
+	}
+
+	regs.SetST(delta.OldST)
+	d.proc.SetState(delta.State)
+	return nil
+}
+
+// StepBack undoes the most recently executed instruction.
+// Returns ErrHistoryEmpty if no instructions are recorded.
+func (d *Debugger) StepBack() error {
+	if (d.history == nil) || (d.history.n == 0) {
+		return ErrHistoryEmpty
+	}
+
+	return d.undo()
+}
+
+// BackToWrite undoes instructions up to and including the most recent one that wrote to the byte at addr, so that
+// the next instruction makes the write.
+// Returns ErrNotRecorded without undoing anything if no recorded instruction wrote to addr.
+func (d *Debugger) BackToWrite(addr uint32) error {
+	if d.history == nil {
+		return ErrNotRecorded
+	}
+
+	w := Watchpoint{Addr: addr, Size: 1, Kind: WatchWrite}
+	for i := d.history.n - 1; i >= 0; i-- {
+		for _, a := range d.history.at(i).Writes {
+			if w.Matches(a) {
+				return d.undoTo(i)
+			}
+		}
+	}
+
+	return ErrNotRecorded
+}
+
+// Rewind undoes instructions until the processor is at the beginning of the instruction that was executing at a
+// cycle, or that began at the cycle.
+// Returns ErrNotRecorded without undoing anything if the cycle is before the oldest recorded instruction, and does
+// nothing if the cycle has not been reached.
+func (d *Debugger) Rewind(cycle uint64) error {
+	if cycle >= d.proc.Cycles() {
+		return nil
+	}
+
+	if (d.history == nil) || (d.history.n == 0) || (d.history.at(0).State.Cycles > cycle) {
+		return ErrNotRecorded
+	}
+
+	i := d.history.n - 1
+	for d.history.at(i).State.Cycles > cycle {
+		i--
+	}
+
+	return d.undoTo(i)
+}
+
+// undoTo undoes the deltas from the newest down to and including the ith
+func (d *Debugger) undoTo(i int) error {
+	for d.history.n > i {
+		if err := d.undo(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,131 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package debug
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bantling/goprocessor/pkg/cpu"
+	"github.com/bantling/goprocessor/pkg/register"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistoryRing(t *testing.T) {
+	h := &history{deltas: make([]Delta, 2)}
+	for c := uint64(1); c <= 3; c++ {
+		h.push(Delta{State: cpu.State{Cycles: c}})
+	}
+
+	assert.Equal(t, 2, h.n)
+	assert.Equal(t, uint64(2), h.at(0).State.Cycles)
+	assert.Equal(t, uint64(3), h.pop().State.Cycles)
+	assert.Equal(t, uint64(2), h.pop().State.Cycles)
+	assert.Equal(t, 0, h.n)
+}
+
+func TestStepBack(t *testing.T) {
+	d, labels := debugger(t)
+	regs := d.Processor().Registers()
+
+	assert.Equal(t, ErrHistoryEmpty, d.StepBack())
+	assert.Nil(t, d.History())
+
+	// Run to done, recording every instruction, starting with Zero set, which MOV R0, 1 clears
+	st := regs.ST()
+	st.SetZero()
+	regs.SetST(st)
+
+	d.Record(100)
+	d.Break(labels["done"], nil)
+	_, err := d.Continue(context.Background())
+	assert.Nil(t, err)
+
+	var (
+		start = register.OfRegisters()
+		end   = *regs
+		count = d.Bus()
+	)
+	start.CP, start.DP0 = 0x1000, 0x1000
+	start.SetST(st)
+
+	val, _ := count.Read8(labels["count"])
+	assert.Equal(t, uint8(3), val)
+	assert.Equal(t, 11, len(d.History()))
+
+	// The first instruction changes the flags, and the first store changes memory
+	mov := d.History()[0]
+	assert.True(t, mov.OldST.IsZero())
+	assert.False(t, mov.NewST.IsZero())
+
+	store := d.History()[9]
+	assert.Equal(t, cpu.State{Cycles: 17, Instructions: 9}, store.State)
+	assert.Equal(t, []RegisterChange{{Name: "PC", Old: 6, New: 11}}, store.Registers)
+	assert.Equal(t, []Access{{Addr: labels["count"], Size: 1, Write: true, Old: 0, New: 3}}, store.Writes)
+
+	// Step back over the second store, then all the way back to the start
+	assert.Nil(t, d.StepBack())
+	assert.Equal(t, uint64(22), d.Processor().Cycles())
+
+	for d.StepBack() == nil {
+	}
+
+	assert.Equal(t, start, *regs)
+	assert.Equal(t, cpu.State{}, d.Processor().State())
+	val, _ = count.Read8(labels["count"])
+	assert.Equal(t, uint8(0), val)
+
+	// Executing again reaches the same state
+	_, err = d.Continue(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, end, *regs)
+}
+
+func TestBackToWriteAndRewind(t *testing.T) {
+	d, labels := debugger(t)
+	regs := d.Processor().Registers()
+	ctx := context.Background()
+
+	assert.Equal(t, ErrNotRecorded, d.BackToWrite(labels["count"]))
+
+	d.Record(100)
+	d.Break(labels["done"], nil)
+	_, err := d.Continue(ctx)
+	assert.Nil(t, err)
+
+	// Back to the second store, which wrote the same value, so the first store is not undone
+	assert.Nil(t, d.BackToWrite(labels["count"]))
+	assert.Equal(t, labels["main"]+11, d.Addr())
+	val, _ := d.Bus().Read8(labels["count"])
+	assert.Equal(t, uint8(3), val)
+	assert.Equal(t, ErrNotRecorded, d.BackToWrite(labels["done"]))
+
+	// Rewind to cycles that have not been reached, in the middle of the first store, and in the middle of the first
+	// instruction
+	assert.Nil(t, d.Rewind(22))
+	assert.Equal(t, labels["main"]+11, d.Addr())
+	assert.Nil(t, d.Rewind(19))
+	assert.Equal(t, labels["main"]+6, d.Addr())
+	val, _ = d.Bus().Read8(labels["count"])
+	assert.Equal(t, uint8(0), val)
+	assert.Nil(t, d.Rewind(2))
+	assert.Equal(t, labels["main"], d.Addr())
+	assert.Equal(t, uint64(0), regs.R0)
+	assert.Equal(t, 0, len(d.History()))
+
+	// A full ring buffer has forgotten the oldest instructions
+	d.Record(2)
+	_, err = d.Continue(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(d.History()))
+	assert.Equal(t, ErrNotRecorded, d.Rewind(0))
+	assert.Equal(t, labels["done"], d.Addr())
+
+	// Forgetting keeps recording with the same capacity
+	d.Forget()
+	assert.Equal(t, 0, len(d.History()))
+	for i := 0; i < 3; i++ {
+		d.Step()
+	}
+	assert.Equal(t, 2, len(d.History()))
+}
@@ -0,0 +1,129 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package debug
+
+import (
+	"github.com/bantling/goprocessor/pkg/memory"
+)
+
+// WatchKind is the kind of memory access a watchpoint stops on
+type WatchKind uint8
+
+const (
+	// WatchRead stops on a read
+	WatchRead WatchKind = iota
+
+	// WatchWrite stops on a write
+	WatchWrite
+
+	// WatchChange stops on a write that changes the value
+	WatchChange
+)
+
+// watchKindNames are the names of the kinds of watchpoints
+var watchKindNames = [...]string{"read", "write", "change"}
+
+func (k WatchKind) String() string {
+	return watchKindNames[k]
+}
+
+// Access is a memory access made by an instruction.
+// Old is the value before a write, and New is the value read or written.
+type Access struct {
+	Addr  uint32
+	Size  uint32
+	Write bool
+	Old   uint64
+	New   uint64
+}
+
+// Watchpoint watches Size bytes of memory at Addr for a kind of access
+type Watchpoint struct {
+	Addr uint32
+	Size uint32
+	Kind WatchKind
+}
+
+// Matches is true if an access is the kind the watchpoint watches, and overlaps the memory it watches
+func (w Watchpoint) Matches(a Access) bool {
+	var (
+		offset  = a.Addr - w.Addr
+		overlap = (uint64(offset) < uint64(w.Size)) || (uint64(w.Addr-a.Addr) < uint64(a.Size))
+	)
+
+	switch {
+	case !overlap:
+		return false
+	case w.Kind == WatchRead:
+		return !a.Write
+	case w.Kind == WatchWrite:
+		return a.Write
+	}
+
+	return a.Write && (a.Old != a.New)
+}
+
+// watchBus is a memory.Bus that records the accesses made to another bus
+type watchBus struct {
+	bus      memory.Bus
+	accesses []Access
+}
+
+func (b *watchBus) read(addr uint32, size uint32, val uint64, err error) (uint64, error) {
+	if err == nil {
+		b.accesses = append(b.accesses, Access{Addr: addr, Size: size, New: val})
+	}
+
+	return val, err
+}
+
+func (b *watchBus) write(addr uint32, size uint32, old uint64, val uint64, err error) error {
+	if err == nil {
+		b.accesses = append(b.accesses, Access{Addr: addr, Size: size, Write: true, Old: old, New: val})
+	}
+
+	return err
+}
+
+func (b *watchBus) Read8(addr uint32) (uint8, error) {
+	val, err := b.bus.Read8(addr)
+	_, err = b.read(addr, 1, uint64(val), err)
+	return val, err
+}
+
+func (b *watchBus) Read16(addr uint32) (uint16, error) {
+	val, err := b.bus.Read16(addr)
+	_, err = b.read(addr, 2, uint64(val), err)
+	return val, err
+}
+
+func (b *watchBus) Read32(addr uint32) (uint32, error) {
+	val, err := b.bus.Read32(addr)
+	_, err = b.read(addr, 4, uint64(val), err)
+	return val, err
+}
+
+func (b *watchBus) Read64(addr uint32) (uint64, error) {
+	val, err := b.bus.Read64(addr)
+	return b.read(addr, 8, val, err)
+}
+
+func (b *watchBus) Write8(addr uint32, val uint8) error {
+	old, _ := b.bus.Read8(addr)
+	return b.write(addr, 1, uint64(old), uint64(val), b.bus.Write8(addr, val))
+}
+
+func (b *watchBus) Write16(addr uint32, val uint16) error {
+	old, _ := b.bus.Read16(addr)
+	return b.write(addr, 2, uint64(old), uint64(val), b.bus.Write16(addr, val))
+}
+
+func (b *watchBus) Write32(addr uint32, val uint32) error {
+	old, _ := b.bus.Read32(addr)
+	return b.write(addr, 4, uint64(old), uint64(val), b.bus.Write32(addr, val))
+}
+
+func (b *watchBus) Write64(addr uint32, val uint64) error {
+	old, _ := b.bus.Read64(addr)
+	return b.write(addr, 8, old, val, b.bus.Write64(addr, val))
+}
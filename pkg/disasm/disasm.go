@@ -0,0 +1,295 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package disasm
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/bantling/goprocessor/pkg/isa"
+	"github.com/bantling/goprocessor/pkg/register"
+)
+
+// operandSizes maps the instructions that select an operand size to the size
+var operandSizes = map[string]uint8{
+	"SOS8":  register.Operand8,
+	"SOS16": register.Operand16,
+	"SOS32": register.Operand32,
+	"SOS64": register.Operand64,
+}
+
+// mathModes maps the instructions that select a math mode to the mode
+var mathModes = map[string]uint8{
+	"SMMI": register.MathInteger,
+	"SMMR": register.MathFractional,
+	"SMMX": register.MathFixed,
+	"SMMF": register.MathFloat,
+}
+
+// Instruction is a decoded instruction, or bytes that are not a valid instruction
+type Instruction struct {
+	// Addr is the address of the first byte
+	Addr uint32
+
+	// Bytes are the encoded bytes
+	Bytes []byte
+
+	// Op is the opcode, which is not valid if the bytes are not an instruction
+	Op isa.Opcode
+
+	// Imm is the value of the immediate operand, sign extended if it is signed
+	Imm uint64
+
+	// Size is the effective operand size the instruction was decoded with
+	Size uint8
+}
+
+// Target returns the address a branch or jump goes to, and true if the instruction is a branch or jump with an
+// immediate operand. The address of an absolute jump is only meaningful if the code is located at its address.
+func (ins Instruction) Target() (uint32, bool) {
+	if !ins.Op.IsValid() || (ins.Op.Group != isa.GroupBranch) {
+		return 0, false
+	}
+
+	imm, ok := ins.Op.Immediate()
+	switch {
+	case !ok:
+		return 0, false
+	case imm.IsSigned():
+		return ins.Addr + uint32(len(ins.Bytes)) + uint32(ins.Imm), true
+	case imm == isa.OperandU32:
+		return uint32(ins.Imm), true
+	}
+
+	// RTS U8
+	return 0, false
+}
+
+// Decode decodes the instruction at the start of code, which is located at addr, given the effective operand size
+// (register.EffectiveOperandSize).
+// If code does not begin with a valid instruction, the instruction is not valid and contains the first byte, or the
+// first two bytes if the first is EXT followed by a reserved or truncated page 1 instruction.
+func Decode(code []byte, addr uint32, size uint8) Instruction {
+	ins := Instruction{Addr: addr, Size: size}
+	if len(code) == 0 {
+		return ins
+	}
+
+	var (
+		page   = isa.Page0
+		opcode = code[0]
+		n      = 1
+	)
+
+	if (opcode == isa.EXT) && (len(code) > 1) {
+		page, opcode, n = isa.Page1, code[1], 2
+	}
+
+	// EXT is only an instruction as part of a page 1 instruction
+	op, ok := isa.Lookup(page, opcode)
+	if !ok || (op.Bytes(size) > len(code)) || ((page == isa.Page0) && (opcode == isa.EXT)) {
+		ins.Bytes = code[:n]
+		return ins
+	}
+
+	ins.Op, ins.Bytes = op, code[:op.Bytes(size)]
+	if imm, ok := op.Immediate(); ok {
+		for _, b := range ins.Bytes[n:] {
+			ins.Imm = (ins.Imm << 8) | uint64(b)
+		}
+
+		switch imm {
+		case isa.OperandS8:
+			ins.Imm = uint64(int8(ins.Imm))
+		case isa.OperandS16:
+			ins.Imm = uint64(int16(ins.Imm))
+		}
+	}
+
+	return ins
+}
+
+// Disassemble decodes all of code, which is located at origin, starting with the given operand size and integer math.
+// The operand size and math mode are tracked by following SOSn and SMMx instructions in order, as the assembler does.
+func Disassemble(code []byte, origin uint32, size uint8) []Instruction {
+	var (
+		instructions []Instruction
+		addr         = origin
+		mode         = register.MathInteger
+	)
+
+	for len(code) > 0 {
+		ins := Decode(code, addr, register.EffectiveOperandSize(size, mode))
+		instructions = append(instructions, ins)
+
+		if s, ok := operandSizes[ins.Op.Mnemonic]; ok {
+			size = s
+		}
+
+		if m, ok := mathModes[ins.Op.Mnemonic]; ok {
+			mode = m
+		}
+
+		code = code[len(ins.Bytes):]
+		addr += uint32(len(ins.Bytes))
+	}
+
+	return instructions
+}
+
+// Names maps addresses to symbol names used as labels
+type Names map[uint32]string
+
+// sortedAddrs returns the addresses of names in order
+func sortedAddrs(names Names) []uint32 {
+	addrs := make([]uint32, 0, len(names))
+	for addr := range names {
+		addrs = append(addrs, addr)
+	}
+
+	sort.Slice(addrs, func(i, j int) bool { return addrs[i] < addrs[j] })
+	return addrs
+}
+
+// isName is true if a symbol name is an identifier that is not a register name
+func isName(name string) bool {
+	for i, c := range name {
+		if !((c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (c == '_') || ((i > 0) && (c >= '0' && c <= '9'))) {
+			return false
+		}
+	}
+
+	for o := isa.OperandR0; o < isa.OperandIndirectPTR0; o++ {
+		if strings.EqualFold(name, o.String()) {
+			return false
+		}
+	}
+
+	return name != ""
+}
+
+// operandText returns an operand in assembler syntax
+func operandText(ins Instruction, o isa.Operand, target string) string {
+	switch o {
+	case isa.OperandStack:
+		return fmt.Sprintf("*SP[0x%02X]", ins.Imm)
+	case isa.OperandMemory:
+		return fmt.Sprintf("M[0x%08X]", ins.Imm)
+	case isa.OperandU8:
+		return fmt.Sprintf("0x%02X", ins.Imm)
+	case isa.OperandU16:
+		return fmt.Sprintf("0x%04X", ins.Imm)
+	case isa.OperandU32, isa.OperandS8, isa.OperandS16:
+		if target != "" {
+			return target
+		}
+
+		return fmt.Sprintf("0x%08X", ins.Imm)
+	case isa.OperandO:
+		return fmt.Sprintf("0x%0*X", 2*o.Bytes(ins.Size), ins.Imm)
+	}
+
+	return o.String()
+}
+
+// Text returns an instruction in assembler syntax, where branch and jump targets are named by names if possible.
+// Bytes that are not an instruction are written as a .u8 directive.
+func (ins Instruction) Text(names Names) string {
+	if !ins.Op.IsValid() {
+		bytes := make([]string, len(ins.Bytes))
+		for i, b := range ins.Bytes {
+			bytes[i] = fmt.Sprintf("0x%02X", b)
+		}
+
+		return ".u8 " + strings.Join(bytes, ", ")
+	}
+
+	target := ""
+	if addr, ok := ins.Target(); ok {
+		if name, ok := names[addr]; ok {
+			target = name
+		} else {
+			target = fmt.Sprintf("0x%08X", addr)
+		}
+	}
+
+	if len(ins.Op.Operands) == 0 {
+		return ins.Op.Mnemonic
+	}
+
+	ops := make([]string, len(ins.Op.Operands))
+	for i, o := range ins.Op.Operands {
+		ops[i] = operandText(ins, o, target)
+	}
+
+	return fmt.Sprintf("%-6s %s", ins.Op.Mnemonic, strings.Join(ops, ","))
+}
+
+// Write writes a listing of instructions that the assembler accepts, and assembles to the same bytes.
+// The listing begins with .org and .size directives for the first instruction.
+//
+// Branch and jump targets are named by symbols, if there is a symbol for the target that is the start of an
+// instruction. Other targets that are the start of an instruction are named Lxxxxxxxx, where xxxxxxxx is the address in
+// hex. Targets outside the listing are written as addresses.
+//
+// Each line has a comment with the address and bytes of the instruction.
+func Write(w io.Writer, instructions []Instruction, symbols Names) error {
+	if len(instructions) == 0 {
+		return nil
+	}
+
+	var (
+		starts = map[uint32]bool{}
+		names  = Names{}
+	)
+
+	for _, ins := range instructions {
+		starts[ins.Addr] = true
+	}
+
+	// Symbols are used in address order, skipping any that the assembler would reject
+	used := map[string]bool{}
+	for _, addr := range sortedAddrs(symbols) {
+		if name := symbols[addr]; starts[addr] && !used[name] && isName(name) {
+			names[addr], used[name] = name, true
+		}
+	}
+
+	for _, ins := range instructions {
+		if addr, ok := ins.Target(); ok && starts[addr] && (names[addr] == "") {
+			names[addr] = fmt.Sprintf("L%08X", addr)
+		}
+	}
+
+	first := instructions[0]
+	if _, err := fmt.Fprintf(w, "        .org   0x%08X\n        .size  %d\n", first.Addr, 8<<first.Size); err != nil {
+		return err
+	}
+
+	for _, ins := range instructions {
+		if name, ok := names[ins.Addr]; ok {
+			if _, err := fmt.Fprintf(w, "%s:\n", name); err != nil {
+				return err
+			}
+		}
+
+		bytes := make([]string, len(ins.Bytes))
+		for i, b := range ins.Bytes {
+			bytes[i] = fmt.Sprintf("%02X", b)
+		}
+
+		if _, err := fmt.Fprintf(
+			w,
+			"        %-32s ; %08X  %s\n",
+			ins.Text(names),
+			ins.Addr,
+			strings.Join(bytes, " "),
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,145 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package disasm
+
+import (
+	"bytes"
+	"math/rand"
+	"strings"
+	"testing"
+	"testing/quick"
+
+	"github.com/bantling/goprocessor/pkg/asm"
+	"github.com/bantling/goprocessor/pkg/isa"
+	"github.com/bantling/goprocessor/pkg/register"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecode(t *testing.T) {
+	// Page 0 with a signed immediate
+	ins := Decode([]byte{0x31, 0xF8, 0x00}, 0x100, register.Operand8)
+	assert.Equal(t, "BPL S8", ins.Op.String())
+	assert.Equal(t, []byte{0x31, 0xF8}, ins.Bytes)
+	assert.Equal(t, uint64(0xFFFFFFFFFFFFFFF8), ins.Imm)
+	target, ok := ins.Target()
+	assert.True(t, ok)
+	assert.Equal(t, uint32(0xFA), target)
+	assert.Equal(t, "BPL    0x000000FA", ins.Text(nil))
+	assert.Equal(t, "BPL    LOOP", ins.Text(Names{0xFA: "LOOP"}))
+
+	// Page 1 with an immediate of the operand size
+	ins = Decode([]byte{0xFF, 0x43, 0x12, 0x34}, 0, register.Operand16)
+	assert.Equal(t, "MOV R0,O", ins.Op.String())
+	assert.Equal(t, uint64(0x1234), ins.Imm)
+	_, ok = ins.Target()
+	assert.False(t, ok)
+	assert.Equal(t, "MOV    R0,0x1234", ins.Text(nil))
+
+	// Absolute jump
+	ins = Decode([]byte{0x33, 0x00, 0x02, 0x00, 0x00}, 0, register.Operand8)
+	target, ok = ins.Target()
+	assert.True(t, ok)
+	assert.Equal(t, uint32(0x00020000), target)
+
+	// Memory, stack, and no operands
+	assert.Equal(t, "MOV    R0c,M[0x00000020]", Decode([]byte{0xFF, 0x4B, 0, 0, 0, 0x20}, 0, 0).Text(nil))
+	assert.Equal(t, "MOV    R0,*SP[0x08]", Decode([]byte{0x8B, 0x08}, 0, 0).Text(nil))
+	assert.Equal(t, "SDAM*[]", Decode([]byte{0xC2}, 0, 0).Text(nil))
+
+	// Reserved, truncated, and EXT at the end
+	ins = Decode([]byte{0xFF, 0xFF}, 0, 0)
+	assert.False(t, ins.Op.IsValid())
+	assert.Equal(t, ".u8 0xFF, 0xFF", ins.Text(nil))
+	assert.Equal(t, ".u8 0x31", Decode([]byte{0x31}, 0, 0).Text(nil))
+	assert.Equal(t, ".u8 0xFF", Decode([]byte{0xFF}, 0, 0).Text(nil))
+}
+
+func TestDisassembleMathMode(t *testing.T) {
+	// Math modes other than integer promote 8 bit O immediates to 16 bits
+	ins := Disassemble([]byte{0xD6, 0xFF, 0x43, 0x00, 0xC8, 0xD4, 0xFF, 0x43, 0x01}, 0, register.Operand8)
+	assert.Equal(t, 4, len(ins))
+	assert.Equal(t, "MOV    R0,0x00C8", ins[1].Text(nil))
+	assert.Equal(t, register.Operand16, ins[1].Size)
+	assert.Equal(t, "MOV    R0,0x01", ins[3].Text(nil))
+}
+
+func TestWrite(t *testing.T) {
+	code := []byte{
+		0xD1,                   // SOS16
+		0xFF, 0x43, 0x00, 0x00, // MOV R0,0
+		0xFF, 0x47, // MOV R0c,*PTR0
+		0x02,       // ADD R0,R0c
+		0x31, 0xFB, // BPL -5
+		0x2E, 0x00, // BEQ +0
+		0x35, 0x10, 0x00, // JMP outside
+	}
+
+	var buf bytes.Buffer
+	assert.Nil(t, Write(&buf, Disassemble(code, 0x1000, register.Operand8), Names{0x1000: "START", 0x1001: "SP"}))
+	assert.Equal(
+		t,
+		`        .org   0x00001000
+        .size  8
+START:
+        SOS16                            ; 00001000  D1
+        MOV    R0,0x0000                 ; 00001001  FF 43 00 00
+L00001005:
+        MOV    R0c,*PTR0                 ; 00001005  FF 47
+        ADD    R0,R0c                    ; 00001007  02
+        BPL    L00001005                 ; 00001008  31 FB
+        BEQ    L0000100C                 ; 0000100A  2E 00
+L0000100C:
+        JMP    0x0000200F                ; 0000100C  35 10 00
+`,
+		buf.String(),
+	)
+}
+
+// roundTrip disassembles code and assembles the listing, returning the assembled bytes
+func roundTrip(t *testing.T, code []byte, origin uint32, size uint8) []byte {
+	var buf bytes.Buffer
+	assert.Nil(t, Write(&buf, Disassemble(code, origin, size), nil))
+
+	prog, err := asm.Assemble("roundtrip.s", strings.NewReader(buf.String()))
+	if !assert.Nil(t, err, buf.String()) {
+		return nil
+	}
+
+	assert.Equal(t, origin, prog.Origin)
+	return prog.Code
+}
+
+func TestRoundTripOpcodes(t *testing.T) {
+	// Every valid opcode in every operand size, with random immediates
+	r := rand.New(rand.NewSource(1))
+	for size := register.Operand8; size <= register.Operand64; size++ {
+		for _, op := range isa.Opcodes() {
+			code := make([]byte, op.Bytes(size))
+			r.Read(code)
+			if op.Page == isa.Page1 {
+				code[0], code[1] = isa.EXT, op.Code
+			} else {
+				code[0] = op.Code
+			}
+
+			assert.Equal(t, code, roundTrip(t, code, 0x00020000, size), op.String())
+		}
+	}
+}
+
+func TestRoundTripProperty(t *testing.T) {
+	// Arbitrary bytes, including reserved opcodes, truncated instructions, and operand size changes
+	f := func(code []byte, origin uint32, size uint8) bool {
+		if len(code) == 0 {
+			return true
+		}
+
+		// Keep the code within the address space
+		origin &= 0x7FFFFFFF
+		size &= register.Operand64
+
+		return bytes.Equal(code, roundTrip(t, code, origin, size))
+	}
+
+	assert.Nil(t, quick.Check(f, &quick.Config{MaxCount: 2000, Rand: rand.New(rand.NewSource(1))}))
+}
@@ -0,0 +1,5 @@
+// Package disasm decodes bytes into instructions, and writes them in the syntax accepted by package asm.
+//
+// A listing written by Write assembles back to the bytes it was decoded from.
+// SPDX-License-Identifier: Apache-2.0
+package disasm
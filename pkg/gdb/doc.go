@@ -0,0 +1,12 @@
+// Package gdb serves a debug.Debugger to clients of the GDB Remote Serial Protocol over a stream such as a TCP or
+// Unix socket.
+//
+// The registers are the fields of register.Registers in order, named in lower case, including st. Values are big
+// endian like memory, and the target description read by qXfer:features:read:target.xml lists them with their sizes.
+// PC is relative to CP, while addresses of memory, breakpoints, and watchpoints are absolute.
+//
+// The supported packets are ? g G p P m M c s Z0 z0 Z1 z1 Z2 z2 Z3 z3 D k, the qSupported, qXfer, qAttached, qC,
+// qfThreadInfo, qsThreadInfo, and QStartNoAckMode queries, and H. Other packets are replied to with an empty packet,
+// which tells the client they are not supported. A continue is interrupted by a 0x03 byte.
+// SPDX-License-Identifier: Apache-2.0
+package gdb
@@ -0,0 +1,118 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gdb
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+const (
+	// interrupt is the byte a client sends to stop a continue
+	interrupt = 0x03
+
+	// escape precedes a byte of packet data that is xored with 0x20
+	escape = '}'
+)
+
+// event is something received from a client: a packet, an interrupt, or a negative acknowledgement.
+// Positive acknowledgements are discarded.
+type event struct {
+	packet    string
+	valid     bool // the checksum of the packet is correct
+	interrupt bool
+	nack      bool
+}
+
+// readEvent reads the next event
+func readEvent(r *bufio.Reader) (event, error) {
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return event{}, err
+		}
+
+		switch b {
+		case interrupt:
+			return event{interrupt: true}, nil
+		case '-':
+			return event{nack: true}, nil
+		case '$':
+			return readPacket(r)
+		}
+	}
+}
+
+// readPacket reads the data and checksum of a packet after the $, and unescapes the data.
+// Data beyond packetSize bytes is discarded, and the packet is invalid.
+func readPacket(r *bufio.Reader) (event, error) {
+	var (
+		data []byte
+		sum  uint8
+		long bool
+	)
+
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return event{}, err
+		}
+
+		if b == '#' {
+			break
+		}
+
+		sum += b
+		if b == escape {
+			if b, err = r.ReadByte(); err != nil {
+				return event{}, err
+			}
+
+			sum += b
+			b ^= 0x20
+		}
+
+		if len(data) == packetSize {
+			long = true
+			continue
+		}
+
+		data = append(data, b)
+	}
+
+	var cs [2]byte
+	if _, err := io.ReadFull(r, cs[:]); err != nil {
+		return event{}, err
+	}
+
+	want, err := strconv.ParseUint(string(cs[:]), 16, 8)
+	return event{packet: string(data), valid: !long && (err == nil) && (uint8(want) == sum)}, nil
+}
+
+// writePacket writes data as a packet, escaping the bytes that have a meaning in packets
+func writePacket(w io.Writer, data string) error {
+	var (
+		buf = make([]byte, 0, len(data)+4)
+		sum uint8
+	)
+
+	buf = append(buf, '$')
+	for i := 0; i < len(data); i++ {
+		b := data[i]
+		switch b {
+		case '#', '$', '*', escape:
+			buf = append(buf, escape)
+			sum += escape
+			b ^= 0x20
+		}
+
+		buf = append(buf, b)
+		sum += b
+	}
+
+	buf = append(buf, fmt.Sprintf("#%02x", sum)...)
+	_, err := w.Write(buf)
+	return err
+}
@@ -0,0 +1,59 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gdb
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWritePacket(t *testing.T) {
+	var buf bytes.Buffer
+	assert.Nil(t, writePacket(&buf, "OK"))
+	assert.Equal(t, "$OK#9a", buf.String())
+
+	buf.Reset()
+	assert.Nil(t, writePacket(&buf, "a#b}"))
+	assert.Equal(t, "$a}\x03b}]#1d", buf.String())
+}
+
+func TestReadEvent(t *testing.T) {
+	r := bufio.NewReader(strings.NewReader("+$OK#9a-\x03$a}\x03b}]#1d$g#00$g#zz"))
+
+	for _, want := range []event{
+		{packet: "OK", valid: true},
+		{nack: true},
+		{interrupt: true},
+		{packet: "a#b}", valid: true},
+		{packet: "g"},
+		{packet: "g"},
+	} {
+		ev, err := readEvent(r)
+		assert.Nil(t, err)
+		assert.Equal(t, want, ev)
+	}
+
+	_, err := readEvent(r)
+	assert.Equal(t, io.EOF, err)
+
+	_, err = readEvent(bufio.NewReader(strings.NewReader("$g#0")))
+	assert.Equal(t, io.ErrUnexpectedEOF, err)
+
+	// Data beyond packetSize is discarded, and the packet is invalid even if the checksum is correct
+	var buf bytes.Buffer
+	assert.Nil(t, writePacket(&buf, strings.Repeat("m", packetSize+1)))
+	ev, err := readEvent(bufio.NewReader(&buf))
+	assert.Nil(t, err)
+	assert.Equal(t, event{packet: strings.Repeat("m", packetSize)}, ev)
+
+	buf.Reset()
+	assert.Nil(t, writePacket(&buf, strings.Repeat("m", packetSize)))
+	ev, err = readEvent(bufio.NewReader(&buf))
+	assert.Nil(t, err)
+	assert.True(t, ev.valid)
+}
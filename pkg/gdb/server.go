@@ -0,0 +1,512 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gdb
+
+import (
+	"bufio"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/bantling/goprocessor/pkg/debug"
+	"github.com/bantling/goprocessor/pkg/memory"
+)
+
+const (
+	// packetSize is the largest packet the server accepts
+	packetSize = 0x1000
+
+	// signals reported by stop replies
+	sigint  = 0x02
+	sigill  = 0x04
+	sigtrap = 0x05
+	sigsegv = 0x0B
+)
+
+// replies to packets
+const (
+	replyOK          = "OK"
+	replyUnsupported = ""
+	replyInvalid     = "E01"
+	replyFault       = "E0E"
+)
+
+// watchKinds maps the type of a Z or z packet to a kind of watchpoint
+var watchKinds = map[byte]debug.WatchKind{
+	'2': debug.WatchWrite,
+	'3': debug.WatchRead,
+}
+
+// Server serves a debug.Debugger to one client at a time
+type Server struct {
+	dbg *debug.Debugger
+}
+
+// NewServer constructs a Server of a Debugger
+func NewServer(dbg *debug.Debugger) *Server {
+	return &Server{dbg: dbg}
+}
+
+// Serve accepts connections from a listener and serves them one at a time, until accepting fails.
+// Closing the listener stops Serve after the current connection is closed.
+func (s *Server) Serve(l net.Listener) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return err
+		}
+
+		// An error only ends the connection that failed
+		s.ServeConn(conn)
+		conn.Close()
+	}
+}
+
+// ServeConn serves one client until it detaches, kills the target, or the connection fails.
+// Returns nil if the client detaches, kills the target, or closes the connection.
+func (s *Server) ServeConn(rw io.ReadWriter) error {
+	var (
+		events = make(chan event)
+		done   = make(chan struct{})
+		errc   = make(chan error, 1)
+		c      = &conn{dbg: s.dbg, w: rw, events: events}
+	)
+	defer close(done)
+
+	go func() {
+		r := bufio.NewReader(rw)
+		for {
+			ev, err := readEvent(r)
+			if err != nil {
+				errc <- err
+				close(events)
+				return
+			}
+
+			select {
+			case events <- ev:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	if err := c.serve(); err != nil {
+		return err
+	}
+
+	select {
+	case err := <-errc:
+		if err != io.EOF {
+			return err
+		}
+	default:
+	}
+
+	return nil
+}
+
+// conn is the state of a connection to a client
+type conn struct {
+	dbg     *debug.Debugger
+	w       io.Writer
+	events  chan event
+	pending []event // events received while continuing, which are handled before the next from events
+	noAck   bool
+	swbreak bool
+	last    string
+}
+
+// next returns the next pending event, or the next event from the client.
+// Returns false if the connection is closed.
+func (c *conn) next() (event, bool) {
+	if len(c.pending) > 0 {
+		ev := c.pending[0]
+		c.pending = c.pending[1:]
+		return ev, true
+	}
+
+	ev, ok := <-c.events
+	return ev, ok
+}
+
+// serve handles events until the client detaches, kills the target, or the connection is closed
+func (c *conn) serve() error {
+	for {
+		ev, ok := c.next()
+		if !ok {
+			return nil
+		}
+
+		switch {
+		case ev.nack:
+			if err := writePacket(c.w, c.last); err != nil {
+				return err
+			}
+
+			continue
+		case ev.interrupt:
+			// Only meaningful while continuing
+			continue
+		}
+
+		if !c.noAck {
+			ack := "+"
+			if !ev.valid {
+				ack = "-"
+			}
+
+			if _, err := io.WriteString(c.w, ack); err != nil {
+				return err
+			}
+		}
+
+		if !ev.valid {
+			continue
+		}
+
+		reply, end := c.handle(ev.packet)
+		if end && (reply == "") {
+			return nil
+		}
+
+		c.last = reply
+		if err := writePacket(c.w, reply); err != nil {
+			return err
+		}
+
+		if end {
+			return nil
+		}
+	}
+}
+
+// handle handles a packet, and returns the reply and true if the session ends after the reply.
+// A session that ends with an empty reply does not send a reply.
+func (c *conn) handle(packet string) (string, bool) {
+	if packet == "" {
+		return replyUnsupported, false
+	}
+
+	regs := c.dbg.Processor().Registers()
+	cmd, args := packet[0], packet[1:]
+
+	switch cmd {
+	case '?':
+		return fmt.Sprintf("S%02x", sigtrap), false
+
+	case 'g':
+		var sb strings.Builder
+		for _, ri := range registers {
+			sb.WriteString(ri.hex(regs))
+		}
+
+		return sb.String(), false
+
+	case 'G':
+		vals := make([]uint64, len(registers))
+		for i, ri := range registers {
+			n := ri.bytes * 2
+			if len(args) < n {
+				return replyInvalid, false
+			}
+
+			val, err := strconv.ParseUint(args[:n], 16, 64)
+			if err != nil {
+				return replyInvalid, false
+			}
+
+			vals[i], args = val, args[n:]
+		}
+
+		if args != "" {
+			return replyInvalid, false
+		}
+
+		for i, ri := range registers {
+			ri.set(regs, vals[i])
+		}
+
+		return replyOK, false
+
+	case 'p':
+		n, err := strconv.ParseUint(args, 16, 32)
+		if (err != nil) || (n >= uint64(len(registers))) {
+			return replyInvalid, false
+		}
+
+		return registers[n].hex(regs), false
+
+	case 'P':
+		parts := strings.SplitN(args, "=", 2)
+		if len(parts) != 2 {
+			return replyInvalid, false
+		}
+
+		n, err := strconv.ParseUint(parts[0], 16, 32)
+		if (err != nil) || (n >= uint64(len(registers))) || (len(parts[1]) != registers[n].bytes*2) {
+			return replyInvalid, false
+		}
+
+		val, err := strconv.ParseUint(parts[1], 16, 64)
+		if err != nil {
+			return replyInvalid, false
+		}
+
+		registers[n].set(regs, val)
+		return replyOK, false
+
+	case 'm':
+		addr, size, ok := addrSize(args)
+		if !ok || (size > packetSize/2) {
+			return replyInvalid, false
+		}
+
+		buf := make([]byte, size)
+		if err := memory.ReadBytes(c.dbg.Bus(), addr, buf); err != nil {
+			return replyFault, false
+		}
+
+		return hex.EncodeToString(buf), false
+
+	case 'M':
+		parts := strings.SplitN(args, ":", 2)
+		addr, size, ok := addrSize(parts[0])
+		if !ok || (len(parts) != 2) {
+			return replyInvalid, false
+		}
+
+		buf, err := hex.DecodeString(parts[1])
+		if (err != nil) || (uint32(len(buf)) != size) {
+			return replyInvalid, false
+		}
+
+		if err := memory.WriteBytes(c.dbg.Bus(), addr, buf); err != nil {
+			return replyFault, false
+		}
+
+		return replyOK, false
+
+	case 'c', 's':
+		if args != "" {
+			addr, err := strconv.ParseUint(args, 16, 32)
+			if err != nil {
+				return replyInvalid, false
+			}
+
+			regs.PC = uint32(addr) - regs.CP
+		}
+
+		if cmd == 's' {
+			return c.stopReply(c.dbg.Step()), false
+		}
+
+		return c.stopReply(c.resume()), false
+
+	case 'Z', 'z':
+		return c.point(cmd == 'Z', args), false
+
+	case 'H':
+		return replyOK, false
+
+	case 'D':
+		return replyOK, true
+
+	case 'k':
+		return "", true
+
+	case 'q', 'Q':
+		return c.query(packet), false
+	}
+
+	return replyUnsupported, false
+}
+
+// addrSize parses the addr,length arguments of a packet
+func addrSize(args string) (uint32, uint32, bool) {
+	parts := strings.Split(args, ",")
+	if len(parts) != 2 {
+		return 0, 0, false
+	}
+
+	addr, err := strconv.ParseUint(parts[0], 16, 32)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	size, err := strconv.ParseUint(parts[1], 16, 32)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	return uint32(addr), uint32(size), true
+}
+
+// point inserts or removes a breakpoint or watchpoint, given the type,addr,kind arguments of a Z or z packet.
+// For a breakpoint the kind is ignored, for a watchpoint it is the number of bytes watched. Only software and
+// hardware breakpoints and write and read watchpoints are supported.
+func (c *conn) point(insert bool, args string) string {
+	if (len(args) < 2) || (args[1] != ',') {
+		return replyInvalid
+	}
+
+	// Conditions and commands evaluated by the target are not supported
+	if i := strings.IndexByte(args, ';'); i >= 0 {
+		args = args[:i]
+	}
+
+	typ := args[0]
+	addr, size, ok := addrSize(args[2:])
+	if !ok {
+		return replyInvalid
+	}
+
+	if (typ == '0') || (typ == '1') {
+		if insert {
+			c.dbg.Break(addr, nil)
+		} else {
+			c.dbg.Delete(addr)
+		}
+
+		return replyOK
+	}
+
+	kind, ok := watchKinds[typ]
+	if !ok {
+		return replyUnsupported
+	}
+
+	w := debug.Watchpoint{Addr: addr, Size: size, Kind: kind}
+	if insert {
+		c.dbg.Watch(w)
+	} else {
+		c.dbg.Remove(w)
+	}
+
+	return replyOK
+}
+
+// query handles the general query and set packets
+func (c *conn) query(packet string) string {
+	name, args := packet, ""
+	if i := strings.IndexByte(packet, ':'); i >= 0 {
+		name, args = packet[:i], packet[i+1:]
+	}
+
+	switch name {
+	case "qSupported":
+		for _, feature := range strings.Split(args, ";") {
+			if feature == "swbreak+" {
+				c.swbreak = true
+			}
+		}
+
+		return fmt.Sprintf("PacketSize=%x;qXfer:features:read+;QStartNoAckMode+;swbreak+", packetSize)
+
+	case "QStartNoAckMode":
+		c.noAck = true
+		return replyOK
+
+	case "qXfer":
+		const prefix = "features:read:target.xml:"
+		if !strings.HasPrefix(args, prefix) {
+			return replyUnsupported
+		}
+
+		offset, size, ok := addrSize(args[len(prefix):])
+		if !ok {
+			return replyInvalid
+		}
+
+		if offset >= uint32(len(targetXML)) {
+			return "l"
+		}
+
+		if rest := targetXML[offset:]; uint32(len(rest)) > size {
+			return "m" + rest[:size]
+		}
+
+		return "l" + targetXML[offset:]
+
+	case "qAttached":
+		return "1"
+
+	case "qC":
+		return "QC1"
+
+	case "qfThreadInfo":
+		return "m1"
+
+	case "qsThreadInfo":
+		return "l"
+	}
+
+	return replyUnsupported
+}
+
+// resume continues until the debugger stops, or the client sends an interrupt or disconnects
+func (c *conn) resume() (debug.Stop, error) {
+	var (
+		ctx, cancel = context.WithCancel(context.Background())
+		done        = make(chan struct{})
+		wg          sync.WaitGroup
+	)
+	defer cancel()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		// An interrupt or closed connection stops running, other events are handled after it stops
+		for {
+			select {
+			case ev, ok := <-c.events:
+				if !ok || ev.interrupt {
+					cancel()
+					return
+				}
+
+				c.pending = append(c.pending, ev)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	stop, err := c.dbg.Continue(ctx)
+	close(done)
+	wg.Wait()
+
+	return stop, err
+}
+
+// stopReply returns the reply to a step or continue
+func (c *conn) stopReply(stop debug.Stop, err error) string {
+	var fault *memory.Fault
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		return fmt.Sprintf("S%02x", sigint)
+	case errors.As(err, &fault):
+		return fmt.Sprintf("S%02x", sigsegv)
+	case err != nil:
+		return fmt.Sprintf("S%02x", sigill)
+	case (stop.Reason == debug.StopBreakpoint) && c.swbreak:
+		return fmt.Sprintf("T%02xswbreak:;", sigtrap)
+	case stop.Reason == debug.StopWatchpoint:
+		watch := "watch"
+		if stop.Watchpoint.Kind == debug.WatchRead {
+			watch = "rwatch"
+		}
+
+		return fmt.Sprintf("T%02x%s:%x;", sigtrap, watch, stop.Watchpoint.Addr)
+	}
+
+	return fmt.Sprintf("S%02x", sigtrap)
+}
@@ -0,0 +1,197 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gdb
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bantling/goprocessor/pkg/debug"
+	"github.com/bantling/goprocessor/pkg/internal/testprog"
+	"github.com/stretchr/testify/assert"
+)
+
+// program stores a value and loops forever
+const program = `
+main:   MOV     R0, 7
+        MOV     M[count], R0
+loop:   JMP     loop
+count:  .u8     0
+`
+
+// client is a minimal RSP client
+type client struct {
+	t    *testing.T
+	conn net.Conn
+	r    *bufio.Reader
+	ack  bool
+}
+
+// serve assembles program at CP 0x1000, serves it on a listener, and returns a connected client and the absolute
+// addresses of the labels
+func serve(t *testing.T, network, addr string) (*client, map[string]uint32, *debug.Debugger, func()) {
+	ram, labels := testprog.Load(t, program, 0x1000)
+	ram.Protect(0x8000, 0x1000)
+
+	dbg := debug.New(ram)
+	dbg.Processor().Registers().CP = 0x1000
+	dbg.Processor().Registers().DP0 = 0x1000
+
+	l, err := net.Listen(network, addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	served := make(chan error)
+	go func() { served <- NewServer(dbg).Serve(l) }()
+
+	conn, err := net.Dial(network, l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &client{t: t, conn: conn, r: bufio.NewReader(conn), ack: true}, labels, dbg, func() {
+		conn.Close()
+		l.Close()
+		assert.NotNil(t, <-served)
+	}
+}
+
+// send sends a packet and returns the reply
+func (c *client) send(packet string) string {
+	assert.Nil(c.t, writePacket(c.conn, packet))
+	return c.reply()
+}
+
+// reply reads a reply and acknowledges it
+func (c *client) reply() string {
+	ev, err := readEvent(c.r)
+	assert.Nil(c.t, err)
+	assert.True(c.t, ev.valid)
+
+	if c.ack {
+		_, err = io.WriteString(c.conn, "+")
+		assert.Nil(c.t, err)
+	}
+
+	return ev.packet
+}
+
+func TestServerTCP(t *testing.T) {
+	c, labels, dbg, closer := serve(t, "tcp", "127.0.0.1:0")
+	defer closer()
+	regs := dbg.Processor().Registers()
+
+	// Queries
+	assert.Equal(t, "PacketSize=1000;qXfer:features:read+;QStartNoAckMode+;swbreak+", c.send("qSupported:swbreak+"))
+	assert.Equal(t, "m<?xml", c.send("qXfer:features:read:target.xml:0,5"))
+	xml := c.send("qXfer:features:read:target.xml:0,1000")
+	assert.Equal(t, "l"+targetXML, xml)
+	assert.True(t, strings.Contains(xml, `<reg name="st" bitsize="32" type="uint32" regnum="28"/>`))
+	assert.Equal(t, "l", c.send(fmt.Sprintf("qXfer:features:read:target.xml:%x,10", len(targetXML))))
+	assert.Equal(t, "1", c.send("qAttached"))
+	assert.Equal(t, "OK", c.send("Hg0"))
+	assert.Equal(t, "", c.send("vCont?"))
+	assert.Equal(t, "S05", c.send("?"))
+
+	// Registers
+	g := c.send("g")
+	assert.Equal(t, 248, len(g))
+	assert.Equal(t, "00001000", g[208:216]) // CP follows 4 64 bit, 14 32 bit, and 8 16 bit registers
+	assert.Equal(t, "00001000", c.send("p1a"))
+	assert.Equal(t, "OK", c.send("P1c=00000123"))
+	assert.Equal(t, uint32(0x123), uint32(regs.ST()))
+	assert.Equal(t, "OK", c.send("P1c=00000000"))
+	assert.Equal(t, "E01", c.send("P1c=0"))
+	assert.Equal(t, "E01", c.send("p20"))
+
+	assert.Equal(t, "OK", c.send("G"+"0123456789abcdef"+g[16:]))
+	assert.Equal(t, uint64(0x0123456789ABCDEF), regs.R0)
+	assert.Equal(t, "E01", c.send("G"+g[2:]))
+
+	// Memory
+	count := labels["count"]
+	assert.Equal(t, "OK", c.send(fmt.Sprintf("M%x,2:0102", count)))
+	assert.Equal(t, "0102", c.send(fmt.Sprintf("m%x,2", count)))
+	assert.Equal(t, "OK", c.send(fmt.Sprintf("M%x,1:00", count)))
+	assert.Equal(t, "E0E", c.send("M8000,1:00"))
+	assert.Equal(t, "E01", c.send("M8000,2:00"))
+	assert.Equal(t, "E01", c.send("mzz,1"))
+
+	// Breakpoint, stepping, and watchpoint
+	loop := labels["loop"]
+	assert.Equal(t, "OK", c.send(fmt.Sprintf("Z0,%x,1", loop)))
+	assert.Equal(t, "T05swbreak:;", c.send("c"))
+	assert.Equal(t, loop, dbg.Addr())
+	assert.Equal(t, uint64(7), regs.R0)
+	assert.Equal(t, "OK", c.send(fmt.Sprintf("z0,%x,1", loop)))
+
+	assert.Equal(t, "OK", c.send(fmt.Sprintf("Z2,%x,1", count)))
+	assert.Equal(t, "OK", c.send(fmt.Sprintf("Z3,%x,1;X2,00", count)))
+	assert.Equal(t, fmt.Sprintf("T05watch:%x;", count), c.send("c1000"))
+	assert.Equal(t, loop, dbg.Addr())
+	assert.Equal(t, "OK", c.send(fmt.Sprintf("z2,%x,1", count)))
+	assert.Equal(t, "OK", c.send(fmt.Sprintf("z3,%x,1", count)))
+	assert.Equal(t, 0, len(dbg.Watchpoints()))
+	assert.Equal(t, "", c.send(fmt.Sprintf("Z4,%x,1", count)))
+
+	assert.Equal(t, "S05", c.send("s1000"))
+	assert.Equal(t, labels["main"]+3, dbg.Addr())
+
+	// Interrupt a continue of the endless loop
+	assert.Nil(t, writePacket(c.conn, "c"))
+	_, err := c.conn.Write([]byte{interrupt})
+	assert.Nil(t, err)
+	assert.Equal(t, "S02", c.reply())
+
+	// A packet received while continuing is handled after the continue stops, it does not stop it
+	assert.Nil(t, writePacket(c.conn, "c"))
+	assert.Nil(t, writePacket(c.conn, "qAttached"))
+	_, err = c.conn.Write([]byte{interrupt})
+	assert.Nil(t, err)
+	assert.Equal(t, "S02", c.reply())
+	assert.Equal(t, "1", c.reply())
+
+	// Detach ends the connection
+	assert.Equal(t, "OK", c.send("D"))
+	_, err = c.r.ReadByte()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestServerUnix(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gdb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	c, _, _, closer := serve(t, "unix", filepath.Join(dir, "sock"))
+	defer closer()
+
+	// A bad checksum is not acknowledged
+	_, err = io.WriteString(c.conn, "$?#00")
+	assert.Nil(t, err)
+	ev, err := readEvent(c.r)
+	assert.Nil(t, err)
+	assert.True(t, ev.nack)
+
+	// Without acknowledgements, a reply is resent on request
+	assert.Equal(t, "OK", c.send("QStartNoAckMode"))
+	c.ack = false
+	assert.Equal(t, "S05", c.send("?"))
+	_, err = io.WriteString(c.conn, "-")
+	assert.Nil(t, err)
+	assert.Equal(t, "S05", c.reply())
+
+	// Kill ends the connection without a reply
+	assert.Nil(t, writePacket(c.conn, "k"))
+	_, err = c.r.ReadByte()
+	assert.Equal(t, io.EOF, err)
+}
@@ -0,0 +1,75 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package gdb
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/bantling/goprocessor/pkg/register"
+)
+
+// registerInfo describes a field of register.Registers
+type registerInfo struct {
+	name  string
+	field int
+	bytes int
+}
+
+// registers describes every field of register.Registers in order
+var registers = func() []registerInfo {
+	var (
+		typ   = reflect.TypeOf(register.Registers{})
+		infos = make([]registerInfo, typ.NumField())
+	)
+
+	for i := range infos {
+		f := typ.Field(i)
+		infos[i] = registerInfo{name: strings.ToLower(f.Name), field: i, bytes: f.Type.Bits() / 8}
+	}
+
+	return infos
+}()
+
+// get returns the value of the register
+func (ri registerInfo) get(regs *register.Registers) uint64 {
+	if ri.name == "st" {
+		return uint64(regs.ST())
+	}
+
+	return reflect.ValueOf(regs).Elem().Field(ri.field).Uint()
+}
+
+// set sets the value of the register
+func (ri registerInfo) set(regs *register.Registers, val uint64) {
+	if ri.name == "st" {
+		regs.SetST(register.StatusRegister(val))
+		return
+	}
+
+	reflect.ValueOf(regs).Elem().Field(ri.field).SetUint(val)
+}
+
+// hex returns the value of the register as big endian hex
+func (ri registerInfo) hex(regs *register.Registers) string {
+	return fmt.Sprintf("%0*x", ri.bytes*2, ri.get(regs))
+}
+
+// targetXML is the target description
+var targetXML = func() string {
+	var sb strings.Builder
+	sb.WriteString(`<?xml version="1.0"?>
+<!DOCTYPE target SYSTEM "gdb-target.dtd">
+<target version="1.0">
+  <feature name="org.bantling.goprocessor.core">
+`)
+
+	for i, ri := range registers {
+		fmt.Fprintf(&sb, "    <reg name=\"%s\" bitsize=\"%d\" type=\"uint%d\" regnum=\"%d\"/>\n",
+			ri.name, ri.bytes*8, ri.bytes*8, i)
+	}
+
+	sb.WriteString("  </feature>\n</target>\n")
+	return sb.String()
+}()
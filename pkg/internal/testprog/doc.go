@@ -0,0 +1,3 @@
+// Package testprog assembles programs into memory for the tests of packages that execute them.
+// SPDX-License-Identifier: Apache-2.0
+package testprog
@@ -0,0 +1,51 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package testprog
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bantling/goprocessor/pkg/asm"
+	"github.com/bantling/goprocessor/pkg/cpu"
+	"github.com/bantling/goprocessor/pkg/memory"
+)
+
+// Load assembles src and writes it into a new RAM at addr, failing the test if it does not assemble.
+// Returns the RAM and the absolute address of each label.
+func Load(t *testing.T, src string, addr uint32) (*memory.RAM, map[string]uint32) {
+	prog, err := asm.Assemble("test.s", strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ram := memory.NewRAM()
+	if err := memory.WriteBytes(ram, addr, prog.Code); err != nil {
+		t.Fatal(err)
+	}
+
+	labels := map[string]uint32{}
+	for _, sym := range prog.Symbols {
+		labels[sym.Name] = addr + uint32(sym.Value)
+	}
+
+	return ram, labels
+}
+
+// Processor loads src at addr as described by Load, and returns a cpu.Processor of the RAM with CP = addr
+func Processor(t *testing.T, src string, addr uint32) *cpu.Processor {
+	ram, _ := Load(t, src, addr)
+	p := cpu.New(ram)
+	p.Registers().CP = addr
+
+	return p
+}
+
+// Steps executes n instructions, failing the test if any of them fails
+func Steps(t *testing.T, p *cpu.Processor, n int) {
+	for i := 0; i < n; i++ {
+		if err := p.Step(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,8 @@
+// Package isa describes the instruction set: the opcodes of each page, their mnemonics, operands, and status flags.
+//
+// The opcode table is generated from doc/InstructionSet.html, so that the processor, assembler, disassembler, and
+// documentation all agree. Run go generate after editing the document.
+// SPDX-License-Identifier: Apache-2.0
+package isa
+
+//go:generate go run ./gen -in ../../doc/InstructionSet.html -out opcodes.go
@@ -0,0 +1,316 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Command gen generates the isa opcode table from the opcode and instruction tables of doc/InstructionSet.html
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"html"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// GenError represents an error parsing the instruction set document
+type GenError string
+
+func (e GenError) Error() string {
+	return string(e)
+}
+
+const (
+	// ErrNoOpcodeTable is returned when the document does not have an opcode table for a page
+	ErrNoOpcodeTable = GenError("Missing opcode table")
+
+	// ErrNoInstructionTable is returned when the document does not have an instructions table
+	ErrNoInstructionTable = GenError("Missing instructions table")
+)
+
+var (
+	opcodeTableRE      = regexp.MustCompile(`(?s)<table id="opcodes(\d)">.*?<tbody>(.*?)</tbody>`)
+	opcodeRowRE        = regexp.MustCompile(`(?s)<th>([0-9A-F])</th>(.*?)</tr>`)
+	opcodeCellRE       = regexp.MustCompile(`<td class="ins0-(\w*)">([^<]*)</td>`)
+	instructionTableRE = regexp.MustCompile(`(?s)<h2>Instructions</h2>.*?<tbody>(.*?)</tbody>`)
+	instructionCellRE  = regexp.MustCompile(`(?s)<td>(.*?)</td>`)
+	brRE               = regexp.MustCompile(`<br>`)
+	groupHeadingRE     = regexp.MustCompile(`class="count-ins0-(\w+)"`)
+)
+
+// groups maps the class suffix of opcode cells to isa.Group constants
+var groups = map[string]string{
+	"binary": "GroupBinary",
+	"branch": "GroupBranch",
+	"float":  "GroupFloat",
+	"move":   "GroupMove",
+	"other":  "GroupOther",
+	"stack":  "GroupStack",
+	"status": "GroupStatus",
+	"unary":  "GroupUnary",
+}
+
+// operands maps operands as written in opcode cells to isa.Operand constants
+var operands = map[string]string{
+	"R0":      "OperandR0",
+	"R0c":     "OperandR0c",
+	"R1":      "OperandR1",
+	"R1c":     "OperandR1c",
+	"OFS0":    "OperandOFS0",
+	"OFS1":    "OperandOFS1",
+	"IX0":     "OperandIX0",
+	"IX1":     "OperandIX1",
+	"PTR0":    "OperandPTR0",
+	"PTR1":    "OperandPTR1",
+	"DP0":     "OperandDP0",
+	"DP1":     "OperandDP1",
+	"SP":      "OperandSP",
+	"SB":      "OperandSB",
+	"CP":      "OperandCP",
+	"ST":      "OperandST",
+	"CTR0":    "OperandCTR0",
+	"CTR1":    "OperandCTR1",
+	"CS0":     "OperandCS0",
+	"CS1":     "OperandCS1",
+	"IS0":     "OperandIS0",
+	"IS1":     "OperandIS1",
+	"*PTR0":   "OperandIndirectPTR0",
+	"*PTR1":   "OperandIndirectPTR1",
+	"*SP[U8]": "OperandStack",
+	"M":       "OperandMemory",
+	"U8":      "OperandU8",
+	"S8":      "OperandS8",
+	"U16":     "OperandU16",
+	"S16":     "OperandS16",
+	"U32":     "OperandU32",
+	"O":       "OperandO",
+}
+
+// flagLetters maps letters of a status flags mask to isa.Flags constants
+var flagLetters = map[byte]string{
+	'C': "FlagCarry",
+	'V': "FlagOverflow",
+	'Z': "FlagZero",
+	'N': "FlagNegative",
+	'A': "FlagAddressMode",
+	'I': "FlagInterruptDisable",
+	'O': "FlagOperandSize",
+	'M': "FlagMathMode",
+}
+
+// flagPositions maps the positions of the two halves of a status flags mask to isa.Flags constants,
+// for masks that contain a 0 or 1 to show the value a flag is set to
+var flagPositions = [2][]string{
+	{"FlagCarry", "FlagOverflow", "FlagZero", "FlagNegative", "FlagAddressMode", "FlagAddressMode", "FlagAddressMode", "FlagAddressMode"},
+	{"FlagOperandSize", "FlagOperandSize", "FlagMathMode", "FlagMathMode", "FlagInterruptDisable"},
+}
+
+// opcode is a parsed opcode cell
+type opcode struct {
+	page     int
+	code     int
+	mnemonic string
+	group    string
+	operands []string
+	flags    []string
+}
+
+// instructionKey identifies a row of the instructions table.
+// The group distinguishes mnemonics that are both binary and unary, like SHL.
+type instructionKey struct {
+	group    string
+	mnemonic string
+	page     int
+}
+
+// parseFlags parses a status flags mask like CVZN---- --------
+func parseFlags(mask string) []string {
+	var (
+		flags []string
+		seen  = map[string]bool{}
+	)
+
+	for half, s := range strings.Fields(mask) {
+		if half > 1 {
+			break
+		}
+
+		for i := 0; i < len(s); i++ {
+			var flag string
+			if f, ok := flagLetters[s[i]]; ok {
+				flag = f
+			} else if (s[i] == '0' || s[i] == '1') && (i < len(flagPositions[half])) {
+				flag = flagPositions[half][i]
+			}
+
+			if (flag != "") && !seen[flag] {
+				seen[flag] = true
+				flags = append(flags, flag)
+			}
+		}
+	}
+
+	return flags
+}
+
+// parseInstructions parses the instructions table into the status flags of each group, mnemonic, and page
+func parseInstructions(doc []byte) (map[instructionKey][]string, error) {
+	table := instructionTableRE.FindSubmatch(doc)
+	if table == nil {
+		return nil, ErrNoInstructionTable
+	}
+
+	var (
+		flags = map[instructionKey][]string{}
+		group string
+	)
+
+	for _, row := range strings.Split(string(table[1]), "<tr>") {
+		if heading := groupHeadingRE.FindStringSubmatch(row); heading != nil {
+			group = groups[heading[1]]
+			continue
+		}
+
+		cells := instructionCellRE.FindAllStringSubmatch(row, -1)
+		if len(cells) < 5 {
+			// Rows without a status flags column
+			continue
+		}
+
+		page, err := strconv.Atoi(strings.TrimSpace(cells[2][1]))
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid page %q", cells[0][1], cells[2][1])
+		}
+
+		// The name may be followed by aliases, and the status flags may be followed by notes
+		var (
+			mnemonic = strings.Fields(cells[0][1])[0]
+			mask     = brRE.Split(strings.TrimSpace(cells[4][1]), 2)[0]
+		)
+
+		flags[instructionKey{group, mnemonic, page}] = parseFlags(mask)
+	}
+
+	return flags, nil
+}
+
+// parseOpcodes parses the opcode tables of both pages
+func parseOpcodes(doc []byte) ([]opcode, error) {
+	var ops []opcode
+
+	tables := opcodeTableRE.FindAllSubmatch(doc, -1)
+	if len(tables) != 2 {
+		return nil, ErrNoOpcodeTable
+	}
+
+	for _, table := range tables {
+		page, _ := strconv.Atoi(string(table[1]))
+		for _, row := range opcodeRowRE.FindAllSubmatch(table[2], -1) {
+			hi, _ := strconv.ParseUint(string(row[1]), 16, 8)
+			for lo, cell := range opcodeCellRE.FindAllSubmatch(row[2], -1) {
+				text := strings.TrimSpace(html.UnescapeString(string(cell[2])))
+				if text == "" {
+					continue
+				}
+
+				op := opcode{
+					page:  page,
+					code:  int(hi)<<4 + lo,
+					group: groups[string(cell[1])],
+				}
+				if op.group == "" {
+					return nil, fmt.Errorf("page %d opcode %02X: unknown group %q", page, op.code, cell[1])
+				}
+
+				fields := strings.SplitN(text, " ", 2)
+				op.mnemonic = fields[0]
+				if len(fields) > 1 {
+					for _, o := range strings.Split(fields[1], ",") {
+						name, ok := operands[strings.TrimSpace(o)]
+						if !ok {
+							return nil, fmt.Errorf("page %d opcode %02X: unknown operand %q", page, op.code, o)
+						}
+
+						op.operands = append(op.operands, name)
+					}
+				}
+
+				ops = append(ops, op)
+			}
+		}
+	}
+
+	return ops, nil
+}
+
+// generate returns the formatted source of the opcode table described by an instruction set document
+func generate(doc []byte) ([]byte, error) {
+	ops, err := parseOpcodes(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	flags, err := parseInstructions(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	var src bytes.Buffer
+	src.WriteString("// Code generated by go run ./gen; DO NOT EDIT.\n\n")
+	src.WriteString("// SPDX-License-Identifier: Apache-2.0\n\n")
+	src.WriteString("package isa\n\n")
+	src.WriteString("// opcodes is every opcode of each page, where reserved opcodes have no mnemonic\n")
+	src.WriteString("var opcodes = [2][256]Opcode{\n")
+
+	page := -1
+	for _, op := range ops {
+		for ; page < op.page; page++ {
+			if page >= 0 {
+				src.WriteString("},\n")
+			}
+			src.WriteString("{\n")
+		}
+
+		// A mnemonic missing from the instructions table on one page is described by the other page
+		f, ok := flags[instructionKey{op.group, op.mnemonic, op.page}]
+		if !ok {
+			f = flags[instructionKey{op.group, op.mnemonic, 1 - op.page}]
+		}
+
+		fmt.Fprintf(&src, "0x%02X: {Page: %d, Code: 0x%02X, Mnemonic: %q, Group: %s", op.code, op.page, op.code, op.mnemonic, op.group)
+		if len(op.operands) > 0 {
+			fmt.Fprintf(&src, ", Operands: []Operand{%s}", strings.Join(op.operands, ", "))
+		}
+		if len(f) > 0 {
+			fmt.Fprintf(&src, ", Flags: %s", strings.Join(f, " | "))
+		}
+		src.WriteString("},\n")
+	}
+	src.WriteString("},\n}\n")
+
+	return format.Source(src.Bytes())
+}
+
+func main() {
+	var (
+		in  = flag.String("in", "", "instruction set document")
+		out = flag.String("out", "", "generated go file")
+	)
+	flag.Parse()
+
+	doc, err := ioutil.ReadFile(*in)
+	if err == nil {
+		var src []byte
+		if src, err = generate(doc); err == nil {
+			err = ioutil.WriteFile(*out, src, 0644)
+		}
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,53 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFlags(t *testing.T) {
+	assert.Equal(t, []string(nil), parseFlags("-------- --------"))
+	assert.Equal(t, []string{"FlagCarry", "FlagZero", "FlagNegative"}, parseFlags("C-ZN---- --------"))
+	assert.Equal(t, []string{"FlagCarry"}, parseFlags("0------- --------"))
+	assert.Equal(t, []string{"FlagZero", "FlagNegative"}, parseFlags("--01----- --------"))
+	assert.Equal(t, []string{"FlagAddressMode"}, parseFlags("----0101 --------"))
+	assert.Equal(t, []string{"FlagOperandSize"}, parseFlags("-------- 10------"))
+	assert.Equal(t, []string{"FlagMathMode"}, parseFlags("-------- --01----"))
+	assert.Equal(t, []string{"FlagInterruptDisable"}, parseFlags("-------- ----1---"))
+	assert.Equal(
+		t,
+		[]string{
+			"FlagCarry",
+			"FlagOverflow",
+			"FlagZero",
+			"FlagNegative",
+			"FlagAddressMode",
+			"FlagInterruptDisable",
+			"FlagOperandSize",
+			"FlagMathMode",
+		},
+		parseFlags("CVZNAAAI OOMM----"),
+	)
+}
+
+func TestGenerateErrors(t *testing.T) {
+	_, err := generate([]byte("<html></html>"))
+	assert.Equal(t, ErrNoOpcodeTable, err)
+}
+
+// TestGenerateUpToDate fails if the instruction set document was edited without running go generate
+func TestGenerateUpToDate(t *testing.T) {
+	doc, err := ioutil.ReadFile("../../../doc/InstructionSet.html")
+	assert.Nil(t, err)
+
+	src, err := generate(doc)
+	assert.Nil(t, err)
+
+	opcodes, err := ioutil.ReadFile("../opcodes.go")
+	assert.Nil(t, err)
+	assert.Equal(t, string(opcodes), string(src))
+}
@@ -0,0 +1,285 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package isa
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Page numbers
+const (
+	// Page0 is the page of opcodes that are a single byte
+	Page0 uint8 = 0
+
+	// Page1 is the page of opcodes that are preceded by EXT
+	Page1 uint8 = 1
+
+	// EXT is the page 0 opcode that executes the next byte as a page 1 opcode
+	EXT uint8 = 0xFF
+)
+
+// Group is the instruction group an opcode belongs to
+type Group uint8
+
+// Instruction groups
+const (
+	GroupBinary Group = iota
+	GroupBranch
+	GroupFloat
+	GroupMove
+	GroupOther
+	GroupStack
+	GroupStatus
+	GroupUnary
+)
+
+var groupNames = [...]string{
+	GroupBinary: "Binary",
+	GroupBranch: "Branch and Jump",
+	GroupFloat:  "Floating Point",
+	GroupMove:   "Move",
+	GroupOther:  "Other",
+	GroupStack:  "Stack",
+	GroupStatus: "Status",
+	GroupUnary:  "Unary",
+}
+
+func (g Group) String() string {
+	return groupNames[g]
+}
+
+// Operand is the kind of an operand, as named in the instruction set
+type Operand uint8
+
+// Operands
+const (
+	OperandR0 Operand = iota
+	OperandR0c
+	OperandR1
+	OperandR1c
+	OperandOFS0
+	OperandOFS1
+	OperandIX0
+	OperandIX1
+	OperandPTR0
+	OperandPTR1
+	OperandDP0
+	OperandDP1
+	OperandSP
+	OperandSB
+	OperandCP
+	OperandST
+	OperandCTR0
+	OperandCTR1
+	OperandCS0
+	OperandCS1
+	OperandIS0
+	OperandIS1
+	OperandIndirectPTR0 // *PTR0
+	OperandIndirectPTR1 // *PTR1
+	OperandStack        // *SP[U8]
+	OperandMemory       // M, a U32 address relative to DP
+	OperandU8
+	OperandS8
+	OperandU16
+	OperandS16
+	OperandU32
+	OperandO // An immediate of the current operand size
+)
+
+var operandNames = [...]string{
+	OperandR0:           "R0",
+	OperandR0c:          "R0c",
+	OperandR1:           "R1",
+	OperandR1c:          "R1c",
+	OperandOFS0:         "OFS0",
+	OperandOFS1:         "OFS1",
+	OperandIX0:          "IX0",
+	OperandIX1:          "IX1",
+	OperandPTR0:         "PTR0",
+	OperandPTR1:         "PTR1",
+	OperandDP0:          "DP0",
+	OperandDP1:          "DP1",
+	OperandSP:           "SP",
+	OperandSB:           "SB",
+	OperandCP:           "CP",
+	OperandST:           "ST",
+	OperandCTR0:         "CTR0",
+	OperandCTR1:         "CTR1",
+	OperandCS0:          "CS0",
+	OperandCS1:          "CS1",
+	OperandIS0:          "IS0",
+	OperandIS1:          "IS1",
+	OperandIndirectPTR0: "*PTR0",
+	OperandIndirectPTR1: "*PTR1",
+	OperandStack:        "*SP[U8]",
+	OperandMemory:       "M",
+	OperandU8:           "U8",
+	OperandS8:           "S8",
+	OperandU16:          "U16",
+	OperandS16:          "S16",
+	OperandU32:          "U32",
+	OperandO:            "O",
+}
+
+func (o Operand) String() string {
+	return operandNames[o]
+}
+
+// IsImmediate is true if the operand is encoded in the bytes following the opcode
+func (o Operand) IsImmediate() bool {
+	return o >= OperandStack
+}
+
+// IsSigned is true if the operand is a signed immediate
+func (o Operand) IsSigned() bool {
+	return (o == OperandS8) || (o == OperandS16)
+}
+
+// Bytes returns the number of bytes the operand occupies after the opcode, given the effective operand size, which
+// promotes 8 bit operands to 16 bits in math modes other than integer (register.EffectiveOperandSize).
+// Operands that are not immediates occupy no bytes.
+func (o Operand) Bytes(operandSize uint8) int {
+	switch o {
+	case OperandStack, OperandU8, OperandS8:
+		return 1
+	case OperandU16, OperandS16:
+		return 2
+	case OperandMemory, OperandU32:
+		return 4
+	case OperandO:
+		return 1 << operandSize
+	}
+
+	return 0
+}
+
+// Flags is the set of status register fields written by an opcode
+type Flags uint8
+
+// Status flags
+const (
+	FlagCarry Flags = 1 << iota
+	FlagOverflow
+	FlagZero
+	FlagNegative
+	FlagAddressMode
+	FlagInterruptDisable
+	FlagOperandSize
+	FlagMathMode
+)
+
+// flagLetters are the letters of each flag in bit order
+const flagLetters = "CVZNAIOM"
+
+// String returns the letter of each flag that is written, or - if it is not, in the order CVZNAIOM
+func (f Flags) String() string {
+	var s strings.Builder
+	for i := 0; i < len(flagLetters); i++ {
+		if f&(1<<i) != 0 {
+			s.WriteByte(flagLetters[i])
+		} else {
+			s.WriteByte('-')
+		}
+	}
+
+	return s.String()
+}
+
+// Opcode describes one opcode of a page
+type Opcode struct {
+	Page     uint8
+	Code     uint8
+	Mnemonic string
+	Group    Group
+	Operands []Operand
+	Flags    Flags
+}
+
+// IsValid is true if the opcode is defined, false if it is reserved
+func (op Opcode) IsValid() bool {
+	return op.Mnemonic != ""
+}
+
+// Immediate returns the immediate operand, if the opcode has one.
+// No opcode has more than one immediate operand.
+func (op Opcode) Immediate() (Operand, bool) {
+	for _, o := range op.Operands {
+		if o.IsImmediate() {
+			return o, true
+		}
+	}
+
+	return 0, false
+}
+
+// IsRelative is true if the opcode is a branch or jump to an address relative to the next instruction
+func (op Opcode) IsRelative() bool {
+	imm, ok := op.Immediate()
+	return ok && (op.Group == GroupBranch) && imm.IsSigned()
+}
+
+// Bytes returns the length of an encoded instruction, including EXT for page 1 and any immediate operand
+func (op Opcode) Bytes(operandSize uint8) int {
+	n := 1 + int(op.Page)
+	if o, ok := op.Immediate(); ok {
+		n += o.Bytes(operandSize)
+	}
+
+	return n
+}
+
+// String returns the opcode as written in the instruction set, EG ADD R0,R0c
+func (op Opcode) String() string {
+	if len(op.Operands) == 0 {
+		return op.Mnemonic
+	}
+
+	ops := make([]string, len(op.Operands))
+	for i, o := range op.Operands {
+		ops[i] = o.String()
+	}
+
+	return fmt.Sprintf("%s %s", op.Mnemonic, strings.Join(ops, ","))
+}
+
+// Lookup returns the opcode of a page, and true if it is valid
+func Lookup(page, code uint8) (Opcode, bool) {
+	if page > Page1 {
+		return Opcode{}, false
+	}
+
+	op := opcodes[page][code]
+	return op, op.IsValid()
+}
+
+// Opcodes returns all valid opcodes, in order of page and code
+func Opcodes() []Opcode {
+	var ops []Opcode
+	for page := range opcodes {
+		for _, op := range opcodes[page] {
+			if op.IsValid() {
+				ops = append(ops, op)
+			}
+		}
+	}
+
+	return ops
+}
+
+// byForm indexes opcodes by the string returned by Opcode.String
+var byForm = func() map[string]Opcode {
+	m := map[string]Opcode{}
+	for _, op := range Opcodes() {
+		m[op.String()] = op
+	}
+
+	return m
+}()
+
+// Find returns the opcode with a mnemonic and operand kinds, and true if there is one
+func Find(mnemonic string, operands ...Operand) (Opcode, bool) {
+	op, ok := byForm[Opcode{Mnemonic: mnemonic, Operands: operands}.String()]
+	return op, ok
+}
@@ -0,0 +1,88 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package isa
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOperand(t *testing.T) {
+	assert.Equal(t, "*SP[U8]", OperandStack.String())
+	assert.False(t, OperandR0.IsImmediate())
+	assert.False(t, OperandIndirectPTR0.IsImmediate())
+	assert.True(t, OperandStack.IsImmediate())
+	assert.True(t, OperandMemory.IsImmediate())
+	assert.True(t, OperandO.IsImmediate())
+	assert.True(t, OperandS16.IsSigned())
+	assert.False(t, OperandU16.IsSigned())
+
+	assert.Equal(t, 0, OperandR0.Bytes(3))
+	assert.Equal(t, 1, OperandStack.Bytes(3))
+	assert.Equal(t, 2, OperandS16.Bytes(0))
+	assert.Equal(t, 4, OperandMemory.Bytes(0))
+	assert.Equal(t, 1, OperandO.Bytes(0))
+	assert.Equal(t, 8, OperandO.Bytes(3))
+}
+
+func TestFlags(t *testing.T) {
+	assert.Equal(t, "--------", Flags(0).String())
+	assert.Equal(t, "CVZN----", (FlagCarry | FlagOverflow | FlagZero | FlagNegative).String())
+	assert.Equal(t, "----AIOM", (FlagAddressMode | FlagInterruptDisable | FlagOperandSize | FlagMathMode).String())
+}
+
+func TestLookup(t *testing.T) {
+	op, ok := Lookup(Page0, 0x00)
+	assert.True(t, ok)
+	assert.Equal(t, "ADC R0,R0c", op.String())
+	assert.Equal(t, GroupBinary, op.Group)
+	assert.Equal(t, FlagCarry|FlagOverflow|FlagZero|FlagNegative, op.Flags)
+	assert.Equal(t, 1, op.Bytes(3))
+
+	op, ok = Lookup(Page0, 0x2A)
+	assert.True(t, ok)
+	assert.Equal(t, "BCC S8", op.String())
+	assert.Equal(t, 2, op.Bytes(3))
+	assert.True(t, op.IsRelative())
+
+	op, ok = Lookup(Page1, 0x00)
+	assert.True(t, ok)
+	assert.Equal(t, "ADD R0,O", op.String())
+	assert.False(t, op.IsRelative())
+	imm, ok := op.Immediate()
+	assert.True(t, ok)
+	assert.Equal(t, OperandO, imm)
+	assert.Equal(t, 3, op.Bytes(0))
+	assert.Equal(t, 10, op.Bytes(3))
+
+	op, ok = Lookup(Page0, EXT)
+	assert.True(t, ok)
+	assert.Equal(t, "EXT", op.String())
+	assert.Equal(t, GroupOther, op.Group)
+
+	// Reserved
+	_, ok = Lookup(Page1, 0xFF)
+	assert.False(t, ok)
+	_, ok = Lookup(2, 0x00)
+	assert.False(t, ok)
+
+	// Every valid opcode is at its own page and code
+	for _, op := range Opcodes() {
+		assert.Equal(t, op, opcodes[op.Page][op.Code])
+	}
+}
+
+func TestFind(t *testing.T) {
+	op, ok := Find("MOV", OperandR0c, OperandMemory)
+	assert.True(t, ok)
+	assert.Equal(t, Page1, op.Page)
+	assert.Equal(t, uint8(0x4B), op.Code)
+
+	op, ok = Find("NOP")
+	assert.True(t, ok)
+	assert.Equal(t, uint8(0xFE), op.Code)
+
+	_, ok = Find("ADC", OperandR0, OperandR1)
+	assert.False(t, ok)
+}
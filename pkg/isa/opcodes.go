@@ -0,0 +1,390 @@
+// Code generated by go run ./gen; DO NOT EDIT.
+
+// SPDX-License-Identifier: Apache-2.0
+
+package isa
+
+// opcodes is every opcode of each page, where reserved opcodes have no mnemonic
+var opcodes = [2][256]Opcode{
+	{
+		0x00: {Page: 0, Code: 0x00, Mnemonic: "ADC", Group: GroupBinary, Operands: []Operand{OperandR0, OperandR0c}, Flags: FlagCarry | FlagOverflow | FlagZero | FlagNegative},
+		0x01: {Page: 0, Code: 0x01, Mnemonic: "ADC", Group: GroupBinary, Operands: []Operand{OperandR1, OperandR1c}, Flags: FlagCarry | FlagOverflow | FlagZero | FlagNegative},
+		0x02: {Page: 0, Code: 0x02, Mnemonic: "ADD", Group: GroupBinary, Operands: []Operand{OperandR0, OperandR0c}, Flags: FlagCarry | FlagOverflow | FlagZero | FlagNegative},
+		0x03: {Page: 0, Code: 0x03, Mnemonic: "ADD", Group: GroupBinary, Operands: []Operand{OperandR1, OperandR1c}, Flags: FlagCarry | FlagOverflow | FlagZero | FlagNegative},
+		0x04: {Page: 0, Code: 0x04, Mnemonic: "ADD", Group: GroupBinary, Operands: []Operand{OperandOFS0, OperandU16}, Flags: FlagCarry | FlagOverflow | FlagZero | FlagNegative},
+		0x05: {Page: 0, Code: 0x05, Mnemonic: "ADD", Group: GroupBinary, Operands: []Operand{OperandOFS1, OperandU16}, Flags: FlagCarry | FlagOverflow | FlagZero | FlagNegative},
+		0x06: {Page: 0, Code: 0x06, Mnemonic: "ADD", Group: GroupBinary, Operands: []Operand{OperandIX0, OperandU16}, Flags: FlagCarry | FlagOverflow | FlagZero | FlagNegative},
+		0x07: {Page: 0, Code: 0x07, Mnemonic: "ADD", Group: GroupBinary, Operands: []Operand{OperandIX1, OperandU16}, Flags: FlagCarry | FlagOverflow | FlagZero | FlagNegative},
+		0x08: {Page: 0, Code: 0x08, Mnemonic: "AND", Group: GroupBinary, Operands: []Operand{OperandR0, OperandR0c}, Flags: FlagZero | FlagNegative},
+		0x09: {Page: 0, Code: 0x09, Mnemonic: "AND", Group: GroupBinary, Operands: []Operand{OperandR1, OperandR1c}, Flags: FlagZero | FlagNegative},
+		0x0A: {Page: 0, Code: 0x0A, Mnemonic: "CMP", Group: GroupBinary, Operands: []Operand{OperandR0, OperandR0c}, Flags: FlagCarry | FlagOverflow | FlagZero},
+		0x0B: {Page: 0, Code: 0x0B, Mnemonic: "CMP", Group: GroupBinary, Operands: []Operand{OperandR1, OperandR1c}, Flags: FlagCarry | FlagOverflow | FlagZero},
+		0x0C: {Page: 0, Code: 0x0C, Mnemonic: "CMP", Group: GroupBinary, Operands: []Operand{OperandOFS0, OperandU16}, Flags: FlagCarry | FlagOverflow | FlagZero},
+		0x0D: {Page: 0, Code: 0x0D, Mnemonic: "CMP", Group: GroupBinary, Operands: []Operand{OperandOFS1, OperandU16}, Flags: FlagCarry | FlagOverflow | FlagZero},
+		0x0E: {Page: 0, Code: 0x0E, Mnemonic: "CMP", Group: GroupBinary, Operands: []Operand{OperandIX0, OperandU16}, Flags: FlagCarry | FlagOverflow | FlagZero},
+		0x0F: {Page: 0, Code: 0x0F, Mnemonic: "CMP", Group: GroupBinary, Operands: []Operand{OperandIX1, OperandU16}, Flags: FlagCarry | FlagOverflow | FlagZero},
+		0x10: {Page: 0, Code: 0x10, Mnemonic: "DIVS", Group: GroupBinary, Operands: []Operand{OperandR0, OperandR0c}, Flags: FlagOverflow | FlagZero | FlagNegative},
+		0x11: {Page: 0, Code: 0x11, Mnemonic: "DIVS", Group: GroupBinary, Operands: []Operand{OperandR1, OperandR1c}, Flags: FlagOverflow | FlagZero | FlagNegative},
+		0x12: {Page: 0, Code: 0x12, Mnemonic: "DIVU", Group: GroupBinary, Operands: []Operand{OperandR0, OperandR0c}, Flags: FlagOverflow | FlagZero | FlagNegative},
+		0x13: {Page: 0, Code: 0x13, Mnemonic: "DIVU", Group: GroupBinary, Operands: []Operand{OperandR1, OperandR1c}, Flags: FlagOverflow | FlagZero | FlagNegative},
+		0x14: {Page: 0, Code: 0x14, Mnemonic: "MULS", Group: GroupBinary, Operands: []Operand{OperandR0, OperandR0c}, Flags: FlagZero | FlagNegative},
+		0x15: {Page: 0, Code: 0x15, Mnemonic: "MULS", Group: GroupBinary, Operands: []Operand{OperandR1, OperandR1c}, Flags: FlagZero | FlagNegative},
+		0x16: {Page: 0, Code: 0x16, Mnemonic: "MULU", Group: GroupBinary, Operands: []Operand{OperandR0, OperandR0c}, Flags: FlagZero | FlagNegative},
+		0x17: {Page: 0, Code: 0x17, Mnemonic: "MULU", Group: GroupBinary, Operands: []Operand{OperandR1, OperandR1c}, Flags: FlagZero | FlagNegative},
+		0x18: {Page: 0, Code: 0x18, Mnemonic: "OR", Group: GroupBinary, Operands: []Operand{OperandR0, OperandR0c}, Flags: FlagZero | FlagNegative},
+		0x19: {Page: 0, Code: 0x19, Mnemonic: "OR", Group: GroupBinary, Operands: []Operand{OperandR1, OperandR1c}, Flags: FlagZero | FlagNegative},
+		0x1A: {Page: 0, Code: 0x1A, Mnemonic: "SHA", Group: GroupBinary, Operands: []Operand{OperandR0, OperandR0c}, Flags: FlagCarry | FlagZero | FlagNegative},
+		0x1B: {Page: 0, Code: 0x1B, Mnemonic: "SHA", Group: GroupBinary, Operands: []Operand{OperandR1, OperandR1c}, Flags: FlagCarry | FlagZero | FlagNegative},
+		0x1C: {Page: 0, Code: 0x1C, Mnemonic: "SHL", Group: GroupBinary, Operands: []Operand{OperandR0, OperandR0c}, Flags: FlagCarry | FlagZero | FlagNegative},
+		0x1D: {Page: 0, Code: 0x1D, Mnemonic: "SHL", Group: GroupBinary, Operands: []Operand{OperandR1, OperandR1c}, Flags: FlagCarry | FlagZero | FlagNegative},
+		0x1E: {Page: 0, Code: 0x1E, Mnemonic: "SHR", Group: GroupBinary, Operands: []Operand{OperandR0, OperandR0c}, Flags: FlagCarry | FlagZero | FlagNegative},
+		0x1F: {Page: 0, Code: 0x1F, Mnemonic: "SHR", Group: GroupBinary, Operands: []Operand{OperandR1, OperandR1c}, Flags: FlagCarry | FlagZero | FlagNegative},
+		0x20: {Page: 0, Code: 0x20, Mnemonic: "SBB", Group: GroupBinary, Operands: []Operand{OperandR0, OperandR0c}, Flags: FlagCarry | FlagOverflow | FlagZero | FlagNegative},
+		0x21: {Page: 0, Code: 0x21, Mnemonic: "SBB", Group: GroupBinary, Operands: []Operand{OperandR1, OperandR1c}, Flags: FlagCarry | FlagOverflow | FlagZero | FlagNegative},
+		0x22: {Page: 0, Code: 0x22, Mnemonic: "SUB", Group: GroupBinary, Operands: []Operand{OperandR0, OperandR0c}, Flags: FlagCarry | FlagOverflow | FlagZero | FlagNegative},
+		0x23: {Page: 0, Code: 0x23, Mnemonic: "SUB", Group: GroupBinary, Operands: []Operand{OperandR1, OperandR1c}, Flags: FlagCarry | FlagOverflow | FlagZero | FlagNegative},
+		0x24: {Page: 0, Code: 0x24, Mnemonic: "SUB", Group: GroupBinary, Operands: []Operand{OperandOFS0, OperandU16}, Flags: FlagCarry | FlagOverflow | FlagZero | FlagNegative},
+		0x25: {Page: 0, Code: 0x25, Mnemonic: "SUB", Group: GroupBinary, Operands: []Operand{OperandOFS1, OperandU16}, Flags: FlagCarry | FlagOverflow | FlagZero | FlagNegative},
+		0x26: {Page: 0, Code: 0x26, Mnemonic: "SUB", Group: GroupBinary, Operands: []Operand{OperandIX0, OperandU16}, Flags: FlagCarry | FlagOverflow | FlagZero | FlagNegative},
+		0x27: {Page: 0, Code: 0x27, Mnemonic: "SUB", Group: GroupBinary, Operands: []Operand{OperandIX1, OperandU16}, Flags: FlagCarry | FlagOverflow | FlagZero | FlagNegative},
+		0x28: {Page: 0, Code: 0x28, Mnemonic: "XOR", Group: GroupBinary, Operands: []Operand{OperandR0, OperandR0c}, Flags: FlagZero | FlagNegative},
+		0x29: {Page: 0, Code: 0x29, Mnemonic: "XOR", Group: GroupBinary, Operands: []Operand{OperandR1, OperandR1c}, Flags: FlagZero | FlagNegative},
+		0x2A: {Page: 0, Code: 0x2A, Mnemonic: "BCC", Group: GroupBranch, Operands: []Operand{OperandS8}},
+		0x2B: {Page: 0, Code: 0x2B, Mnemonic: "BCS", Group: GroupBranch, Operands: []Operand{OperandS8}},
+		0x2C: {Page: 0, Code: 0x2C, Mnemonic: "BVC", Group: GroupBranch, Operands: []Operand{OperandS8}},
+		0x2D: {Page: 0, Code: 0x2D, Mnemonic: "BVS", Group: GroupBranch, Operands: []Operand{OperandS8}},
+		0x2E: {Page: 0, Code: 0x2E, Mnemonic: "BEQ", Group: GroupBranch, Operands: []Operand{OperandS8}},
+		0x2F: {Page: 0, Code: 0x2F, Mnemonic: "BNE", Group: GroupBranch, Operands: []Operand{OperandS8}},
+		0x30: {Page: 0, Code: 0x30, Mnemonic: "BMI", Group: GroupBranch, Operands: []Operand{OperandS8}},
+		0x31: {Page: 0, Code: 0x31, Mnemonic: "BPL", Group: GroupBranch, Operands: []Operand{OperandS8}},
+		0x32: {Page: 0, Code: 0x32, Mnemonic: "JMA", Group: GroupBranch, Operands: []Operand{OperandR0}},
+		0x33: {Page: 0, Code: 0x33, Mnemonic: "JMA", Group: GroupBranch, Operands: []Operand{OperandU32}},
+		0x34: {Page: 0, Code: 0x34, Mnemonic: "JMP", Group: GroupBranch, Operands: []Operand{OperandR0}},
+		0x35: {Page: 0, Code: 0x35, Mnemonic: "JMP", Group: GroupBranch, Operands: []Operand{OperandS16}},
+		0x36: {Page: 0, Code: 0x36, Mnemonic: "JSA", Group: GroupBranch, Operands: []Operand{OperandR0}},
+		0x37: {Page: 0, Code: 0x37, Mnemonic: "JSA", Group: GroupBranch, Operands: []Operand{OperandU32}},
+		0x38: {Page: 0, Code: 0x38, Mnemonic: "JSR", Group: GroupBranch, Operands: []Operand{OperandR0}},
+		0x39: {Page: 0, Code: 0x39, Mnemonic: "JSR", Group: GroupBranch, Operands: []Operand{OperandS16}},
+		0x3A: {Page: 0, Code: 0x3A, Mnemonic: "RTS", Group: GroupBranch},
+		0x3B: {Page: 0, Code: 0x3B, Mnemonic: "RTS", Group: GroupBranch, Operands: []Operand{OperandU8}},
+		0x3C: {Page: 0, Code: 0x3C, Mnemonic: "RTI", Group: GroupBranch, Flags: FlagCarry | FlagOverflow | FlagZero | FlagNegative | FlagAddressMode | FlagInterruptDisable | FlagOperandSize | FlagMathMode},
+		0x3D: {Page: 0, Code: 0x3D, Mnemonic: "FS2F", Group: GroupFloat, Operands: []Operand{OperandR0}, Flags: FlagZero | FlagNegative},
+		0x3E: {Page: 0, Code: 0x3E, Mnemonic: "FS2F", Group: GroupFloat, Operands: []Operand{OperandR0c}, Flags: FlagZero | FlagNegative},
+		0x3F: {Page: 0, Code: 0x3F, Mnemonic: "FS2F", Group: GroupFloat, Operands: []Operand{OperandR1}, Flags: FlagZero | FlagNegative},
+		0x40: {Page: 0, Code: 0x40, Mnemonic: "FS2F", Group: GroupFloat, Operands: []Operand{OperandR1c}, Flags: FlagZero | FlagNegative},
+		0x41: {Page: 0, Code: 0x41, Mnemonic: "FABS", Group: GroupFloat, Operands: []Operand{OperandR0}, Flags: FlagZero | FlagNegative},
+		0x42: {Page: 0, Code: 0x42, Mnemonic: "FABS", Group: GroupFloat, Operands: []Operand{OperandR0c}, Flags: FlagZero | FlagNegative},
+		0x43: {Page: 0, Code: 0x43, Mnemonic: "FABS", Group: GroupFloat, Operands: []Operand{OperandR1}, Flags: FlagZero | FlagNegative},
+		0x44: {Page: 0, Code: 0x44, Mnemonic: "FABS", Group: GroupFloat, Operands: []Operand{OperandR1c}, Flags: FlagZero | FlagNegative},
+		0x45: {Page: 0, Code: 0x45, Mnemonic: "FACS", Group: GroupFloat, Operands: []Operand{OperandR0, OperandR0c}, Flags: FlagZero},
+		0x46: {Page: 0, Code: 0x46, Mnemonic: "FACS", Group: GroupFloat, Operands: []Operand{OperandR1, OperandR1c}, Flags: FlagZero},
+		0x47: {Page: 0, Code: 0x47, Mnemonic: "FASN", Group: GroupFloat, Operands: []Operand{OperandR0, OperandR0c}, Flags: FlagZero | FlagNegative},
+		0x48: {Page: 0, Code: 0x48, Mnemonic: "FASN", Group: GroupFloat, Operands: []Operand{OperandR1, OperandR1c}, Flags: FlagZero | FlagNegative},
+		0x49: {Page: 0, Code: 0x49, Mnemonic: "FATN", Group: GroupFloat, Operands: []Operand{OperandR0, OperandR0c}, Flags: FlagZero | FlagNegative},
+		0x4A: {Page: 0, Code: 0x4A, Mnemonic: "FATN", Group: GroupFloat, Operands: []Operand{OperandR1, OperandR1c}, Flags: FlagZero | FlagNegative},
+		0x4B: {Page: 0, Code: 0x4B, Mnemonic: "FCEL", Group: GroupFloat, Operands: []Operand{OperandR0}, Flags: FlagZero | FlagNegative},
+		0x4C: {Page: 0, Code: 0x4C, Mnemonic: "FCEL", Group: GroupFloat, Operands: []Operand{OperandR0c}, Flags: FlagZero | FlagNegative},
+		0x4D: {Page: 0, Code: 0x4D, Mnemonic: "FCEL", Group: GroupFloat, Operands: []Operand{OperandR1}, Flags: FlagZero | FlagNegative},
+		0x4E: {Page: 0, Code: 0x4E, Mnemonic: "FCEL", Group: GroupFloat, Operands: []Operand{OperandR1c}, Flags: FlagZero | FlagNegative},
+		0x4F: {Page: 0, Code: 0x4F, Mnemonic: "FCOS", Group: GroupFloat, Operands: []Operand{OperandR0, OperandR0c}, Flags: FlagZero | FlagNegative},
+		0x50: {Page: 0, Code: 0x50, Mnemonic: "FCOS", Group: GroupFloat, Operands: []Operand{OperandR1, OperandR1c}, Flags: FlagZero | FlagNegative},
+		0x51: {Page: 0, Code: 0x51, Mnemonic: "FF2S", Group: GroupFloat, Operands: []Operand{OperandR0}, Flags: FlagZero | FlagNegative},
+		0x52: {Page: 0, Code: 0x52, Mnemonic: "FF2S", Group: GroupFloat, Operands: []Operand{OperandR0c}, Flags: FlagZero | FlagNegative},
+		0x53: {Page: 0, Code: 0x53, Mnemonic: "FF2S", Group: GroupFloat, Operands: []Operand{OperandR1}, Flags: FlagZero | FlagNegative},
+		0x54: {Page: 0, Code: 0x54, Mnemonic: "FF2S", Group: GroupFloat, Operands: []Operand{OperandR1c}, Flags: FlagZero | FlagNegative},
+		0x55: {Page: 0, Code: 0x55, Mnemonic: "FF2U", Group: GroupFloat, Operands: []Operand{OperandR0}, Flags: FlagZero | FlagNegative},
+		0x56: {Page: 0, Code: 0x56, Mnemonic: "FF2U", Group: GroupFloat, Operands: []Operand{OperandR0c}, Flags: FlagZero | FlagNegative},
+		0x57: {Page: 0, Code: 0x57, Mnemonic: "FF2U", Group: GroupFloat, Operands: []Operand{OperandR1}, Flags: FlagZero | FlagNegative},
+		0x58: {Page: 0, Code: 0x58, Mnemonic: "FF2U", Group: GroupFloat, Operands: []Operand{OperandR1c}, Flags: FlagZero | FlagNegative},
+		0x59: {Page: 0, Code: 0x59, Mnemonic: "FFLR", Group: GroupFloat, Operands: []Operand{OperandR0}, Flags: FlagZero | FlagNegative},
+		0x5A: {Page: 0, Code: 0x5A, Mnemonic: "FFLR", Group: GroupFloat, Operands: []Operand{OperandR0c}, Flags: FlagZero | FlagNegative},
+		0x5B: {Page: 0, Code: 0x5B, Mnemonic: "FFLR", Group: GroupFloat, Operands: []Operand{OperandR1}, Flags: FlagZero | FlagNegative},
+		0x5C: {Page: 0, Code: 0x5C, Mnemonic: "FFLR", Group: GroupFloat, Operands: []Operand{OperandR1c}, Flags: FlagZero | FlagNegative},
+		0x5D: {Page: 0, Code: 0x5D, Mnemonic: "FLOG", Group: GroupFloat, Operands: []Operand{OperandR0, OperandR0c}, Flags: FlagZero | FlagNegative},
+		0x5E: {Page: 0, Code: 0x5E, Mnemonic: "FLOG", Group: GroupFloat, Operands: []Operand{OperandR1, OperandR1c}, Flags: FlagZero | FlagNegative},
+		0x5F: {Page: 0, Code: 0x5F, Mnemonic: "FNLG", Group: GroupFloat, Operands: []Operand{OperandR0, OperandR0c}, Flags: FlagZero | FlagNegative},
+		0x60: {Page: 0, Code: 0x60, Mnemonic: "FNLG", Group: GroupFloat, Operands: []Operand{OperandR1, OperandR1c}, Flags: FlagZero | FlagNegative},
+		0x61: {Page: 0, Code: 0x61, Mnemonic: "FPOW", Group: GroupFloat, Operands: []Operand{OperandR0, OperandR0c}, Flags: FlagOverflow | FlagZero | FlagNegative},
+		0x62: {Page: 0, Code: 0x62, Mnemonic: "FPOW", Group: GroupFloat, Operands: []Operand{OperandR1, OperandR1c}, Flags: FlagOverflow | FlagZero | FlagNegative},
+		0x63: {Page: 0, Code: 0x63, Mnemonic: "FSIN", Group: GroupFloat, Operands: []Operand{OperandR0, OperandR0c}, Flags: FlagZero | FlagNegative},
+		0x64: {Page: 0, Code: 0x64, Mnemonic: "FSIN", Group: GroupFloat, Operands: []Operand{OperandR1, OperandR1c}, Flags: FlagZero | FlagNegative},
+		0x65: {Page: 0, Code: 0x65, Mnemonic: "FSQR", Group: GroupFloat, Operands: []Operand{OperandR0, OperandR0c}, Flags: FlagZero | FlagNegative},
+		0x66: {Page: 0, Code: 0x66, Mnemonic: "FSQR", Group: GroupFloat, Operands: []Operand{OperandR1, OperandR1c}, Flags: FlagZero | FlagNegative},
+		0x67: {Page: 0, Code: 0x67, Mnemonic: "FTAN", Group: GroupFloat, Operands: []Operand{OperandR0, OperandR0c}, Flags: FlagZero | FlagNegative},
+		0x68: {Page: 0, Code: 0x68, Mnemonic: "FTAN", Group: GroupFloat, Operands: []Operand{OperandR1, OperandR1c}, Flags: FlagZero | FlagNegative},
+		0x69: {Page: 0, Code: 0x69, Mnemonic: "FU2F", Group: GroupFloat, Operands: []Operand{OperandR0}, Flags: FlagZero | FlagNegative},
+		0x6A: {Page: 0, Code: 0x6A, Mnemonic: "FU2F", Group: GroupFloat, Operands: []Operand{OperandR0c}, Flags: FlagZero | FlagNegative},
+		0x6B: {Page: 0, Code: 0x6B, Mnemonic: "FU2F", Group: GroupFloat, Operands: []Operand{OperandR1}, Flags: FlagZero | FlagNegative},
+		0x6C: {Page: 0, Code: 0x6C, Mnemonic: "FU2F", Group: GroupFloat, Operands: []Operand{OperandR1c}, Flags: FlagZero | FlagNegative},
+		0x6D: {Page: 0, Code: 0x6D, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandPTR0, OperandU32}, Flags: FlagZero | FlagNegative},
+		0x6E: {Page: 0, Code: 0x6E, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandOFS0, OperandU16}, Flags: FlagZero | FlagNegative},
+		0x6F: {Page: 0, Code: 0x6F, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandIX0, OperandU16}, Flags: FlagZero | FlagNegative},
+		0x70: {Page: 0, Code: 0x70, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandPTR1, OperandU32}, Flags: FlagZero | FlagNegative},
+		0x71: {Page: 0, Code: 0x71, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandOFS1, OperandU16}, Flags: FlagZero | FlagNegative},
+		0x72: {Page: 0, Code: 0x72, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandIX1, OperandU16}, Flags: FlagZero | FlagNegative},
+		0x73: {Page: 0, Code: 0x73, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandR0, OperandR0c}, Flags: FlagZero | FlagNegative},
+		0x74: {Page: 0, Code: 0x74, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandR0, OperandR1}, Flags: FlagZero | FlagNegative},
+		0x75: {Page: 0, Code: 0x75, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandR0, OperandR1c}, Flags: FlagZero | FlagNegative},
+		0x76: {Page: 0, Code: 0x76, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandR0c, OperandR0}, Flags: FlagZero | FlagNegative},
+		0x77: {Page: 0, Code: 0x77, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandR0c, OperandR1}, Flags: FlagZero | FlagNegative},
+		0x78: {Page: 0, Code: 0x78, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandR0c, OperandR1c}, Flags: FlagZero | FlagNegative},
+		0x79: {Page: 0, Code: 0x79, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandR1, OperandR0}, Flags: FlagZero | FlagNegative},
+		0x7A: {Page: 0, Code: 0x7A, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandR1, OperandR0c}, Flags: FlagZero | FlagNegative},
+		0x7B: {Page: 0, Code: 0x7B, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandR1, OperandR1c}, Flags: FlagZero | FlagNegative},
+		0x7C: {Page: 0, Code: 0x7C, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandR1c, OperandR0}, Flags: FlagZero | FlagNegative},
+		0x7D: {Page: 0, Code: 0x7D, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandR1c, OperandR0c}, Flags: FlagZero | FlagNegative},
+		0x7E: {Page: 0, Code: 0x7E, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandR1c, OperandR1}, Flags: FlagZero | FlagNegative},
+		0x7F: {Page: 0, Code: 0x7F, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandR0, OperandIndirectPTR0}, Flags: FlagZero | FlagNegative},
+		0x80: {Page: 0, Code: 0x80, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandR0, OperandOFS0}, Flags: FlagZero | FlagNegative},
+		0x81: {Page: 0, Code: 0x81, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandR0, OperandIX0}, Flags: FlagZero | FlagNegative},
+		0x82: {Page: 0, Code: 0x82, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandIndirectPTR0, OperandR0}, Flags: FlagZero | FlagNegative},
+		0x83: {Page: 0, Code: 0x83, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandOFS0, OperandR0}, Flags: FlagZero | FlagNegative},
+		0x84: {Page: 0, Code: 0x84, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandIX0, OperandR0}, Flags: FlagZero | FlagNegative},
+		0x85: {Page: 0, Code: 0x85, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandR1, OperandIndirectPTR1}, Flags: FlagZero | FlagNegative},
+		0x86: {Page: 0, Code: 0x86, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandR1, OperandOFS1}, Flags: FlagZero | FlagNegative},
+		0x87: {Page: 0, Code: 0x87, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandR1, OperandIX1}, Flags: FlagZero | FlagNegative},
+		0x88: {Page: 0, Code: 0x88, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandIndirectPTR1, OperandR1}, Flags: FlagZero | FlagNegative},
+		0x89: {Page: 0, Code: 0x89, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandOFS1, OperandR1}, Flags: FlagZero | FlagNegative},
+		0x8A: {Page: 0, Code: 0x8A, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandIX1, OperandR1}, Flags: FlagZero | FlagNegative},
+		0x8B: {Page: 0, Code: 0x8B, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandR0, OperandStack}, Flags: FlagZero | FlagNegative},
+		0x8C: {Page: 0, Code: 0x8C, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandR0c, OperandStack}, Flags: FlagZero | FlagNegative},
+		0x8D: {Page: 0, Code: 0x8D, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandR1, OperandStack}, Flags: FlagZero | FlagNegative},
+		0x8E: {Page: 0, Code: 0x8E, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandR1c, OperandStack}, Flags: FlagZero | FlagNegative},
+		0x8F: {Page: 0, Code: 0x8F, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandR0, OperandMemory}, Flags: FlagZero | FlagNegative},
+		0x90: {Page: 0, Code: 0x90, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandStack, OperandR0}, Flags: FlagZero | FlagNegative},
+		0x91: {Page: 0, Code: 0x91, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandStack, OperandR0c}, Flags: FlagZero | FlagNegative},
+		0x92: {Page: 0, Code: 0x92, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandStack, OperandR1}, Flags: FlagZero | FlagNegative},
+		0x93: {Page: 0, Code: 0x93, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandStack, OperandR1c}, Flags: FlagZero | FlagNegative},
+		0x94: {Page: 0, Code: 0x94, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandMemory, OperandR0}, Flags: FlagZero | FlagNegative},
+		0x95: {Page: 0, Code: 0x95, Mnemonic: "SWP", Group: GroupMove, Operands: []Operand{OperandR0, OperandR0c}, Flags: FlagZero | FlagNegative},
+		0x96: {Page: 0, Code: 0x96, Mnemonic: "SWP", Group: GroupMove, Operands: []Operand{OperandR0, OperandR1}, Flags: FlagZero | FlagNegative},
+		0x97: {Page: 0, Code: 0x97, Mnemonic: "SWP", Group: GroupMove, Operands: []Operand{OperandR0, OperandR1c}, Flags: FlagZero | FlagNegative},
+		0x98: {Page: 0, Code: 0x98, Mnemonic: "SWP", Group: GroupMove, Operands: []Operand{OperandR0c, OperandR1}, Flags: FlagZero | FlagNegative},
+		0x99: {Page: 0, Code: 0x99, Mnemonic: "SWP", Group: GroupMove, Operands: []Operand{OperandR0c, OperandR1c}, Flags: FlagZero | FlagNegative},
+		0x9A: {Page: 0, Code: 0x9A, Mnemonic: "SWP", Group: GroupMove, Operands: []Operand{OperandR1, OperandR1c}, Flags: FlagZero | FlagNegative},
+		0x9B: {Page: 0, Code: 0x9B, Mnemonic: "SWP", Group: GroupMove, Operands: []Operand{OperandR0, OperandIndirectPTR0}, Flags: FlagZero | FlagNegative},
+		0x9C: {Page: 0, Code: 0x9C, Mnemonic: "SWP", Group: GroupMove, Operands: []Operand{OperandR0, OperandOFS0}, Flags: FlagZero | FlagNegative},
+		0x9D: {Page: 0, Code: 0x9D, Mnemonic: "SWP", Group: GroupMove, Operands: []Operand{OperandR0, OperandIX0}, Flags: FlagZero | FlagNegative},
+		0x9E: {Page: 0, Code: 0x9E, Mnemonic: "SWP", Group: GroupMove, Operands: []Operand{OperandR1, OperandIndirectPTR1}, Flags: FlagZero | FlagNegative},
+		0x9F: {Page: 0, Code: 0x9F, Mnemonic: "SWP", Group: GroupMove, Operands: []Operand{OperandR1, OperandOFS1}, Flags: FlagZero | FlagNegative},
+		0xA0: {Page: 0, Code: 0xA0, Mnemonic: "SWP", Group: GroupMove, Operands: []Operand{OperandR1, OperandIX1}, Flags: FlagZero | FlagNegative},
+		0xA1: {Page: 0, Code: 0xA1, Mnemonic: "PSH", Group: GroupStack, Operands: []Operand{OperandCP}},
+		0xA2: {Page: 0, Code: 0xA2, Mnemonic: "PSH", Group: GroupStack, Operands: []Operand{OperandST}},
+		0xA3: {Page: 0, Code: 0xA3, Mnemonic: "PSH", Group: GroupStack, Operands: []Operand{OperandR0}},
+		0xA4: {Page: 0, Code: 0xA4, Mnemonic: "PSH", Group: GroupStack, Operands: []Operand{OperandR0c}},
+		0xA5: {Page: 0, Code: 0xA5, Mnemonic: "PSH", Group: GroupStack, Operands: []Operand{OperandR1}},
+		0xA6: {Page: 0, Code: 0xA6, Mnemonic: "PSH", Group: GroupStack, Operands: []Operand{OperandR1c}},
+		0xA7: {Page: 0, Code: 0xA7, Mnemonic: "PSH", Group: GroupStack, Operands: []Operand{OperandDP0}},
+		0xA8: {Page: 0, Code: 0xA8, Mnemonic: "PSH", Group: GroupStack, Operands: []Operand{OperandPTR0}},
+		0xA9: {Page: 0, Code: 0xA9, Mnemonic: "PSH", Group: GroupStack, Operands: []Operand{OperandOFS0}},
+		0xAA: {Page: 0, Code: 0xAA, Mnemonic: "PSH", Group: GroupStack, Operands: []Operand{OperandIX0}},
+		0xAB: {Page: 0, Code: 0xAB, Mnemonic: "PSH", Group: GroupStack, Operands: []Operand{OperandDP1}},
+		0xAC: {Page: 0, Code: 0xAC, Mnemonic: "PSH", Group: GroupStack, Operands: []Operand{OperandPTR1}},
+		0xAD: {Page: 0, Code: 0xAD, Mnemonic: "PSH", Group: GroupStack, Operands: []Operand{OperandOFS1}},
+		0xAE: {Page: 0, Code: 0xAE, Mnemonic: "PSH", Group: GroupStack, Operands: []Operand{OperandIX1}},
+		0xAF: {Page: 0, Code: 0xAF, Mnemonic: "PUL", Group: GroupStack, Operands: []Operand{OperandCP}, Flags: FlagZero | FlagNegative},
+		0xB0: {Page: 0, Code: 0xB0, Mnemonic: "PUL", Group: GroupStack, Operands: []Operand{OperandST}, Flags: FlagZero | FlagNegative},
+		0xB1: {Page: 0, Code: 0xB1, Mnemonic: "PUL", Group: GroupStack, Operands: []Operand{OperandR0}, Flags: FlagZero | FlagNegative},
+		0xB2: {Page: 0, Code: 0xB2, Mnemonic: "PUL", Group: GroupStack, Operands: []Operand{OperandR0c}, Flags: FlagZero | FlagNegative},
+		0xB3: {Page: 0, Code: 0xB3, Mnemonic: "PUL", Group: GroupStack, Operands: []Operand{OperandR1}, Flags: FlagZero | FlagNegative},
+		0xB4: {Page: 0, Code: 0xB4, Mnemonic: "PUL", Group: GroupStack, Operands: []Operand{OperandR1c}, Flags: FlagZero | FlagNegative},
+		0xB5: {Page: 0, Code: 0xB5, Mnemonic: "PUL", Group: GroupStack, Operands: []Operand{OperandDP0}, Flags: FlagZero | FlagNegative},
+		0xB6: {Page: 0, Code: 0xB6, Mnemonic: "PUL", Group: GroupStack, Operands: []Operand{OperandPTR0}, Flags: FlagZero | FlagNegative},
+		0xB7: {Page: 0, Code: 0xB7, Mnemonic: "PUL", Group: GroupStack, Operands: []Operand{OperandOFS0}, Flags: FlagZero | FlagNegative},
+		0xB8: {Page: 0, Code: 0xB8, Mnemonic: "PUL", Group: GroupStack, Operands: []Operand{OperandIX0}, Flags: FlagZero | FlagNegative},
+		0xB9: {Page: 0, Code: 0xB9, Mnemonic: "PUL", Group: GroupStack, Operands: []Operand{OperandDP1}, Flags: FlagZero | FlagNegative},
+		0xBA: {Page: 0, Code: 0xBA, Mnemonic: "PUL", Group: GroupStack, Operands: []Operand{OperandPTR1}, Flags: FlagZero | FlagNegative},
+		0xBB: {Page: 0, Code: 0xBB, Mnemonic: "PUL", Group: GroupStack, Operands: []Operand{OperandOFS1}, Flags: FlagZero | FlagNegative},
+		0xBC: {Page: 0, Code: 0xBC, Mnemonic: "PUL", Group: GroupStack, Operands: []Operand{OperandIX1}, Flags: FlagZero | FlagNegative},
+		0xBD: {Page: 0, Code: 0xBD, Mnemonic: "SSP", Group: GroupStack, Operands: []Operand{OperandU8}},
+		0xBE: {Page: 0, Code: 0xBE, Mnemonic: "CLC", Group: GroupStatus, Flags: FlagCarry},
+		0xBF: {Page: 0, Code: 0xBF, Mnemonic: "SEC", Group: GroupStatus, Flags: FlagCarry},
+		0xC0: {Page: 0, Code: 0xC0, Mnemonic: "SDAM*", Group: GroupStatus, Flags: FlagAddressMode},
+		0xC1: {Page: 0, Code: 0xC1, Mnemonic: "SDAM*()", Group: GroupStatus, Flags: FlagAddressMode},
+		0xC2: {Page: 0, Code: 0xC2, Mnemonic: "SDAM*[]", Group: GroupStatus, Flags: FlagAddressMode},
+		0xC3: {Page: 0, Code: 0xC3, Mnemonic: "SDAM*([])", Group: GroupStatus, Flags: FlagAddressMode},
+		0xC4: {Page: 0, Code: 0xC4, Mnemonic: "SDAM**", Group: GroupStatus, Flags: FlagAddressMode},
+		0xC5: {Page: 0, Code: 0xC5, Mnemonic: "SDAM**()", Group: GroupStatus, Flags: FlagAddressMode},
+		0xC6: {Page: 0, Code: 0xC6, Mnemonic: "SDAM**[]", Group: GroupStatus, Flags: FlagAddressMode},
+		0xC7: {Page: 0, Code: 0xC7, Mnemonic: "SDAM**([])", Group: GroupStatus, Flags: FlagAddressMode},
+		0xC8: {Page: 0, Code: 0xC8, Mnemonic: "SCAM*", Group: GroupStatus, Flags: FlagAddressMode},
+		0xC9: {Page: 0, Code: 0xC9, Mnemonic: "SCAM*()", Group: GroupStatus, Flags: FlagAddressMode},
+		0xCA: {Page: 0, Code: 0xCA, Mnemonic: "SCAM*[]", Group: GroupStatus, Flags: FlagAddressMode},
+		0xCB: {Page: 0, Code: 0xCB, Mnemonic: "SCAM*([])", Group: GroupStatus, Flags: FlagAddressMode},
+		0xCC: {Page: 0, Code: 0xCC, Mnemonic: "SCAM**", Group: GroupStatus, Flags: FlagAddressMode},
+		0xCD: {Page: 0, Code: 0xCD, Mnemonic: "SCAM*(*)", Group: GroupStatus, Flags: FlagAddressMode},
+		0xCE: {Page: 0, Code: 0xCE, Mnemonic: "SCAM*[*]", Group: GroupStatus, Flags: FlagAddressMode},
+		0xCF: {Page: 0, Code: 0xCF, Mnemonic: "SCAM*([*])", Group: GroupStatus, Flags: FlagAddressMode},
+		0xD0: {Page: 0, Code: 0xD0, Mnemonic: "SOS8", Group: GroupStatus, Flags: FlagOperandSize},
+		0xD1: {Page: 0, Code: 0xD1, Mnemonic: "SOS16", Group: GroupStatus, Flags: FlagOperandSize},
+		0xD2: {Page: 0, Code: 0xD2, Mnemonic: "SOS32", Group: GroupStatus, Flags: FlagOperandSize},
+		0xD3: {Page: 0, Code: 0xD3, Mnemonic: "SOS64", Group: GroupStatus, Flags: FlagOperandSize},
+		0xD4: {Page: 0, Code: 0xD4, Mnemonic: "SMMI", Group: GroupStatus, Flags: FlagMathMode},
+		0xD5: {Page: 0, Code: 0xD5, Mnemonic: "SMMR", Group: GroupStatus, Flags: FlagMathMode},
+		0xD6: {Page: 0, Code: 0xD6, Mnemonic: "SMMX", Group: GroupStatus, Flags: FlagMathMode},
+		0xD7: {Page: 0, Code: 0xD7, Mnemonic: "SMMF", Group: GroupStatus, Flags: FlagMathMode},
+		0xD8: {Page: 0, Code: 0xD8, Mnemonic: "CLI", Group: GroupStatus, Flags: FlagInterruptDisable},
+		0xD9: {Page: 0, Code: 0xD9, Mnemonic: "SEI", Group: GroupStatus, Flags: FlagInterruptDisable},
+		0xDA: {Page: 0, Code: 0xDA, Mnemonic: "DEC", Group: GroupUnary, Operands: []Operand{OperandR0}, Flags: FlagCarry | FlagOverflow | FlagZero | FlagNegative},
+		0xDB: {Page: 0, Code: 0xDB, Mnemonic: "DEC", Group: GroupUnary, Operands: []Operand{OperandR1}, Flags: FlagCarry | FlagOverflow | FlagZero | FlagNegative},
+		0xDC: {Page: 0, Code: 0xDC, Mnemonic: "INC", Group: GroupUnary, Operands: []Operand{OperandR0}, Flags: FlagCarry | FlagOverflow | FlagZero | FlagNegative},
+		0xDD: {Page: 0, Code: 0xDD, Mnemonic: "INC", Group: GroupUnary, Operands: []Operand{OperandR1}, Flags: FlagCarry | FlagOverflow | FlagZero | FlagNegative},
+		0xDE: {Page: 0, Code: 0xDE, Mnemonic: "NEG", Group: GroupUnary, Operands: []Operand{OperandR0}, Flags: FlagZero | FlagNegative},
+		0xDF: {Page: 0, Code: 0xDF, Mnemonic: "NEG", Group: GroupUnary, Operands: []Operand{OperandR1}, Flags: FlagZero | FlagNegative},
+		0xE0: {Page: 0, Code: 0xE0, Mnemonic: "NG1", Group: GroupUnary, Operands: []Operand{OperandR0}, Flags: FlagZero | FlagNegative},
+		0xE1: {Page: 0, Code: 0xE1, Mnemonic: "NG1", Group: GroupUnary, Operands: []Operand{OperandR1}, Flags: FlagZero | FlagNegative},
+		0xE2: {Page: 0, Code: 0xE2, Mnemonic: "NOT", Group: GroupUnary, Operands: []Operand{OperandR0}, Flags: FlagZero | FlagNegative},
+		0xE3: {Page: 0, Code: 0xE3, Mnemonic: "NOT", Group: GroupUnary, Operands: []Operand{OperandR1}, Flags: FlagZero | FlagNegative},
+		0xE4: {Page: 0, Code: 0xE4, Mnemonic: "NEXT", Group: GroupUnary, Operands: []Operand{OperandOFS0}, Flags: FlagCarry | FlagOverflow | FlagZero | FlagNegative},
+		0xE5: {Page: 0, Code: 0xE5, Mnemonic: "NEXT", Group: GroupUnary, Operands: []Operand{OperandOFS1}, Flags: FlagCarry | FlagOverflow | FlagZero | FlagNegative},
+		0xE6: {Page: 0, Code: 0xE6, Mnemonic: "NEXT", Group: GroupUnary, Operands: []Operand{OperandIX0}, Flags: FlagCarry | FlagOverflow | FlagZero | FlagNegative},
+		0xE7: {Page: 0, Code: 0xE7, Mnemonic: "NEXT", Group: GroupUnary, Operands: []Operand{OperandIX1}, Flags: FlagCarry | FlagOverflow | FlagZero | FlagNegative},
+		0xE8: {Page: 0, Code: 0xE8, Mnemonic: "ONE", Group: GroupUnary, Operands: []Operand{OperandR0}, Flags: FlagZero | FlagNegative},
+		0xE9: {Page: 0, Code: 0xE9, Mnemonic: "ONE", Group: GroupUnary, Operands: []Operand{OperandR1}, Flags: FlagZero | FlagNegative},
+		0xEA: {Page: 0, Code: 0xEA, Mnemonic: "ONE", Group: GroupUnary, Operands: []Operand{OperandOFS0}, Flags: FlagZero | FlagNegative},
+		0xEB: {Page: 0, Code: 0xEB, Mnemonic: "ONE", Group: GroupUnary, Operands: []Operand{OperandOFS1}, Flags: FlagZero | FlagNegative},
+		0xEC: {Page: 0, Code: 0xEC, Mnemonic: "ONE", Group: GroupUnary, Operands: []Operand{OperandIX0}, Flags: FlagZero | FlagNegative},
+		0xED: {Page: 0, Code: 0xED, Mnemonic: "ONE", Group: GroupUnary, Operands: []Operand{OperandIX1}, Flags: FlagZero | FlagNegative},
+		0xEE: {Page: 0, Code: 0xEE, Mnemonic: "PREV", Group: GroupUnary, Operands: []Operand{OperandOFS0}, Flags: FlagCarry | FlagOverflow | FlagZero | FlagNegative},
+		0xEF: {Page: 0, Code: 0xEF, Mnemonic: "PREV", Group: GroupUnary, Operands: []Operand{OperandOFS1}, Flags: FlagCarry | FlagOverflow | FlagZero | FlagNegative},
+		0xF0: {Page: 0, Code: 0xF0, Mnemonic: "PREV", Group: GroupUnary, Operands: []Operand{OperandIX0}, Flags: FlagCarry | FlagOverflow | FlagZero | FlagNegative},
+		0xF1: {Page: 0, Code: 0xF1, Mnemonic: "PREV", Group: GroupUnary, Operands: []Operand{OperandIX1}, Flags: FlagCarry | FlagOverflow | FlagZero | FlagNegative},
+		0xF2: {Page: 0, Code: 0xF2, Mnemonic: "SHA", Group: GroupUnary, Operands: []Operand{OperandR0}, Flags: FlagZero | FlagNegative},
+		0xF3: {Page: 0, Code: 0xF3, Mnemonic: "SHA", Group: GroupUnary, Operands: []Operand{OperandR1}, Flags: FlagZero | FlagNegative},
+		0xF4: {Page: 0, Code: 0xF4, Mnemonic: "SHL", Group: GroupUnary, Operands: []Operand{OperandR0}, Flags: FlagCarry | FlagZero | FlagNegative},
+		0xF5: {Page: 0, Code: 0xF5, Mnemonic: "SHL", Group: GroupUnary, Operands: []Operand{OperandR1}, Flags: FlagCarry | FlagZero | FlagNegative},
+		0xF6: {Page: 0, Code: 0xF6, Mnemonic: "SHR", Group: GroupUnary, Operands: []Operand{OperandR0}, Flags: FlagCarry | FlagZero | FlagNegative},
+		0xF7: {Page: 0, Code: 0xF7, Mnemonic: "SHR", Group: GroupUnary, Operands: []Operand{OperandR1}, Flags: FlagCarry | FlagZero | FlagNegative},
+		0xF8: {Page: 0, Code: 0xF8, Mnemonic: "ZRO", Group: GroupUnary, Operands: []Operand{OperandR0}, Flags: FlagZero | FlagNegative},
+		0xF9: {Page: 0, Code: 0xF9, Mnemonic: "ZRO", Group: GroupUnary, Operands: []Operand{OperandR1}, Flags: FlagZero | FlagNegative},
+		0xFA: {Page: 0, Code: 0xFA, Mnemonic: "ZRO", Group: GroupUnary, Operands: []Operand{OperandOFS0}, Flags: FlagZero | FlagNegative},
+		0xFB: {Page: 0, Code: 0xFB, Mnemonic: "ZRO", Group: GroupUnary, Operands: []Operand{OperandOFS1}, Flags: FlagZero | FlagNegative},
+		0xFC: {Page: 0, Code: 0xFC, Mnemonic: "ZRO", Group: GroupUnary, Operands: []Operand{OperandIX0}, Flags: FlagZero | FlagNegative},
+		0xFD: {Page: 0, Code: 0xFD, Mnemonic: "ZRO", Group: GroupUnary, Operands: []Operand{OperandIX1}, Flags: FlagZero | FlagNegative},
+		0xFE: {Page: 0, Code: 0xFE, Mnemonic: "NOP", Group: GroupOther},
+		0xFF: {Page: 0, Code: 0xFF, Mnemonic: "EXT", Group: GroupOther},
+	},
+	{
+		0x00: {Page: 1, Code: 0x00, Mnemonic: "ADD", Group: GroupBinary, Operands: []Operand{OperandR0, OperandO}, Flags: FlagCarry | FlagOverflow | FlagZero | FlagNegative},
+		0x01: {Page: 1, Code: 0x01, Mnemonic: "ADD", Group: GroupBinary, Operands: []Operand{OperandR0c, OperandO}, Flags: FlagCarry | FlagOverflow | FlagZero | FlagNegative},
+		0x02: {Page: 1, Code: 0x02, Mnemonic: "ADD", Group: GroupBinary, Operands: []Operand{OperandR1, OperandO}, Flags: FlagCarry | FlagOverflow | FlagZero | FlagNegative},
+		0x03: {Page: 1, Code: 0x03, Mnemonic: "ADD", Group: GroupBinary, Operands: []Operand{OperandR1c, OperandO}, Flags: FlagCarry | FlagOverflow | FlagZero | FlagNegative},
+		0x04: {Page: 1, Code: 0x04, Mnemonic: "ADD", Group: GroupBinary, Operands: []Operand{OperandIndirectPTR0, OperandO}, Flags: FlagCarry | FlagOverflow | FlagZero | FlagNegative},
+		0x05: {Page: 1, Code: 0x05, Mnemonic: "ADD", Group: GroupBinary, Operands: []Operand{OperandIndirectPTR1, OperandO}, Flags: FlagCarry | FlagOverflow | FlagZero | FlagNegative},
+		0x06: {Page: 1, Code: 0x06, Mnemonic: "ADD", Group: GroupBinary, Operands: []Operand{OperandR0, OperandIndirectPTR0}, Flags: FlagCarry | FlagOverflow | FlagZero | FlagNegative},
+		0x07: {Page: 1, Code: 0x07, Mnemonic: "ADD", Group: GroupBinary, Operands: []Operand{OperandR0c, OperandIndirectPTR0}, Flags: FlagCarry | FlagOverflow | FlagZero | FlagNegative},
+		0x08: {Page: 1, Code: 0x08, Mnemonic: "ADD", Group: GroupBinary, Operands: []Operand{OperandR1, OperandIndirectPTR1}, Flags: FlagCarry | FlagOverflow | FlagZero | FlagNegative},
+		0x09: {Page: 1, Code: 0x09, Mnemonic: "ADD", Group: GroupBinary, Operands: []Operand{OperandR1c, OperandIndirectPTR1}, Flags: FlagCarry | FlagOverflow | FlagZero | FlagNegative},
+		0x0A: {Page: 1, Code: 0x0A, Mnemonic: "ADD", Group: GroupBinary, Operands: []Operand{OperandIndirectPTR0, OperandR0}, Flags: FlagCarry | FlagOverflow | FlagZero | FlagNegative},
+		0x0B: {Page: 1, Code: 0x0B, Mnemonic: "ADD", Group: GroupBinary, Operands: []Operand{OperandIndirectPTR0, OperandR0c}, Flags: FlagCarry | FlagOverflow | FlagZero | FlagNegative},
+		0x0C: {Page: 1, Code: 0x0C, Mnemonic: "ADD", Group: GroupBinary, Operands: []Operand{OperandIndirectPTR1, OperandR1}, Flags: FlagCarry | FlagOverflow | FlagZero | FlagNegative},
+		0x0D: {Page: 1, Code: 0x0D, Mnemonic: "ADD", Group: GroupBinary, Operands: []Operand{OperandIndirectPTR1, OperandR1c}, Flags: FlagCarry | FlagOverflow | FlagZero | FlagNegative},
+		0x0E: {Page: 1, Code: 0x0E, Mnemonic: "CMP", Group: GroupBinary, Operands: []Operand{OperandR0, OperandO}, Flags: FlagCarry | FlagOverflow | FlagZero},
+		0x0F: {Page: 1, Code: 0x0F, Mnemonic: "CMP", Group: GroupBinary, Operands: []Operand{OperandR0c, OperandO}, Flags: FlagCarry | FlagOverflow | FlagZero},
+		0x10: {Page: 1, Code: 0x10, Mnemonic: "CMP", Group: GroupBinary, Operands: []Operand{OperandR1, OperandO}, Flags: FlagCarry | FlagOverflow | FlagZero},
+		0x11: {Page: 1, Code: 0x11, Mnemonic: "CMP", Group: GroupBinary, Operands: []Operand{OperandR1c, OperandO}, Flags: FlagCarry | FlagOverflow | FlagZero},
+		0x12: {Page: 1, Code: 0x12, Mnemonic: "CMP", Group: GroupBinary, Operands: []Operand{OperandIndirectPTR0, OperandO}, Flags: FlagCarry | FlagOverflow | FlagZero},
+		0x13: {Page: 1, Code: 0x13, Mnemonic: "CMP", Group: GroupBinary, Operands: []Operand{OperandIndirectPTR1, OperandO}, Flags: FlagCarry | FlagOverflow | FlagZero},
+		0x14: {Page: 1, Code: 0x14, Mnemonic: "SHA", Group: GroupBinary, Operands: []Operand{OperandR0, OperandU8}, Flags: FlagCarry | FlagZero | FlagNegative},
+		0x15: {Page: 1, Code: 0x15, Mnemonic: "SHA", Group: GroupBinary, Operands: []Operand{OperandR0c, OperandU8}, Flags: FlagCarry | FlagZero | FlagNegative},
+		0x16: {Page: 1, Code: 0x16, Mnemonic: "SHA", Group: GroupBinary, Operands: []Operand{OperandR1, OperandU8}, Flags: FlagCarry | FlagZero | FlagNegative},
+		0x17: {Page: 1, Code: 0x17, Mnemonic: "SHA", Group: GroupBinary, Operands: []Operand{OperandR1c, OperandU8}, Flags: FlagCarry | FlagZero | FlagNegative},
+		0x18: {Page: 1, Code: 0x18, Mnemonic: "SHL", Group: GroupBinary, Operands: []Operand{OperandR0, OperandU8}, Flags: FlagCarry | FlagZero | FlagNegative},
+		0x19: {Page: 1, Code: 0x19, Mnemonic: "SHL", Group: GroupBinary, Operands: []Operand{OperandR0c, OperandU8}, Flags: FlagCarry | FlagZero | FlagNegative},
+		0x1A: {Page: 1, Code: 0x1A, Mnemonic: "SHL", Group: GroupBinary, Operands: []Operand{OperandR1, OperandU8}, Flags: FlagCarry | FlagZero | FlagNegative},
+		0x1B: {Page: 1, Code: 0x1B, Mnemonic: "SHL", Group: GroupBinary, Operands: []Operand{OperandR1c, OperandU8}, Flags: FlagCarry | FlagZero | FlagNegative},
+		0x1C: {Page: 1, Code: 0x1C, Mnemonic: "SHR", Group: GroupBinary, Operands: []Operand{OperandR0, OperandU8}, Flags: FlagCarry | FlagZero | FlagNegative},
+		0x1D: {Page: 1, Code: 0x1D, Mnemonic: "SHR", Group: GroupBinary, Operands: []Operand{OperandR0c, OperandU8}, Flags: FlagCarry | FlagZero | FlagNegative},
+		0x1E: {Page: 1, Code: 0x1E, Mnemonic: "SHR", Group: GroupBinary, Operands: []Operand{OperandR1, OperandU8}, Flags: FlagCarry | FlagZero | FlagNegative},
+		0x1F: {Page: 1, Code: 0x1F, Mnemonic: "SHR", Group: GroupBinary, Operands: []Operand{OperandR1c, OperandU8}, Flags: FlagCarry | FlagZero | FlagNegative},
+		0x20: {Page: 1, Code: 0x20, Mnemonic: "SUB", Group: GroupBinary, Operands: []Operand{OperandR0, OperandO}, Flags: FlagCarry | FlagOverflow | FlagZero | FlagNegative},
+		0x21: {Page: 1, Code: 0x21, Mnemonic: "SUB", Group: GroupBinary, Operands: []Operand{OperandR0c, OperandO}, Flags: FlagCarry | FlagOverflow | FlagZero | FlagNegative},
+		0x22: {Page: 1, Code: 0x22, Mnemonic: "SUB", Group: GroupBinary, Operands: []Operand{OperandR1, OperandO}, Flags: FlagCarry | FlagOverflow | FlagZero | FlagNegative},
+		0x23: {Page: 1, Code: 0x23, Mnemonic: "SUB", Group: GroupBinary, Operands: []Operand{OperandR1c, OperandO}, Flags: FlagCarry | FlagOverflow | FlagZero | FlagNegative},
+		0x24: {Page: 1, Code: 0x24, Mnemonic: "SUB", Group: GroupBinary, Operands: []Operand{OperandIndirectPTR0, OperandO}, Flags: FlagCarry | FlagOverflow | FlagZero | FlagNegative},
+		0x25: {Page: 1, Code: 0x25, Mnemonic: "SUB", Group: GroupBinary, Operands: []Operand{OperandIndirectPTR1, OperandO}, Flags: FlagCarry | FlagOverflow | FlagZero | FlagNegative},
+		0x26: {Page: 1, Code: 0x26, Mnemonic: "SUB", Group: GroupBinary, Operands: []Operand{OperandR0, OperandIndirectPTR0}, Flags: FlagCarry | FlagOverflow | FlagZero | FlagNegative},
+		0x27: {Page: 1, Code: 0x27, Mnemonic: "SUB", Group: GroupBinary, Operands: []Operand{OperandR0c, OperandIndirectPTR0}, Flags: FlagCarry | FlagOverflow | FlagZero | FlagNegative},
+		0x28: {Page: 1, Code: 0x28, Mnemonic: "SUB", Group: GroupBinary, Operands: []Operand{OperandR1, OperandIndirectPTR1}, Flags: FlagCarry | FlagOverflow | FlagZero | FlagNegative},
+		0x29: {Page: 1, Code: 0x29, Mnemonic: "SUB", Group: GroupBinary, Operands: []Operand{OperandR1c, OperandIndirectPTR1}, Flags: FlagCarry | FlagOverflow | FlagZero | FlagNegative},
+		0x2A: {Page: 1, Code: 0x2A, Mnemonic: "SUB", Group: GroupBinary, Operands: []Operand{OperandIndirectPTR0, OperandR0}, Flags: FlagCarry | FlagOverflow | FlagZero | FlagNegative},
+		0x2B: {Page: 1, Code: 0x2B, Mnemonic: "SUB", Group: GroupBinary, Operands: []Operand{OperandIndirectPTR0, OperandR0c}, Flags: FlagCarry | FlagOverflow | FlagZero | FlagNegative},
+		0x2C: {Page: 1, Code: 0x2C, Mnemonic: "SUB", Group: GroupBinary, Operands: []Operand{OperandIndirectPTR1, OperandR1}, Flags: FlagCarry | FlagOverflow | FlagZero | FlagNegative},
+		0x2D: {Page: 1, Code: 0x2D, Mnemonic: "SUB", Group: GroupBinary, Operands: []Operand{OperandIndirectPTR1, OperandR1c}, Flags: FlagCarry | FlagOverflow | FlagZero | FlagNegative},
+		0x2E: {Page: 1, Code: 0x2E, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandCP, OperandU32}, Flags: FlagZero | FlagNegative},
+		0x2F: {Page: 1, Code: 0x2F, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandDP0, OperandU32}, Flags: FlagZero | FlagNegative},
+		0x30: {Page: 1, Code: 0x30, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandDP1, OperandU32}, Flags: FlagZero | FlagNegative},
+		0x31: {Page: 1, Code: 0x31, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandSB, OperandU32}, Flags: FlagZero | FlagNegative},
+		0x32: {Page: 1, Code: 0x32, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandSP, OperandU16}, Flags: FlagZero | FlagNegative},
+		0x33: {Page: 1, Code: 0x33, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandCP, OperandR0}, Flags: FlagZero | FlagNegative},
+		0x34: {Page: 1, Code: 0x34, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandST, OperandR0}, Flags: FlagZero | FlagNegative},
+		0x35: {Page: 1, Code: 0x35, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandDP0, OperandR0}, Flags: FlagZero | FlagNegative},
+		0x36: {Page: 1, Code: 0x36, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandPTR0, OperandR0}, Flags: FlagZero | FlagNegative},
+		0x37: {Page: 1, Code: 0x37, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandDP1, OperandR0}, Flags: FlagZero | FlagNegative},
+		0x38: {Page: 1, Code: 0x38, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandPTR1, OperandR0}, Flags: FlagZero | FlagNegative},
+		0x39: {Page: 1, Code: 0x39, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandSB, OperandR0}, Flags: FlagZero | FlagNegative},
+		0x3A: {Page: 1, Code: 0x3A, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandSP, OperandR0}, Flags: FlagZero | FlagNegative},
+		0x3B: {Page: 1, Code: 0x3B, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandR0, OperandCP}, Flags: FlagZero | FlagNegative},
+		0x3C: {Page: 1, Code: 0x3C, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandR0, OperandST}, Flags: FlagZero | FlagNegative},
+		0x3D: {Page: 1, Code: 0x3D, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandR0, OperandDP0}, Flags: FlagZero | FlagNegative},
+		0x3E: {Page: 1, Code: 0x3E, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandR0, OperandPTR0}, Flags: FlagZero | FlagNegative},
+		0x3F: {Page: 1, Code: 0x3F, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandR0, OperandDP1}, Flags: FlagZero | FlagNegative},
+		0x40: {Page: 1, Code: 0x40, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandR0, OperandPTR1}, Flags: FlagZero | FlagNegative},
+		0x41: {Page: 1, Code: 0x41, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandR0, OperandSB}, Flags: FlagZero | FlagNegative},
+		0x42: {Page: 1, Code: 0x42, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandR0, OperandSP}, Flags: FlagZero | FlagNegative},
+		0x43: {Page: 1, Code: 0x43, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandR0, OperandO}, Flags: FlagZero | FlagNegative},
+		0x44: {Page: 1, Code: 0x44, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandR0c, OperandO}, Flags: FlagZero | FlagNegative},
+		0x45: {Page: 1, Code: 0x45, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandR1, OperandO}, Flags: FlagZero | FlagNegative},
+		0x46: {Page: 1, Code: 0x46, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandR1c, OperandO}, Flags: FlagZero | FlagNegative},
+		0x47: {Page: 1, Code: 0x47, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandR0c, OperandIndirectPTR0}, Flags: FlagZero | FlagNegative},
+		0x48: {Page: 1, Code: 0x48, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandR1c, OperandIndirectPTR1}, Flags: FlagZero | FlagNegative},
+		0x49: {Page: 1, Code: 0x49, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandIndirectPTR0, OperandR0c}, Flags: FlagZero | FlagNegative},
+		0x4A: {Page: 1, Code: 0x4A, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandIndirectPTR1, OperandR1c}, Flags: FlagZero | FlagNegative},
+		0x4B: {Page: 1, Code: 0x4B, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandR0c, OperandMemory}, Flags: FlagZero | FlagNegative},
+		0x4C: {Page: 1, Code: 0x4C, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandR1, OperandMemory}, Flags: FlagZero | FlagNegative},
+		0x4D: {Page: 1, Code: 0x4D, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandR1c, OperandMemory}, Flags: FlagZero | FlagNegative},
+		0x4E: {Page: 1, Code: 0x4E, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandMemory, OperandR0c}, Flags: FlagZero | FlagNegative},
+		0x4F: {Page: 1, Code: 0x4F, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandMemory, OperandR1}, Flags: FlagZero | FlagNegative},
+		0x50: {Page: 1, Code: 0x50, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandMemory, OperandR1c}, Flags: FlagZero | FlagNegative},
+		0x51: {Page: 1, Code: 0x51, Mnemonic: "SWP", Group: GroupMove, Operands: []Operand{OperandR0, OperandMemory}, Flags: FlagZero | FlagNegative},
+		0x52: {Page: 1, Code: 0x52, Mnemonic: "SWP", Group: GroupMove, Operands: []Operand{OperandR0c, OperandMemory}, Flags: FlagZero | FlagNegative},
+		0x53: {Page: 1, Code: 0x53, Mnemonic: "SWP", Group: GroupMove, Operands: []Operand{OperandR1, OperandMemory}, Flags: FlagZero | FlagNegative},
+		0x54: {Page: 1, Code: 0x54, Mnemonic: "SWP", Group: GroupMove, Operands: []Operand{OperandR1c, OperandMemory}, Flags: FlagZero | FlagNegative},
+		0x55: {Page: 1, Code: 0x55, Mnemonic: "DEC", Group: GroupUnary, Operands: []Operand{OperandR0c}, Flags: FlagCarry | FlagOverflow | FlagZero | FlagNegative},
+		0x56: {Page: 1, Code: 0x56, Mnemonic: "DEC", Group: GroupUnary, Operands: []Operand{OperandR1c}, Flags: FlagCarry | FlagOverflow | FlagZero | FlagNegative},
+		0x57: {Page: 1, Code: 0x57, Mnemonic: "INC", Group: GroupUnary, Operands: []Operand{OperandR0c}, Flags: FlagCarry | FlagOverflow | FlagZero | FlagNegative},
+		0x58: {Page: 1, Code: 0x58, Mnemonic: "INC", Group: GroupUnary, Operands: []Operand{OperandR1c}, Flags: FlagCarry | FlagOverflow | FlagZero | FlagNegative},
+		0x59: {Page: 1, Code: 0x59, Mnemonic: "NEG", Group: GroupUnary, Operands: []Operand{OperandR0c}, Flags: FlagZero | FlagNegative},
+		0x5A: {Page: 1, Code: 0x5A, Mnemonic: "NEG", Group: GroupUnary, Operands: []Operand{OperandR1c}, Flags: FlagZero | FlagNegative},
+		0x5B: {Page: 1, Code: 0x5B, Mnemonic: "NG1", Group: GroupUnary, Operands: []Operand{OperandR0c}, Flags: FlagZero | FlagNegative},
+		0x5C: {Page: 1, Code: 0x5C, Mnemonic: "NG1", Group: GroupUnary, Operands: []Operand{OperandR1c}, Flags: FlagZero | FlagNegative},
+		0x5D: {Page: 1, Code: 0x5D, Mnemonic: "NOT", Group: GroupUnary, Operands: []Operand{OperandR0c}, Flags: FlagZero | FlagNegative},
+		0x5E: {Page: 1, Code: 0x5E, Mnemonic: "NOT", Group: GroupUnary, Operands: []Operand{OperandR1c}, Flags: FlagZero | FlagNegative},
+		0x5F: {Page: 1, Code: 0x5F, Mnemonic: "ONE", Group: GroupUnary, Operands: []Operand{OperandR0c}, Flags: FlagZero | FlagNegative},
+		0x60: {Page: 1, Code: 0x60, Mnemonic: "ONE", Group: GroupUnary, Operands: []Operand{OperandR1c}, Flags: FlagZero | FlagNegative},
+		0x61: {Page: 1, Code: 0x61, Mnemonic: "SHA", Group: GroupUnary, Operands: []Operand{OperandR0c}, Flags: FlagZero | FlagNegative},
+		0x62: {Page: 1, Code: 0x62, Mnemonic: "SHA", Group: GroupUnary, Operands: []Operand{OperandR1c}, Flags: FlagZero | FlagNegative},
+		0x63: {Page: 1, Code: 0x63, Mnemonic: "SHL", Group: GroupUnary, Operands: []Operand{OperandR0c}, Flags: FlagCarry | FlagZero | FlagNegative},
+		0x64: {Page: 1, Code: 0x64, Mnemonic: "SHL", Group: GroupUnary, Operands: []Operand{OperandR1c}, Flags: FlagCarry | FlagZero | FlagNegative},
+		0x65: {Page: 1, Code: 0x65, Mnemonic: "SHR", Group: GroupUnary, Operands: []Operand{OperandR0c}, Flags: FlagCarry | FlagZero | FlagNegative},
+		0x66: {Page: 1, Code: 0x66, Mnemonic: "SHR", Group: GroupUnary, Operands: []Operand{OperandR1c}, Flags: FlagCarry | FlagZero | FlagNegative},
+		0x67: {Page: 1, Code: 0x67, Mnemonic: "ZRO", Group: GroupUnary, Operands: []Operand{OperandR0c}, Flags: FlagZero | FlagNegative},
+		0x68: {Page: 1, Code: 0x68, Mnemonic: "ZRO", Group: GroupUnary, Operands: []Operand{OperandR1c}, Flags: FlagZero | FlagNegative},
+		0x69: {Page: 1, Code: 0x69, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandCTR0, OperandU32}, Flags: FlagZero | FlagNegative},
+		0x6A: {Page: 1, Code: 0x6A, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandCTR1, OperandU32}, Flags: FlagZero | FlagNegative},
+		0x6B: {Page: 1, Code: 0x6B, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandCS0, OperandS16}, Flags: FlagZero | FlagNegative},
+		0x6C: {Page: 1, Code: 0x6C, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandCS1, OperandS16}, Flags: FlagZero | FlagNegative},
+		0x6D: {Page: 1, Code: 0x6D, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandIS0, OperandS16}, Flags: FlagZero | FlagNegative},
+		0x6E: {Page: 1, Code: 0x6E, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandIS1, OperandS16}, Flags: FlagZero | FlagNegative},
+		0x6F: {Page: 1, Code: 0x6F, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandR0, OperandCTR0}, Flags: FlagZero | FlagNegative},
+		0x70: {Page: 1, Code: 0x70, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandR0, OperandCTR1}, Flags: FlagZero | FlagNegative},
+		0x71: {Page: 1, Code: 0x71, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandCTR0, OperandR0}, Flags: FlagZero | FlagNegative},
+		0x72: {Page: 1, Code: 0x72, Mnemonic: "MOV", Group: GroupMove, Operands: []Operand{OperandCTR1, OperandR0}, Flags: FlagZero | FlagNegative},
+		0x73: {Page: 1, Code: 0x73, Mnemonic: "STEP", Group: GroupUnary, Operands: []Operand{OperandCTR0}, Flags: FlagZero | FlagNegative},
+		0x74: {Page: 1, Code: 0x74, Mnemonic: "STEP", Group: GroupUnary, Operands: []Operand{OperandCTR1}, Flags: FlagZero | FlagNegative},
+		0x75: {Page: 1, Code: 0x75, Mnemonic: "STEP", Group: GroupUnary, Operands: []Operand{OperandIX0}, Flags: FlagZero | FlagNegative},
+		0x76: {Page: 1, Code: 0x76, Mnemonic: "STEP", Group: GroupUnary, Operands: []Operand{OperandIX1}, Flags: FlagZero | FlagNegative},
+		0x77: {Page: 1, Code: 0x77, Mnemonic: "LOOP", Group: GroupBranch, Operands: []Operand{OperandCTR0, OperandS8}, Flags: FlagZero | FlagNegative},
+		0x78: {Page: 1, Code: 0x78, Mnemonic: "LOOP", Group: GroupBranch, Operands: []Operand{OperandCTR1, OperandS8}, Flags: FlagZero | FlagNegative},
+	},
+}
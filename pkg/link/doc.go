@@ -0,0 +1,14 @@
+// Package link combines object files of package obj into an image that can be loaded into memory.
+//
+// The objects of a program are laid out one after another: the code of every object at the code address of the
+// Script, which is the CP of the program, then the data of every object at the data address, which is the DP of the
+// program, followed by the bss of every object. The objects of OS routines are laid out the same way at OSBase,
+// and must end at or below the IRQ vectors at IRQVectors. The reset vector at FFFFFFFC is set to the address of a
+// global symbol.
+//
+// Global symbols of all objects are visible to every object. Absolute relocations are filled in with the address of
+// the symbol, and relative relocations with the address relative to the code or data address of the program or OS
+// routines that contain the relocation. A relative relocation of the program may not refer to a label of the OS
+// routines, or vice versa, since they have different code and data addresses.
+// SPDX-License-Identifier: Apache-2.0
+package link
@@ -0,0 +1,451 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package link
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/bantling/goprocessor/pkg/memory"
+	"github.com/bantling/goprocessor/pkg/obj"
+)
+
+// LinkError represents an error linking objects
+type LinkError string
+
+func (e LinkError) Error() string {
+	return string(e)
+}
+
+const (
+	// ErrUndefined is returned when a symbol is not defined by any object
+	ErrUndefined = LinkError("Undefined Symbol")
+
+	// ErrDuplicate is returned when a global symbol is defined by more than one object
+	ErrDuplicate = LinkError("Duplicate Symbol")
+
+	// ErrOverflow is returned when a relocated value does not fit in its field
+	ErrOverflow = LinkError("Relocation Overflow")
+
+	// ErrOverlap is returned when sections overlap each other, the OS routines, or the IRQ vectors
+	ErrOverlap = LinkError("Overlap")
+
+	// ErrRegion is returned when a relative relocation of the program refers to the OS routines, or vice versa
+	ErrRegion = LinkError("Relative Reference To Another Region")
+)
+
+// Error describes an error linking an object
+type Error struct {
+	Object string
+	Symbol string
+	Err    error
+}
+
+func (e *Error) Error() string {
+	if e.Symbol == "" {
+		return fmt.Sprintf("%s: %s", e.Object, e.Err)
+	}
+
+	return fmt.Sprintf("%s: %s: %s", e.Object, e.Err, e.Symbol)
+}
+
+// Unwrap returns the underlying error
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Object is an object file and the name it is reported by
+type Object struct {
+	Name string
+	File *obj.File
+}
+
+// Layout is the address and size of a section of an object
+type Layout struct {
+	Object  string
+	Section obj.Section
+	Addr    uint32
+	Size    uint32
+}
+
+// Symbol is a label of an object and its address
+type Symbol struct {
+	Object string
+	Name   string
+	Addr   uint32
+	Global bool
+}
+
+// Segment is bytes located at an address
+type Segment struct {
+	Addr  uint32
+	Bytes []byte
+}
+
+// Image is the result of linking.
+// Program contains the code and data of the program, and OS contains the OS routines and the reset vector.
+// The bss is not part of either, and must be zero when the image is loaded.
+type Image struct {
+	Code    uint32 // the CP of the program
+	Data    uint32 // the DP of the program
+	Program Segment
+	OS      Segment
+	Layout  []Layout // ordered by address
+	Symbols []Symbol // ordered by address and name
+}
+
+// Load writes the program and OS segments of an image into a bus
+func (img *Image) Load(bus memory.Bus) error {
+	if err := memory.WriteBytes(bus, img.Program.Addr, img.Program.Bytes); err != nil {
+		return err
+	}
+
+	return memory.WriteBytes(bus, img.OS.Addr, img.OS.Bytes)
+}
+
+// placed is an object and the address of each of its sections
+type placed struct {
+	Object
+	addrs [obj.SectionBSS + 1]uint32
+}
+
+// region is the objects of the program or the OS routines, and the code and data addresses they are relative to
+type region struct {
+	code    uint32
+	data    uint32
+	end     uint64 // the end of the bss
+	objects []*placed
+}
+
+// place lays out objects at a code and data address, where data = 0 means the data follows the code
+func place(objects []Object, code, data uint32) *region {
+	r := &region{code: code, data: data}
+
+	addr := uint64(code)
+	for _, o := range objects {
+		p := &placed{Object: o}
+		p.addrs[obj.SectionCode], addr = uint32(addr), addr+uint64(o.File.Size(obj.SectionCode))
+		r.objects = append(r.objects, p)
+	}
+
+	if data != 0 {
+		addr = uint64(data)
+	}
+
+	r.data = uint32(addr)
+	for _, section := range []obj.Section{obj.SectionData, obj.SectionBSS} {
+		for _, p := range r.objects {
+			p.addrs[section], addr = uint32(addr), addr+uint64(p.File.Size(section))
+		}
+	}
+
+	r.end = addr
+	return r
+}
+
+// codeEnd returns the end of the code of a region
+func (r *region) codeEnd() uint64 {
+	end := uint64(r.code)
+	for _, p := range r.objects {
+		end += uint64(p.File.Size(obj.SectionCode))
+	}
+
+	return end
+}
+
+// global is the address of a global symbol, the section it is in, and the region of the object that defines it
+type global struct {
+	addr    uint32
+	section obj.Section
+	region  *region
+}
+
+// linker holds the state of linking
+type linker struct {
+	regions []*region
+	globals map[string]global
+	img     *Image
+}
+
+// Link lays out the program objects and the OS objects according to a script, resolves symbols, and applies
+// relocations. The names of the OS objects are normally the OS files of the script.
+func Link(script Script, program, os []Object) (*Image, error) {
+	var (
+		prog = place(program, script.Code, script.Data)
+		osr  = place(os, memory.OSBase, 0)
+		l    = &linker{
+			regions: []*region{prog, osr},
+			globals: map[string]global{},
+			img:     &Image{Code: prog.code, Data: prog.data},
+		}
+	)
+
+	if err := l.check(prog, osr); err != nil {
+		return nil, err
+	}
+
+	if err := l.define(); err != nil {
+		return nil, err
+	}
+
+	var osChunks []Segment
+	for _, r := range l.regions {
+		chunks, err := l.relocate(r)
+		if err != nil {
+			return nil, err
+		}
+
+		if r == prog {
+			l.img.Program = merge(chunks)
+		} else {
+			osChunks = chunks
+		}
+	}
+
+	if script.Reset != "" {
+		g, ok := l.globals[script.Reset]
+		if !ok {
+			return nil, &Error{Object: "script", Symbol: script.Reset, Err: ErrUndefined}
+		}
+
+		vector := Segment{Addr: memory.ResetVector, Bytes: make([]byte, 4)}
+		put(vector.Bytes, int64(g.addr))
+		osChunks = append(osChunks, vector)
+	}
+
+	l.img.OS = merge(osChunks)
+
+	sort.SliceStable(l.img.Layout, func(i, j int) bool {
+		return l.img.Layout[i].Addr < l.img.Layout[j].Addr
+	})
+
+	sort.Slice(l.img.Symbols, func(i, j int) bool {
+		si, sj := l.img.Symbols[i], l.img.Symbols[j]
+		return (si.Addr < sj.Addr) || ((si.Addr == sj.Addr) && (si.Name < sj.Name))
+	})
+
+	return l.img, nil
+}
+
+// check returns an error if the code and data of the program overlap each other or the OS routines, or the OS
+// routines overlap the IRQ vectors
+func (l *linker) check(prog, osr *region) error {
+	var (
+		codeEnd  = prog.codeEnd()
+		dataEnd  = prog.end
+		dataAddr = uint64(prog.data)
+	)
+
+	if (dataAddr < codeEnd) && (uint64(prog.code) < dataEnd) {
+		return &Error{Object: "script", Err: ErrOverlap}
+	}
+
+	if (codeEnd > uint64(memory.OSBase)) || (dataEnd > uint64(memory.OSBase)) {
+		return &Error{Object: "script", Err: ErrOverlap}
+	}
+
+	if osr.end > uint64(memory.IRQVectors) {
+		return &Error{Object: "script", Err: ErrOverlap}
+	}
+
+	return nil
+}
+
+// define records the layout and labels of every object, and the addresses of global symbols
+func (l *linker) define() error {
+	for _, r := range l.regions {
+		for _, p := range r.objects {
+			for section := obj.SectionCode; section <= obj.SectionBSS; section++ {
+				if size := p.File.Size(section); size > 0 {
+					layout := Layout{Object: p.Name, Section: section, Addr: p.addrs[section], Size: size}
+					l.img.Layout = append(l.img.Layout, layout)
+				}
+			}
+
+			for _, sym := range p.File.Symbols {
+				if sym.Section == obj.SectionUndefined {
+					continue
+				}
+
+				addr := uint32(sym.Value)
+				if sym.Section.IsLocated() {
+					addr += p.addrs[sym.Section]
+					symbol := Symbol{Object: p.Name, Name: sym.Name, Addr: addr, Global: sym.Global}
+					l.img.Symbols = append(l.img.Symbols, symbol)
+				}
+
+				if sym.Global {
+					if _, ok := l.globals[sym.Name]; ok {
+						return &Error{Object: p.Name, Symbol: sym.Name, Err: ErrDuplicate}
+					}
+
+					l.globals[sym.Name] = global{addr: addr, section: sym.Section, region: r}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// sectionNames maps the names relocations use for sections to the sections
+var sectionNames = map[string]obj.Section{
+	obj.SectionCode.String(): obj.SectionCode,
+	obj.SectionData.String(): obj.SectionData,
+	obj.SectionBSS.String():  obj.SectionBSS,
+}
+
+// resolve returns the address of a symbol referred to by an object of a region, the section it is in, and the region
+// that defines it
+func (l *linker) resolve(r *region, p *placed, name string) (global, error) {
+	if section, ok := sectionNames[name]; ok {
+		return global{addr: p.addrs[section], section: section, region: r}, nil
+	}
+
+	if sym, ok := p.File.Lookup(name); ok && (sym.Section != obj.SectionUndefined) {
+		addr := uint32(sym.Value)
+		if sym.Section.IsLocated() {
+			addr += p.addrs[sym.Section]
+		}
+
+		return global{addr: addr, section: sym.Section, region: r}, nil
+	}
+
+	// An external symbol is a label or constant of another object
+	if g, ok := l.globals[name]; ok {
+		return g, nil
+	}
+
+	return global{}, &Error{Object: p.Name, Symbol: name, Err: ErrUndefined}
+}
+
+// relocate applies the relocations of every object of a region, and returns the code and data of each object
+func (l *linker) relocate(r *region) ([]Segment, error) {
+	var chunks []Segment
+	for _, p := range r.objects {
+		code := append([]byte(nil), p.File.Code...)
+		data := append([]byte(nil), p.File.Data...)
+
+		for _, rel := range p.File.Relocs {
+			g, err := l.resolve(r, p, rel.Symbol)
+			if err != nil {
+				return nil, err
+			}
+
+			// A relative address is relative to the CP or DP of the region, so it cannot refer to another region
+			val := int64(g.addr) + rel.Addend
+			if (rel.Kind == obj.RelocRelative) && g.section.IsLocated() {
+				if g.region != r {
+					return nil, &Error{Object: p.Name, Symbol: rel.Symbol, Err: ErrRegion}
+				}
+
+				if g.section == obj.SectionCode {
+					val -= int64(r.code)
+				} else {
+					val -= int64(r.data)
+				}
+			}
+
+			if !fits(val, rel.Size) {
+				return nil, &Error{Object: p.Name, Symbol: rel.Symbol, Err: ErrOverflow}
+			}
+
+			field := code
+			if rel.Section == obj.SectionData {
+				field = data
+			}
+
+			put(field[rel.Offset:rel.Offset+uint32(rel.Size)], val)
+		}
+
+		chunks = append(
+			chunks,
+			Segment{Addr: p.addrs[obj.SectionCode], Bytes: code},
+			Segment{Addr: p.addrs[obj.SectionData], Bytes: data},
+		)
+	}
+
+	return chunks, nil
+}
+
+// fits is true if a value can be stored in a field of size bytes, as a signed or unsigned value
+func fits(val int64, size uint8) bool {
+	bits := uint(size) * 8
+	if bits == 64 {
+		return true
+	}
+
+	return (val >= -(1 << (bits - 1))) && (val < (1 << bits))
+}
+
+// put stores the lowest len(field) bytes of a value in field, highest byte first
+func put(field []byte, val int64) {
+	for i := range field {
+		field[i] = byte(val >> (uint(len(field)-1-i) * 8))
+	}
+}
+
+// merge returns a segment that contains all chunks, with any gaps between them zero filled
+func merge(chunks []Segment) Segment {
+	var (
+		start = uint64(1) << 32
+		end   uint64
+	)
+
+	for _, c := range chunks {
+		if len(c.Bytes) == 0 {
+			continue
+		}
+
+		if uint64(c.Addr) < start {
+			start = uint64(c.Addr)
+		}
+
+		if e := uint64(c.Addr) + uint64(len(c.Bytes)); e > end {
+			end = e
+		}
+	}
+
+	if end == 0 {
+		return Segment{}
+	}
+
+	seg := Segment{Addr: uint32(start), Bytes: make([]byte, end-start)}
+	for _, c := range chunks {
+		copy(seg.Bytes[uint64(c.Addr)-start:], c.Bytes)
+	}
+
+	return seg
+}
+
+// WriteMap writes a map of an image: the code and data addresses, the layout of each section, and the address of
+// each label, marked G for global or L for local
+func WriteMap(w io.Writer, img *Image) error {
+	if _, err := fmt.Fprintf(w, "CODE %08X\nDATA %08X\n\n", img.Code, img.Data); err != nil {
+		return err
+	}
+
+	for _, l := range img.Layout {
+		if _, err := fmt.Fprintf(w, "%08X %08X %-5s %s\n", l.Addr, l.Size, l.Section, l.Object); err != nil {
+			return err
+		}
+	}
+
+	if _, err := fmt.Fprintln(w); err != nil {
+		return err
+	}
+
+	for _, sym := range img.Symbols {
+		kind := "L"
+		if sym.Global {
+			kind = "G"
+		}
+
+		if _, err := fmt.Fprintf(w, "%08X %s %s %s\n", sym.Addr, kind, sym.Name, sym.Object); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
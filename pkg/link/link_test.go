@@ -0,0 +1,226 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package link
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bantling/goprocessor/pkg/asm"
+	"github.com/bantling/goprocessor/pkg/cpu"
+	"github.com/bantling/goprocessor/pkg/memory"
+	"github.com/bantling/goprocessor/pkg/obj"
+	"github.com/stretchr/testify/assert"
+)
+
+// object assembles src into an Object, failing if there are any errors
+func object(t *testing.T, name, src string) Object {
+	f, err := asm.AssembleObject(name, strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return Object{Name: name, File: f}
+}
+
+// mainSrc adds the data of lib to its own data with a routine of lib, and leaves the sum in R0
+const mainSrc = `
+        .extern add, value
+        .global main
+main:   LD      DP0, count
+        LD      R0, M[count]
+        LD      R0c, M[value]
+        JSR     add
+        BEQ     main
+        NOP
+        .data
+count:  .u8     3
+`
+
+// libSrc is a routine and data used by main
+const libSrc = `
+        .global add, value
+add:    ADD     R0, R0c
+        RTS
+        .data
+value:  .u8     4
+        .bss
+buf:    .space  8
+`
+
+// osSrc is an OS routine for the reset vector
+const osSrc = `
+        .global reset
+reset:  NOP
+`
+
+func TestReadScript(t *testing.T) {
+	script, err := ReadScript(strings.NewReader(`
+; program
+code  0x20000
+data  0x30000   ; DP
+os    kernel.o  io.o
+reset reset
+`))
+	assert.Nil(t, err)
+	assert.Equal(t, Script{Code: 0x20000, Data: 0x30000, OS: []string{"kernel.o", "io.o"}, Reset: "reset"}, script)
+
+	for src, msg := range map[string]string{
+		"data 0":           "script requires a code address",
+		"code":             "script line 1: code requires an address",
+		"code 0x1FFFFFFFF": "script line 1: invalid address 0x1FFFFFFFF",
+		"os":               "script line 1: os requires object files",
+		"reset":            "script line 1: reset requires a symbol",
+		"entry main":       "script line 1: unknown directive entry",
+	} {
+		_, err := ReadScript(strings.NewReader(src))
+		assert.Equal(t, msg, err.Error(), src)
+	}
+}
+
+func TestLink(t *testing.T) {
+	var (
+		program = []Object{object(t, "main.o", mainSrc), object(t, "lib.o", libSrc)}
+		os      = []Object{object(t, "os.o", osSrc)}
+	)
+
+	img, err := Link(Script{Code: 0x20000, Reset: "reset"}, program, os)
+	assert.Nil(t, err)
+
+	// main.o code is 25 bytes and lib.o code is 2 bytes, so the data begins at 0x2001B
+	assert.Equal(t, uint32(0x20000), img.Code)
+	assert.Equal(t, uint32(0x2001B), img.Data)
+	assert.Equal(
+		t,
+		[]Layout{
+			{Object: "main.o", Section: obj.SectionCode, Addr: 0x20000, Size: 25},
+			{Object: "lib.o", Section: obj.SectionCode, Addr: 0x20019, Size: 2},
+			{Object: "main.o", Section: obj.SectionData, Addr: 0x2001B, Size: 1},
+			{Object: "lib.o", Section: obj.SectionData, Addr: 0x2001C, Size: 1},
+			{Object: "lib.o", Section: obj.SectionBSS, Addr: 0x2001D, Size: 8},
+			{Object: "os.o", Section: obj.SectionCode, Addr: 0xFFFF0000, Size: 1},
+		},
+		img.Layout,
+	)
+	assert.Equal(
+		t,
+		[]Symbol{
+			{Object: "main.o", Name: "main", Addr: 0x20000, Global: true},
+			{Object: "lib.o", Name: "add", Addr: 0x20019, Global: true},
+			{Object: "main.o", Name: "count", Addr: 0x2001B},
+			{Object: "lib.o", Name: "value", Addr: 0x2001C, Global: true},
+			{Object: "lib.o", Name: "buf", Addr: 0x2001D},
+			{Object: "os.o", Name: "reset", Addr: 0xFFFF0000, Global: true},
+		},
+		img.Symbols,
+	)
+
+	assert.Equal(t, uint32(0x20000), img.Program.Addr)
+	assert.Equal(t, 0x1D, len(img.Program.Bytes))
+	assert.Equal(t, []byte{0xFF, 0x2F, 0x00, 0x02, 0x00, 0x1B}, img.Program.Bytes[:6]) // MOV DP0, count
+	assert.Equal(t, []byte{0x37, 0x00, 0x02, 0x00, 0x19}, img.Program.Bytes[17:22])    // JSA add
+
+	assert.Equal(t, uint32(0xFFFF0000), img.OS.Addr)
+	assert.Equal(t, 0x10000, len(img.OS.Bytes))
+	assert.Equal(t, []byte{0xFF, 0xFF, 0x00, 0x00}, img.OS.Bytes[0xFFFC:])
+
+	// Run main up to the NOP
+	ram := memory.NewRAM()
+	assert.Nil(t, img.Load(ram))
+
+	p := cpu.New(ram)
+	p.Registers().CP = img.Code
+	for p.Registers().PC != 24 {
+		assert.Nil(t, p.Step())
+	}
+
+	assert.Equal(t, uint64(7), p.Registers().R0)
+
+	var buf bytes.Buffer
+	assert.Nil(t, WriteMap(&buf, img))
+	assert.Equal(
+		t,
+		`CODE 00020000
+DATA 0002001B
+
+00020000 00000019 .code main.o
+00020019 00000002 .code lib.o
+0002001B 00000001 .data main.o
+0002001C 00000001 .data lib.o
+0002001D 00000008 .bss  lib.o
+FFFF0000 00000001 .code os.o
+
+00020000 G main main.o
+00020019 G add lib.o
+0002001B L count main.o
+0002001C G value lib.o
+0002001D L buf lib.o
+FFFF0000 G reset os.o
+`,
+		buf.String(),
+	)
+}
+
+func TestLinkLongJump(t *testing.T) {
+	// A JMP out of S16 range is assembled as a JMA, whose relocation resolves to CP + FAR
+	far := object(t, "far.o", `
+        JMP   FAR
+        .space 0x8000
+FAR:    NOP
+`)
+
+	img, err := Link(Script{Code: 0x20000}, []Object{far}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{0x33, 0x00, 0x02, 0x80, 0x05}, img.Program.Bytes[:5])
+
+	ram := memory.NewRAM()
+	assert.Nil(t, img.Load(ram))
+
+	p := cpu.New(ram)
+	p.Registers().CP = img.Code
+	assert.Nil(t, p.Step())
+	assert.Equal(t, uint32(0x8005), p.Registers().PC)
+}
+
+func TestLinkErrors(t *testing.T) {
+	var (
+		main = object(t, "main.o", mainSrc)
+		lib  = object(t, "lib.o", libSrc)
+		irq  = object(t, "irq.o", "IRQ: .space 0xFFDD")
+		ptr  = object(t, "ptr.o", ".data\nP: .u8 P")
+	)
+
+	for _, test := range []struct {
+		name    string
+		script  Script
+		program []Object
+		os      []Object
+		object  string
+		symbol  string
+		err     error
+	}{
+		{"undefined", Script{Code: 0x20000}, []Object{main}, nil, "main.o", "value", ErrUndefined},
+		{"duplicate", Script{Code: 0x20000}, []Object{main, lib, lib}, nil, "lib.o", "add", ErrDuplicate},
+		{"reset", Script{Code: 0x20000, Reset: "boot"}, []Object{main, lib}, nil, "script", "boot", ErrUndefined},
+		{"overflow", Script{Code: 0x20000}, []Object{ptr}, nil, "ptr.o", ".data", ErrOverflow},
+		{"data overlap", Script{Code: 0x20000, Data: 0x20010}, []Object{main, lib}, nil, "script", "", ErrOverlap},
+		{"OS overlap", Script{Code: 0xFFFEFFF0}, []Object{main, lib}, nil, "script", "", ErrOverlap},
+		{"region", Script{Code: 0x20000}, []Object{main}, []Object{lib}, "main.o", "value", ErrRegion},
+		{"IRQ overlap", Script{Code: 0x20000}, nil, []Object{irq}, "script", "", ErrOverlap},
+	} {
+		_, err := Link(test.script, test.program, test.os)
+
+		var linkErr *Error
+		if assert.True(t, errors.As(err, &linkErr), test.name) {
+			assert.Equal(t, test.object, linkErr.Object, test.name)
+			assert.Equal(t, test.symbol, linkErr.Symbol, test.name)
+			assert.Equal(t, test.err, linkErr.Err, test.name)
+		}
+	}
+
+	// OS routines may end right below the IRQ vectors
+	_, err := Link(Script{Code: 0x20000}, nil, []Object{object(t, "os.o", "OS: .space 0xFFDC")})
+	assert.Nil(t, err)
+}
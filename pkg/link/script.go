@@ -0,0 +1,92 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package link
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Script describes where to lay out the sections of a program.
+//
+// It is read from lines of a directive and arguments, where a semicolon begins a comment:
+//
+//	code address        the address of the code, which is required
+//	data address        the address of the data, which follows the code by default
+//	os file.o ...       object files of OS routines
+//	reset name          the global symbol the reset vector points to
+type Script struct {
+	Code  uint32
+	Data  uint32 // 0 if the data follows the code
+	OS    []string
+	Reset string
+}
+
+// ReadScript reads a Script
+func ReadScript(r io.Reader) (Script, error) {
+	var (
+		script  Script
+		hasCode bool
+		scanner = bufio.NewScanner(r)
+	)
+
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Text()
+		if i := strings.IndexByte(line, ';'); i >= 0 {
+			line = line[:i]
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch directive, args := strings.ToLower(fields[0]), fields[1:]; {
+		case (directive == "code") || (directive == "data"):
+			if len(args) != 1 {
+				return Script{}, fmt.Errorf("script line %d: %s requires an address", n, directive)
+			}
+
+			addr, err := strconv.ParseUint(args[0], 0, 32)
+			if err != nil {
+				return Script{}, fmt.Errorf("script line %d: invalid address %s", n, args[0])
+			}
+
+			if directive == "code" {
+				script.Code, hasCode = uint32(addr), true
+			} else {
+				script.Data = uint32(addr)
+			}
+
+		case directive == "os":
+			if len(args) == 0 {
+				return Script{}, fmt.Errorf("script line %d: os requires object files", n)
+			}
+
+			script.OS = append(script.OS, args...)
+
+		case directive == "reset":
+			if len(args) != 1 {
+				return Script{}, fmt.Errorf("script line %d: reset requires a symbol", n)
+			}
+
+			script.Reset = args[0]
+
+		default:
+			return Script{}, fmt.Errorf("script line %d: unknown directive %s", n, fields[0])
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return Script{}, err
+	}
+
+	if !hasCode {
+		return Script{}, fmt.Errorf("script requires a code address")
+	}
+
+	return script, nil
+}
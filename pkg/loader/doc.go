@@ -0,0 +1,10 @@
+// Package loader places relocatable program images in a shared address space.
+//
+// Code addresses are relative to CP, so an image runs at any CP as long as it makes no absolute references.
+// The only absolute references an image may make are JMA and JSA with an immediate address that is an entry of the
+// kernel jump table declared to the Loader. Instructions that jump to an address in a register, or load CP, DP, or SB
+// with MOV or PUL, are rejected, since the loader cannot know where they go. Instead, the loader sets DP to CP.
+//
+// Each Load reserves the code and stack of the image, so several programs can be loaded without overlapping.
+// SPDX-License-Identifier: Apache-2.0
+package loader
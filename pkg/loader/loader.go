@@ -0,0 +1,248 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"fmt"
+
+	"github.com/bantling/goprocessor/pkg/disasm"
+	"github.com/bantling/goprocessor/pkg/isa"
+	"github.com/bantling/goprocessor/pkg/memory"
+	"github.com/bantling/goprocessor/pkg/register"
+)
+
+// EntrySize is the number of bytes of each jump table entry, which is a JMA U32 instruction
+const EntrySize = 5
+
+// LoaderError represents an error loading an image
+type LoaderError string
+
+func (e LoaderError) Error() string {
+	return string(e)
+}
+
+const (
+	// ErrAbsoluteReference is returned when an image refers to an absolute address that is not a jump table entry
+	ErrAbsoluteReference = LoaderError("Absolute Reference")
+
+	// ErrInvalidInstruction is returned when the instructions of an image contain bytes that are not an instruction
+	ErrInvalidInstruction = LoaderError("Invalid Instruction")
+
+	// ErrOverlap is returned when an image or stack overlaps memory that is already in use
+	ErrOverlap = LoaderError("Overlap")
+
+	// ErrEntry is returned when the entry point of an image is not within its instructions
+	ErrEntry = LoaderError("Entry Not In Instructions")
+)
+
+// ImageError describes an error in the instruction at an offset of an image
+type ImageError struct {
+	Offset uint32
+	Text   string
+	Err    error
+}
+
+func (e *ImageError) Error() string {
+	return fmt.Sprintf("%08X: %s: %s", e.Offset, e.Text, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *ImageError) Unwrap() error {
+	return e.Err
+}
+
+// Image is a relocatable program.
+// Origin is the offset from CP of the first byte of Code, which is the same as the .org of the assembled source.
+// The first Text bytes of Code are instructions, and the rest is data; if Text is 0, all of Code is instructions.
+// Entry is the offset from CP of the first instruction to execute.
+// Size is the operand size selected when the image begins, such as register.Operand8.
+type Image struct {
+	Origin uint32
+	Code   []byte
+	Text   int
+	Entry  uint32
+	Size   uint8
+}
+
+// instructions returns the bytes of Code that are instructions
+func (img Image) instructions() []byte {
+	if (img.Text == 0) || (img.Text > len(img.Code)) {
+		return img.Code
+	}
+
+	return img.Code[:img.Text]
+}
+
+// JumpTable is a kernel jump table of Entries consecutive JMA U32 instructions at the absolute address Base.
+// Programs call a kernel routine with JSA to an entry, which jumps to the routine.
+type JumpTable struct {
+	Base    uint32
+	Entries int
+}
+
+// Entry returns the absolute address of entry n
+func (t JumpTable) Entry(n int) uint32 {
+	return t.Base + uint32(n*EntrySize)
+}
+
+// Contains is true if addr is the address of an entry
+func (t JumpTable) Contains(addr uint32) bool {
+	offset := addr - t.Base
+	return (uint64(offset) < uint64(t.Entries*EntrySize)) && (offset%EntrySize == 0)
+}
+
+// InstallJumpTable writes a jump table at base with one entry for each routine address, and returns it
+func InstallJumpTable(bus memory.Bus, base uint32, routines []uint32) (JumpTable, error) {
+	jma, _ := isa.Find("JMA", isa.OperandU32)
+	for i, routine := range routines {
+		addr := base + uint32(i*EntrySize)
+		if err := bus.Write8(addr, jma.Code); err != nil {
+			return JumpTable{}, err
+		}
+
+		if err := bus.Write32(addr+1, routine); err != nil {
+			return JumpTable{}, err
+		}
+	}
+
+	return JumpTable{Base: base, Entries: len(routines)}, nil
+}
+
+// Validate returns an *ImageError for the first instruction of img that makes an absolute reference other than a JMA
+// or JSA to an entry of table, or that is not a valid instruction.
+// The entry point must be the start of an instruction.
+func Validate(img Image, table JumpTable) error {
+	entryFound := false
+	for _, ins := range disasm.Disassemble(img.instructions(), img.Origin, img.Size) {
+		if ins.Addr == img.Entry {
+			entryFound = true
+		}
+
+		if err := validateInstruction(ins, table); err != nil {
+			return &ImageError{Offset: ins.Addr, Text: ins.Text(nil), Err: err}
+		}
+	}
+
+	if !entryFound {
+		return &ImageError{Offset: img.Entry, Text: "entry", Err: ErrEntry}
+	}
+
+	return nil
+}
+
+// validateInstruction returns an error if ins is not a valid instruction or makes an absolute reference
+// that is not a jump table entry
+func validateInstruction(ins disasm.Instruction, table JumpTable) error {
+	if !ins.Op.IsValid() {
+		return ErrInvalidInstruction
+	}
+
+	switch ins.Op.Mnemonic {
+	case "JMA", "JSA":
+		if addr, ok := ins.Target(); !ok || !table.Contains(addr) {
+			return ErrAbsoluteReference
+		}
+
+	case "MOV", "PUL":
+		// Loading CP, DP, or SB would move the program, its data, or its stack to an absolute address
+		switch ins.Op.Operands[0] {
+		case isa.OperandCP, isa.OperandDP0, isa.OperandDP1, isa.OperandSB:
+			return ErrAbsoluteReference
+		}
+	}
+
+	return nil
+}
+
+// Placement is where to load an image: the code is at CP + the image Origin, and the stack is SL + 1 bytes at SB
+type Placement struct {
+	CP uint32
+	SB uint32
+	SL uint16
+}
+
+// region is a range of absolute addresses [start, end)
+type region struct {
+	start uint64
+	end   uint64
+}
+
+// overlaps is true if r and o have an address in common
+func (r region) overlaps(o region) bool {
+	return (r.start < o.end) && (o.start < r.end)
+}
+
+// Loader loads images into a memory.Bus, keeping track of the memory used by each image so they do not overlap
+type Loader struct {
+	bus   memory.Bus
+	table JumpTable
+	used  []region
+}
+
+// New constructs a Loader that loads images into bus, which may only call the kernel through table.
+// The memory of the table is in use.
+func New(bus memory.Bus, table JumpTable) *Loader {
+	start := uint64(table.Base)
+
+	return &Loader{
+		bus:   bus,
+		table: table,
+		used:  []region{{start: start, end: start + uint64(table.Entries*EntrySize)}},
+	}
+}
+
+// Reserve marks size bytes at addr as in use, so that no image is loaded there
+func (l *Loader) Reserve(addr uint32, size uint32) error {
+	r := region{start: uint64(addr), end: uint64(addr) + uint64(size)}
+	for _, u := range l.used {
+		if r.overlaps(u) {
+			return ErrOverlap
+		}
+	}
+
+	l.used = append(l.used, r)
+	return nil
+}
+
+// Load validates img, writes it at the placement, and returns registers initialized by register.OfRegisters with
+// CP, SB, and SL of the placement, DP0 = DP1 = CP, SP = SL, and PC = the image entry, ready to be copied into a
+// cpu.Processor. Since an image cannot load DP, M addresses of the image are relative to CP like its labels.
+// If the image is not valid, or its code or stack overlaps memory in use, nothing is written.
+func (l *Loader) Load(img Image, at Placement) (register.Registers, error) {
+	if err := Validate(img, l.table); err != nil {
+		return register.Registers{}, err
+	}
+
+	var (
+		codeStart = uint64(at.CP) + uint64(img.Origin)
+		code      = region{start: codeStart, end: codeStart + uint64(len(img.Code))}
+		stack     = region{start: uint64(at.SB), end: uint64(at.SB) + uint64(at.SL) + 1}
+	)
+
+	if code.overlaps(stack) {
+		return register.Registers{}, ErrOverlap
+	}
+
+	for _, u := range l.used {
+		if code.overlaps(u) || stack.overlaps(u) {
+			return register.Registers{}, ErrOverlap
+		}
+	}
+
+	if err := memory.WriteBytes(l.bus, uint32(code.start), img.Code); err != nil {
+		return register.Registers{}, err
+	}
+
+	l.used = append(l.used, code, stack)
+
+	regs := register.OfRegisters()
+	regs.CP = at.CP
+	regs.DP0 = at.CP
+	regs.DP1 = at.CP
+	regs.PC = img.Entry
+	regs.SB = at.SB
+	regs.SL = at.SL
+	regs.SP = at.SL
+
+	return regs, nil
+}
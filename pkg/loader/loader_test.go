@@ -0,0 +1,155 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bantling/goprocessor/pkg/asm"
+	"github.com/bantling/goprocessor/pkg/cpu"
+	"github.com/bantling/goprocessor/pkg/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+// image assembles src into an Image, failing if there are any errors
+func image(t *testing.T, src string) Image {
+	prog, err := asm.Assemble("test.s", strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return Image{Origin: prog.Origin, Code: prog.Code, Entry: prog.Origin}
+}
+
+func TestJumpTable(t *testing.T) {
+	var (
+		ram        = memory.NewRAM()
+		table, err = InstallJumpTable(ram, 0x1000, []uint32{0x2000, 0x3000})
+	)
+
+	assert.Nil(t, err)
+	assert.Equal(t, JumpTable{Base: 0x1000, Entries: 2}, table)
+	assert.Equal(t, uint32(0x1005), table.Entry(1))
+
+	assert.True(t, table.Contains(0x1000))
+	assert.True(t, table.Contains(0x1005))
+	assert.False(t, table.Contains(0x1001))
+	assert.False(t, table.Contains(0x100A))
+	assert.False(t, table.Contains(0x0FFB))
+
+	buf := make([]byte, 10)
+	memory.ReadBytes(ram, 0x1000, buf)
+	assert.Equal(t, []byte{0x33, 0x00, 0x00, 0x20, 0x00, 0x33, 0x00, 0x00, 0x30, 0x00}, buf)
+}
+
+func TestValidate(t *testing.T) {
+	table := JumpTable{Base: 0x1000, Entries: 2}
+
+	for _, test := range []struct {
+		src    string
+		offset uint32
+		err    error
+	}{
+		{"JSA 0x1005\nJMP 0\nBEQ $", 0, nil},
+		{"NOP\nJSA 0x1003", 1, ErrAbsoluteReference},
+		{"NOP\nJMA 0x2000", 1, ErrAbsoluteReference},
+		{"JSR R0\nJMA R0", 1, ErrAbsoluteReference},
+		{"MOV CP, 0x2000", 0, ErrAbsoluteReference},
+		{"MOV SB, R0", 0, ErrAbsoluteReference},
+		{"MOV DP0, 0x2000", 0, ErrAbsoluteReference},
+		{"NOP\nMOV DP1, 0x2000", 1, ErrAbsoluteReference},
+		{"MOV DP0, R0", 0, ErrAbsoluteReference},
+		{"MOV DP1, R0", 0, ErrAbsoluteReference},
+		{"PUL CP", 0, ErrAbsoluteReference},
+		{"PUL DP0", 0, ErrAbsoluteReference},
+		{"PUL DP1", 0, ErrAbsoluteReference},
+		{"PSH DP0\nMOV R0, DP1", 0, nil},
+		{".u8 0xFF", 0, ErrInvalidInstruction},
+	} {
+		err := Validate(image(t, test.src), table)
+		if test.err == nil {
+			assert.Nil(t, err, test.src)
+			continue
+		}
+
+		var imageErr *ImageError
+		assert.True(t, errors.As(err, &imageErr), test.src)
+		assert.Equal(t, test.offset, imageErr.Offset, test.src)
+		assert.True(t, errors.Is(err, test.err), test.src)
+	}
+
+	// Data after the instructions is not decoded
+	img := image(t, "NOP\n.u8 0xFF")
+	img.Text = 1
+	assert.Nil(t, Validate(img, table))
+
+	// The entry must be the start of an instruction
+	img = image(t, "JSA 0x1000")
+	img.Entry = 1
+	assert.True(t, errors.Is(Validate(img, table), ErrEntry))
+}
+
+func TestLoad(t *testing.T) {
+	var (
+		ram      = memory.NewRAM()
+		table, _ = InstallJumpTable(ram, 0x00080000, []uint32{0x00090000})
+		kernel   = image(t, "INC R0\nRTS")
+		l        = New(ram, table)
+		program  = image(t, `
+        .org 0x10
+        LD   R0, 5
+        JSA  0x00080000
+        NOP
+`)
+	)
+
+	// The kernel routine is not a program, it only needs to be reserved and written
+	assert.Nil(t, l.Reserve(0x00090000, uint32(len(kernel.Code))))
+	memory.WriteBytes(ram, 0x00090000, kernel.Code)
+	assert.Equal(t, ErrOverlap, l.Reserve(0x00090001, 1))
+
+	// Load the same program twice with different code and stack, and run both of them through the kernel
+	for i, at := range []Placement{
+		{CP: 0x00020000, SB: 0x00030000, SL: 0xFF},
+		{CP: 0x00040000, SB: 0x00050000, SL: 0x1FF},
+	} {
+		regs, err := l.Load(program, at)
+		assert.Nil(t, err, i)
+		assert.Equal(t, at.CP, regs.CP, i)
+		assert.Equal(t, at.CP, regs.DP0, i)
+		assert.Equal(t, at.CP, regs.DP1, i)
+		assert.Equal(t, uint32(0x10), regs.PC, i)
+		assert.Equal(t, at.SB, regs.SB, i)
+		assert.Equal(t, at.SL, regs.SL, i)
+		assert.Equal(t, at.SL, regs.SP, i)
+
+		p := cpu.New(ram)
+		*p.Registers() = regs
+		for j := 0; j < 5; j++ {
+			assert.Nil(t, p.Step(), i)
+		}
+
+		assert.Equal(t, uint64(6), p.Registers().R0, i)
+		assert.Equal(t, uint32(0x10+len(program.Code)-1), p.Registers().PC, i)
+		assert.Equal(t, at.SL, p.Registers().SP, i)
+	}
+
+	// Overlapping code, stack, or jump table is not loaded
+	for _, at := range []Placement{
+		{CP: 0x00020008, SB: 0x00060000, SL: 0xFF},
+		{CP: 0x00060000, SB: 0x000300FF, SL: 0xFF},
+		{CP: 0x0007FFF0, SB: 0x00060000, SL: 0xFF},
+		{CP: 0x00060000, SB: 0x00060010, SL: 0xFF},
+	} {
+		_, err := l.Load(program, at)
+		assert.Equal(t, ErrOverlap, err)
+	}
+
+	// An invalid image is not written
+	_, err := l.Load(image(t, "JMA 0x00090000"), Placement{CP: 0x00060000, SB: 0x00070000, SL: 0xFF})
+	assert.True(t, errors.Is(err, ErrAbsoluteReference))
+	val, _ := ram.Read8(0x00060000)
+	assert.Equal(t, uint8(0), val)
+}
@@ -0,0 +1,91 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package memory
+
+import (
+	"fmt"
+)
+
+const (
+	// OSBase is the lowest address reserved for OS routines
+	OSBase uint32 = 0xFFFF0000
+
+	// OSLimit is the highest address reserved for OS routines
+	OSLimit uint32 = 0xFFFFFFFB
+
+	// IRQVectors is the address of the 32 bit pointers to the routines of the IRQ lines, which occupy the top of the
+	// OS routines up to ResetVector
+	IRQVectors uint32 = 0xFFFFFFDC
+
+	// ResetVector is the address of the 32 bit pointer to the reset/error interrupt routine
+	ResetVector uint32 = 0xFFFFFFFC
+)
+
+// Fault describes a memory access that a Bus refused
+type Fault struct {
+	Addr  uint32
+	Write bool
+}
+
+func (f *Fault) Error() string {
+	if f.Write {
+		return fmt.Sprintf("Memory fault writing %08X", f.Addr)
+	}
+
+	return fmt.Sprintf("Memory fault reading %08X", f.Addr)
+}
+
+// Bus is a 32 bit address space.
+// Multi byte values are stored highest byte first, lowest byte last.
+// Addresses wrap around, so a 32 bit value read at FFFFFFFE is read from FFFFFFFE, FFFFFFFF, 0, and 1.
+// A Bus returns a *Fault if an address cannot be accessed.
+type Bus interface {
+	// Read8 reads an 8 bit value
+	Read8(addr uint32) (uint8, error)
+
+	// Read16 reads a 16 bit value
+	Read16(addr uint32) (uint16, error)
+
+	// Read32 reads a 32 bit value
+	Read32(addr uint32) (uint32, error)
+
+	// Read64 reads a 64 bit value
+	Read64(addr uint32) (uint64, error)
+
+	// Write8 writes an 8 bit value
+	Write8(addr uint32, val uint8) error
+
+	// Write16 writes a 16 bit value
+	Write16(addr uint32, val uint16) error
+
+	// Write32 writes a 32 bit value
+	Write32(addr uint32, val uint32) error
+
+	// Write64 writes a 64 bit value
+	Write64(addr uint32, val uint64) error
+}
+
+// ReadBytes reads len(buf) bytes starting at addr into buf
+func ReadBytes(bus Bus, addr uint32, buf []byte) error {
+	for i := range buf {
+		val, err := bus.Read8(addr + uint32(i))
+		if err != nil {
+			return err
+		}
+
+		buf[i] = val
+	}
+
+	return nil
+}
+
+// WriteBytes writes all of buf starting at addr
+func WriteBytes(bus Bus, addr uint32, buf []byte) error {
+	for i, val := range buf {
+		if err := bus.Write8(addr+uint32(i), val); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,3 @@
+// Package memory defines the 32 bit address space
+// SPDX-License-Identifier: Apache-2.0
+package memory
@@ -0,0 +1,191 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package memory
+
+import (
+	"sort"
+)
+
+const (
+	// PageSize is the number of bytes in a RAM page
+	PageSize uint32 = 0x00001000
+
+	// PageShift is the shift to convert an address to a page number
+	PageShift uint = 12
+
+	// PageOffset is the filter for the offset of an address within a page
+	PageOffset uint32 = PageSize - 1
+)
+
+// page is a single page of RAM
+type page [PageSize]uint8
+
+// RAM is a sparse paged implementation of Bus that covers the whole 4 GiB address space.
+// Pages are only allocated when written, reading a page that has never been written returns zeroes.
+type RAM struct {
+	pages     map[uint32]*page
+	protected map[uint32]bool
+}
+
+// NewRAM constructs an empty RAM
+func NewRAM() *RAM {
+	return &RAM{
+		pages:     map[uint32]*page{},
+		protected: map[uint32]bool{},
+	}
+}
+
+// Protect marks every page that contains any part of [addr, addr + size) as read only.
+// Writes to a read only page return a *Fault.
+func (m *RAM) Protect(addr uint32, size uint32) {
+	if size == 0 {
+		return
+	}
+
+	var (
+		first = addr >> PageShift
+		last  = (addr + size - 1) >> PageShift
+	)
+
+	for pg := first; ; pg++ {
+		m.protected[pg] = true
+		if pg == last {
+			break
+		}
+	}
+}
+
+// IsProtected returns true if the page containing addr is read only
+func (m *RAM) IsProtected(addr uint32) bool {
+	return m.protected[addr>>PageShift]
+}
+
+// Pages returns the numbers of the pages that have been written, in ascending order
+func (m *RAM) Pages() []uint32 {
+	return sortedPages(len(m.pages), func(add func(uint32)) {
+		for pg := range m.pages {
+			add(pg)
+		}
+	})
+}
+
+// ProtectedPages returns the numbers of the read only pages, in ascending order
+func (m *RAM) ProtectedPages() []uint32 {
+	return sortedPages(len(m.protected), func(add func(uint32)) {
+		for pg := range m.protected {
+			add(pg)
+		}
+	})
+}
+
+// Reset frees every page and removes all protection, so that all of memory reads as zeroes and is writable
+func (m *RAM) Reset() {
+	m.pages = map[uint32]*page{}
+	m.protected = map[uint32]bool{}
+}
+
+// Read8 reads an 8 bit value
+func (m *RAM) Read8(addr uint32) (uint8, error) {
+	if pg := m.pages[addr>>PageShift]; pg != nil {
+		return pg[addr&PageOffset], nil
+	}
+
+	return 0, nil
+}
+
+// Read16 reads a 16 bit value
+func (m *RAM) Read16(addr uint32) (uint16, error) {
+	val, err := m.read(addr, 2)
+	return uint16(val), err
+}
+
+// Read32 reads a 32 bit value
+func (m *RAM) Read32(addr uint32) (uint32, error) {
+	val, err := m.read(addr, 4)
+	return uint32(val), err
+}
+
+// Read64 reads a 64 bit value
+func (m *RAM) Read64(addr uint32) (uint64, error) {
+	return m.read(addr, 8)
+}
+
+// Write8 writes an 8 bit value
+func (m *RAM) Write8(addr uint32, val uint8) error {
+	pg, err := m.writablePage(addr)
+	if err != nil {
+		return err
+	}
+
+	pg[addr&PageOffset] = val
+	return nil
+}
+
+// Write16 writes a 16 bit value
+func (m *RAM) Write16(addr uint32, val uint16) error {
+	return m.write(addr, uint64(val), 2)
+}
+
+// Write32 writes a 32 bit value
+func (m *RAM) Write32(addr uint32, val uint32) error {
+	return m.write(addr, uint64(val), 4)
+}
+
+// Write64 writes a 64 bit value
+func (m *RAM) Write64(addr uint32, val uint64) error {
+	return m.write(addr, val, 8)
+}
+
+// read reads size bytes highest byte first
+func (m *RAM) read(addr uint32, size uint32) (uint64, error) {
+	var val uint64
+	for i := uint32(0); i < size; i++ {
+		b, _ := m.Read8(addr + i)
+		val = (val << 8) | uint64(b)
+	}
+
+	return val, nil
+}
+
+// write writes size bytes highest byte first.
+// The write is checked before any byte is written, so a fault leaves memory unchanged.
+func (m *RAM) write(addr uint32, val uint64, size uint32) error {
+	for i := uint32(0); i < size; i++ {
+		if m.protected[(addr+i)>>PageShift] {
+			return &Fault{Addr: addr + i, Write: true}
+		}
+	}
+
+	for i := size; i > 0; i-- {
+		m.Write8(addr+i-1, uint8(val))
+		val >>= 8
+	}
+
+	return nil
+}
+
+// writablePage returns the page containing addr, allocating it if necessary.
+// Returns a *Fault if the page is read only.
+func (m *RAM) writablePage(addr uint32) (*page, error) {
+	pgNum := addr >> PageShift
+	if m.protected[pgNum] {
+		return nil, &Fault{Addr: addr, Write: true}
+	}
+
+	pg := m.pages[pgNum]
+	if pg == nil {
+		pg = new(page)
+		m.pages[pgNum] = pg
+	}
+
+	return pg, nil
+}
+
+// sortedPages collects page numbers from a map iteration, and sorts them
+func sortedPages(n int, each func(add func(uint32))) []uint32 {
+	pages := make([]uint32, 0, n)
+	each(func(pg uint32) { pages = append(pages, pg) })
+	sort.Slice(pages, func(i, j int) bool { return pages[i] < pages[j] })
+
+	return pages
+}
@@ -0,0 +1,119 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package memory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRAMReadWrite(t *testing.T) {
+	var bus Bus = NewRAM()
+
+	// Unwritten memory reads as zero
+	val8, err := bus.Read8(0x12345678)
+	assert.Equal(t, uint8(0), val8)
+	assert.Nil(t, err)
+
+	val64, err := bus.Read64(ResetVector)
+	assert.Equal(t, uint64(0), val64)
+	assert.Nil(t, err)
+
+	// Highest byte is stored first
+	assert.Nil(t, bus.Write16(0x0100, 0x1234))
+	val8, _ = bus.Read8(0x0100)
+	assert.Equal(t, uint8(0x12), val8)
+	val8, _ = bus.Read8(0x0101)
+	assert.Equal(t, uint8(0x34), val8)
+	val16, _ := bus.Read16(0x0100)
+	assert.Equal(t, uint16(0x1234), val16)
+
+	assert.Nil(t, bus.Write32(0x0200, 0x12345678))
+	val32, _ := bus.Read32(0x0200)
+	assert.Equal(t, uint32(0x12345678), val32)
+	val16, _ = bus.Read16(0x0202)
+	assert.Equal(t, uint16(0x5678), val16)
+
+	assert.Nil(t, bus.Write64(0x0300, 0x0123456789ABCDEF))
+	val64, _ = bus.Read64(0x0300)
+	assert.Equal(t, uint64(0x0123456789ABCDEF), val64)
+	val32, _ = bus.Read32(0x0304)
+	assert.Equal(t, uint32(0x89ABCDEF), val32)
+
+	// Values may cross pages
+	assert.Nil(t, bus.Write32(PageSize-2, 0xCAFEBABE))
+	val32, _ = bus.Read32(PageSize - 2)
+	assert.Equal(t, uint32(0xCAFEBABE), val32)
+	val16, _ = bus.Read16(PageSize)
+	assert.Equal(t, uint16(0xBABE), val16)
+
+	// Addresses wrap around
+	assert.Nil(t, bus.Write32(0xFFFFFFFE, 0xDEADBEEF))
+	val16, _ = bus.Read16(0xFFFFFFFE)
+	assert.Equal(t, uint16(0xDEAD), val16)
+	val16, _ = bus.Read16(0)
+	assert.Equal(t, uint16(0xBEEF), val16)
+	val32, _ = bus.Read32(0xFFFFFFFE)
+	assert.Equal(t, uint32(0xDEADBEEF), val32)
+}
+
+func TestRAMProtect(t *testing.T) {
+	ram := NewRAM()
+	assert.Nil(t, ram.Write32(OSBase, 0x11223344))
+
+	ram.Protect(OSBase, 0x10000)
+	assert.True(t, ram.IsProtected(OSBase))
+	assert.True(t, ram.IsProtected(ResetVector))
+	assert.False(t, ram.IsProtected(OSBase-1))
+
+	// Reads still work
+	val32, err := ram.Read32(OSBase)
+	assert.Equal(t, uint32(0x11223344), val32)
+	assert.Nil(t, err)
+
+	// Writes fault
+	assert.Equal(t, &Fault{Addr: ResetVector, Write: true}, ram.Write8(ResetVector, 1))
+	assert.EqualError(t, ram.Write8(ResetVector, 1), "Memory fault writing FFFFFFFC")
+
+	// A write that partially overlaps a protected page does not modify anything
+	assert.Equal(t, &Fault{Addr: OSBase, Write: true}, ram.Write32(OSBase-2, 0xFFFFFFFF))
+	val16, _ := ram.Read16(OSBase - 2)
+	assert.Equal(t, uint16(0), val16)
+}
+
+func TestRAMPages(t *testing.T) {
+	ram := NewRAM()
+	assert.Equal(t, []uint32{}, ram.Pages())
+	assert.Equal(t, []uint32{}, ram.ProtectedPages())
+
+	// Reading does not allocate a page, writing does
+	ram.Read8(0x3000)
+	assert.Nil(t, ram.Write16(0x2FFF, 0x1234))
+	assert.Nil(t, ram.Write8(0x1000, 1))
+	ram.Protect(OSBase, 0x2000)
+	assert.Equal(t, []uint32{1, 2, 3}, ram.Pages())
+	assert.Equal(t, []uint32{OSBase >> PageShift, OSBase>>PageShift + 1}, ram.ProtectedPages())
+
+	ram.Reset()
+	assert.Equal(t, []uint32{}, ram.Pages())
+	assert.Equal(t, []uint32{}, ram.ProtectedPages())
+	val8, _ := ram.Read8(0x1000)
+	assert.Equal(t, uint8(0), val8)
+	assert.Nil(t, ram.Write8(OSBase, 1))
+}
+
+func TestReadWriteBytes(t *testing.T) {
+	ram := NewRAM()
+	assert.Nil(t, WriteBytes(ram, 0x1000, []byte{1, 2, 3, 4}))
+
+	val32, _ := ram.Read32(0x1000)
+	assert.Equal(t, uint32(0x01020304), val32)
+
+	buf := make([]byte, 4)
+	assert.Nil(t, ReadBytes(ram, 0x1001, buf))
+	assert.Equal(t, []byte{2, 3, 4, 0}, buf)
+
+	ram.Protect(0x2000, 1)
+	assert.Equal(t, &Fault{Addr: 0x2000, Write: true}, WriteBytes(ram, 0x1FFF, []byte{1, 2}))
+}
@@ -0,0 +1,18 @@
+// Package obj defines the object file format written by the assembler and combined by the linker.
+//
+// An object file contains up to three sections: code, which is located relative to CP, and data and bss, which are
+// located relative to DP. The bss section has a size but no bytes, it is zero filled when loaded.
+//
+// Symbols are labels and constants. A label is an offset into a section, a constant has an absolute value, and an
+// external symbol is defined by another object file. Only global symbols are visible to other object files.
+//
+// A relocation describes a field of a section that refers to a symbol whose address is not known until the object
+// is linked. The field is zero, and is filled in with the address of the symbol plus an addend. Absolute
+// relocations are used for the targets of JMA and JSA, data pointers loaded into DP, and values of data directives.
+// Relative relocations are used for all other references, and are relative to CP for code and DP for data and bss.
+//
+// Line entries map section offsets to the source lines that generated them, for debuggers.
+//
+// The file is big endian, beginning with the magic bytes GPOB and a 16 bit version.
+// SPDX-License-Identifier: Apache-2.0
+package obj
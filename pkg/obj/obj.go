@@ -0,0 +1,358 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package obj
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+)
+
+const (
+	// Magic is the first four bytes of an object file
+	Magic = "GPOB"
+
+	// Version is the version of the format written by Write
+	Version uint16 = 1
+)
+
+// ObjectError represents an error reading an object file
+type ObjectError string
+
+func (e ObjectError) Error() string {
+	return string(e)
+}
+
+const (
+	// ErrMagic is returned when a file does not begin with Magic
+	ErrMagic = ObjectError("Not An Object File")
+
+	// ErrVersion is returned when a file has a version that cannot be read
+	ErrVersion = ObjectError("Unsupported Object Version")
+
+	// ErrCorrupt is returned when a file is truncated or contains invalid values
+	ErrCorrupt = ObjectError("Corrupt Object File")
+)
+
+// Section identifies the section a symbol, relocation, or line belongs to
+type Section uint8
+
+const (
+	// SectionCode is the code section, located relative to CP
+	SectionCode Section = iota
+
+	// SectionData is the data section, located relative to DP
+	SectionData
+
+	// SectionBSS is the zero filled section that follows the data section
+	SectionBSS
+
+	// SectionAbsolute is the section of constants
+	SectionAbsolute
+
+	// SectionUndefined is the section of external symbols
+	SectionUndefined
+)
+
+// sectionNames are the names of the sections, which are the directives that select them
+var sectionNames = map[Section]string{
+	SectionCode:      ".code",
+	SectionData:      ".data",
+	SectionBSS:       ".bss",
+	SectionAbsolute:  "absolute",
+	SectionUndefined: "undefined",
+}
+
+func (s Section) String() string {
+	return sectionNames[s]
+}
+
+// IsLocated is true if the section has bytes located when the object is linked
+func (s Section) IsLocated() bool {
+	return s <= SectionBSS
+}
+
+// RelocKind is how the address of a relocation is computed
+type RelocKind uint8
+
+const (
+	// RelocAbsolute is the absolute address of the symbol plus the addend
+	RelocAbsolute RelocKind = iota
+
+	// RelocRelative is the address of the symbol plus the addend, relative to CP for a code symbol or DP otherwise
+	RelocRelative
+)
+
+// Symbol is a label, constant, or external symbol
+type Symbol struct {
+	Name string
+
+	// Section is the section a label is in, SectionAbsolute for a constant, or SectionUndefined for an external symbol
+	Section Section
+
+	// Value is the offset of a label into its section, or the value of a constant
+	Value int64
+
+	// Global is true if the symbol is visible to other object files
+	Global bool
+}
+
+// Reloc is a field of a section that refers to a symbol
+type Reloc struct {
+	Section Section
+	Offset  uint32
+	Size    uint8 // the number of bytes of the field: 1, 2, 4, or 8
+	Kind    RelocKind
+
+	// Symbol is the name of a symbol, or of a section of the same object file to refer to the start of it
+	Symbol string
+	Addend int64
+}
+
+// Line maps an offset into a section to the source line that generated the bytes at the offset
+type Line struct {
+	Section Section
+	Offset  uint32
+	Line    uint32
+}
+
+// File is an object file
+type File struct {
+	// Source is the name of the source file that was assembled
+	Source string
+
+	Code    []byte
+	Data    []byte
+	BSSSize uint32
+
+	Symbols []Symbol
+	Relocs  []Reloc
+	Lines   []Line
+}
+
+// Size returns the number of bytes of a section, which is 0 if the section is not located
+func (f *File) Size(s Section) uint32 {
+	switch s {
+	case SectionCode:
+		return uint32(len(f.Code))
+	case SectionData:
+		return uint32(len(f.Data))
+	case SectionBSS:
+		return f.BSSSize
+	}
+
+	return 0
+}
+
+// Lookup returns the symbol with a name, and true if it exists
+func (f *File) Lookup(name string) (Symbol, bool) {
+	for _, sym := range f.Symbols {
+		if sym.Name == name {
+			return sym, true
+		}
+	}
+
+	return Symbol{}, false
+}
+
+// writer writes big endian values, remembering the first error
+type writer struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (w *writer) write(val interface{}) {
+	if w.err == nil {
+		w.err = binary.Write(w.w, binary.BigEndian, val)
+	}
+}
+
+func (w *writer) writeString(s string) {
+	w.write(uint16(len(s)))
+	w.write([]byte(s))
+}
+
+func (w *writer) writeBytes(b []byte) {
+	w.write(uint32(len(b)))
+	w.write(b)
+}
+
+// Write writes an object file in the current Version
+func Write(dst io.Writer, f *File) error {
+	w := &writer{w: bufio.NewWriter(dst)}
+
+	w.write([]byte(Magic))
+	w.write(Version)
+	w.writeString(f.Source)
+	w.writeBytes(f.Code)
+	w.writeBytes(f.Data)
+	w.write(f.BSSSize)
+
+	w.write(uint32(len(f.Symbols)))
+	for _, sym := range f.Symbols {
+		w.writeString(sym.Name)
+		w.write(uint8(sym.Section))
+		w.write(sym.Value)
+		w.write(sym.Global)
+	}
+
+	w.write(uint32(len(f.Relocs)))
+	for _, rel := range f.Relocs {
+		w.write(uint8(rel.Section))
+		w.write(rel.Offset)
+		w.write(rel.Size)
+		w.write(uint8(rel.Kind))
+		w.writeString(rel.Symbol)
+		w.write(rel.Addend)
+	}
+
+	w.write(uint32(len(f.Lines)))
+	for _, line := range f.Lines {
+		w.write(uint8(line.Section))
+		w.write(line.Offset)
+		w.write(line.Line)
+	}
+
+	if w.err != nil {
+		return w.err
+	}
+
+	return w.w.Flush()
+}
+
+// reader reads big endian values, remembering the first error
+type reader struct {
+	r   *bufio.Reader
+	err error
+}
+
+func (r *reader) read(val interface{}) {
+	if r.err == nil {
+		if r.err = binary.Read(r.r, binary.BigEndian, val); (r.err == io.EOF) || (r.err == io.ErrUnexpectedEOF) {
+			r.err = ErrCorrupt
+		}
+	}
+}
+
+func (r *reader) readBytes(n int) []byte {
+	if (r.err != nil) || (n == 0) {
+		return nil
+	}
+
+	// Read through a limit, so a corrupt count does not allocate more than the file contains
+	b, err := ioutil.ReadAll(io.LimitReader(r.r, int64(n)))
+	if (err != nil) || (len(b) != n) {
+		r.err = ErrCorrupt
+	}
+
+	return b
+}
+
+func (r *reader) readString() string {
+	var n uint16
+	r.read(&n)
+	return string(r.readBytes(int(n)))
+}
+
+// readCount reads the number of bytes or entries that follow
+func (r *reader) readCount() int {
+	var n uint32
+	r.read(&n)
+	return int(n)
+}
+
+// Read reads an object file
+func Read(src io.Reader) (*File, error) {
+	var (
+		r       = &reader{r: bufio.NewReader(src)}
+		f       = &File{}
+		version uint16
+	)
+
+	if string(r.readBytes(len(Magic))) != Magic {
+		return nil, ErrMagic
+	}
+
+	if r.read(&version); r.err != nil {
+		return nil, r.err
+	}
+
+	if version != Version {
+		return nil, ErrVersion
+	}
+
+	f.Source = r.readString()
+	f.Code = r.readBytes(r.readCount())
+	f.Data = r.readBytes(r.readCount())
+	r.read(&f.BSSSize)
+
+	for i, n := 0, r.readCount(); (i < n) && (r.err == nil); i++ {
+		var sym Symbol
+		sym.Name = r.readString()
+		r.read(&sym.Section)
+		r.read(&sym.Value)
+		r.read(&sym.Global)
+
+		f.Symbols = append(f.Symbols, sym)
+	}
+
+	for i, n := 0, r.readCount(); (i < n) && (r.err == nil); i++ {
+		var rel Reloc
+		r.read(&rel.Section)
+		r.read(&rel.Offset)
+		r.read(&rel.Size)
+		r.read(&rel.Kind)
+		rel.Symbol = r.readString()
+		r.read(&rel.Addend)
+
+		f.Relocs = append(f.Relocs, rel)
+	}
+
+	for i, n := 0, r.readCount(); (i < n) && (r.err == nil); i++ {
+		var line Line
+		r.read(&line.Section)
+		r.read(&line.Offset)
+		r.read(&line.Line)
+
+		f.Lines = append(f.Lines, line)
+	}
+
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	if err := f.validate(); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// validate returns ErrCorrupt if any symbol, relocation, or line has an invalid section, or a relocation has an
+// invalid size or kind or is not within its section
+func (f *File) validate() error {
+	for _, sym := range f.Symbols {
+		if sym.Section > SectionUndefined {
+			return ErrCorrupt
+		}
+	}
+
+	for _, rel := range f.Relocs {
+		switch {
+		case !rel.Section.IsLocated() || (rel.Section == SectionBSS),
+			(rel.Size != 1) && (rel.Size != 2) && (rel.Size != 4) && (rel.Size != 8),
+			rel.Kind > RelocRelative,
+			uint64(rel.Offset)+uint64(rel.Size) > uint64(f.Size(rel.Section)):
+			return ErrCorrupt
+		}
+	}
+
+	for _, line := range f.Lines {
+		if !line.Section.IsLocated() {
+			return ErrCorrupt
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,98 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package obj
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testFile is an object file with every kind of entry
+var testFile = &File{
+	Source:  "test.s",
+	Code:    []byte{0x37, 0x00, 0x00, 0x00, 0x00, 0x3A},
+	Data:    []byte{0x00, 0x00, 0x00, 0x00},
+	BSSSize: 16,
+	Symbols: []Symbol{
+		{Name: "main", Section: SectionCode, Global: true},
+		{Name: "table", Section: SectionData},
+		{Name: "N", Section: SectionAbsolute, Value: -2},
+		{Name: "print", Section: SectionUndefined},
+	},
+	Relocs: []Reloc{
+		{Section: SectionCode, Offset: 1, Size: 4, Kind: RelocAbsolute, Symbol: "print"},
+		{Section: SectionData, Offset: 0, Size: 4, Kind: RelocRelative, Symbol: ".bss", Addend: 8},
+	},
+	Lines: []Line{
+		{Section: SectionCode, Offset: 0, Line: 3},
+		{Section: SectionCode, Offset: 5, Line: 4},
+	},
+}
+
+func TestWriteRead(t *testing.T) {
+	var buf bytes.Buffer
+	assert.Nil(t, Write(&buf, testFile))
+	assert.Equal(t, []byte("GPOB\x00\x01\x00\x06test.s"), buf.Bytes()[:14])
+
+	f, err := Read(bytes.NewReader(buf.Bytes()))
+	assert.Nil(t, err)
+	assert.Equal(t, testFile, f)
+
+	// Empty sections and tables
+	buf.Reset()
+	assert.Nil(t, Write(&buf, &File{}))
+
+	f, err = Read(&buf)
+	assert.Nil(t, err)
+	assert.Equal(t, &File{}, f)
+}
+
+func TestReadErrors(t *testing.T) {
+	var buf bytes.Buffer
+	Write(&buf, testFile)
+	valid := buf.Bytes()
+
+	// change returns a copy of the valid file with the byte at offset replaced
+	change := func(offset int, val byte) []byte {
+		b := append([]byte(nil), valid...)
+		b[offset] = val
+		return b
+	}
+
+	for _, test := range []struct {
+		name string
+		data []byte
+		err  error
+	}{
+		{"empty", nil, ErrMagic},
+		{"magic", change(0, 'X'), ErrMagic},
+		{"version", change(5, 2), ErrVersion},
+		{"truncated", valid[:len(valid)-1], ErrCorrupt},
+		{"code length", change(14, 0xFF), ErrCorrupt},
+		{"symbol section", change(bytes.Index(valid, []byte("\x00\x05print"))+7, 9), ErrCorrupt},
+		{"reloc size", change(bytes.LastIndex(valid, []byte("\x00\x05print"))-2, 3), ErrCorrupt},
+	} {
+		_, err := Read(bytes.NewReader(test.data))
+		assert.Equal(t, test.err, err, test.name)
+	}
+}
+
+func TestFile(t *testing.T) {
+	assert.Equal(t, uint32(6), testFile.Size(SectionCode))
+	assert.Equal(t, uint32(4), testFile.Size(SectionData))
+	assert.Equal(t, uint32(16), testFile.Size(SectionBSS))
+	assert.Equal(t, uint32(0), testFile.Size(SectionAbsolute))
+
+	sym, ok := testFile.Lookup("table")
+	assert.True(t, ok)
+	assert.Equal(t, SectionData, sym.Section)
+
+	_, ok = testFile.Lookup("missing")
+	assert.False(t, ok)
+
+	assert.Equal(t, ".bss", SectionBSS.String())
+	assert.True(t, SectionBSS.IsLocated())
+	assert.False(t, SectionAbsolute.IsLocated())
+}
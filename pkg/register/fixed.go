@@ -0,0 +1,251 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package register
+
+import (
+	"math"
+	"math/big"
+	"strings"
+)
+
+const (
+	// FixedScale16 is the scaling factor of a 16 bit fixed point value, which has 2 decimal places
+	FixedScale16 int64 = 100
+
+	// FixedScale32 is the scaling factor of a 32 bit fixed point value, which has 4 decimal places
+	FixedScale32 int64 = 10000
+
+	// FixedScale64 is the scaling factor of a 64 bit fixed point value, which has 8 decimal places
+	FixedScale64 int64 = 100000000
+
+	// FixedMax16 is the largest magnitude of a 16 bit fixed point value, 99.99
+	FixedMax16 int64 = 9999
+
+	// FixedMax32 is the largest magnitude of a 32 bit fixed point value, 99,999.9999
+	FixedMax32 int64 = 999999999
+
+	// FixedMax64 is the largest magnitude of a 64 bit fixed point value.
+	// The decimal range of 99,999,999,999.99999999 does not fit in 64 bits, so the range is limited to
+	// 92,233,720,368.54775807.
+	FixedMax64 int64 = math.MaxInt64
+
+	// ErrFixedSyntax is returned by ParseFixed if a string is not a decimal number
+	ErrFixedSyntax = GeneralRegisterError("Invalid Fixed Point Number")
+
+	// ErrFixedRange is returned by ParseFixed if a number is outside the range of the operand size
+	ErrFixedRange = GeneralRegisterError("Fixed Point Number Out Of Range")
+)
+
+// fixedFormat describes the fixed point format of an operand size
+type fixedFormat struct {
+	bits   uint
+	digits int
+	scale  int64
+	max    int64
+}
+
+// fixedFormats are the formats of each effective operand size
+var fixedFormats = [...]fixedFormat{
+	STOperand16: {bits: 16, digits: 2, scale: FixedScale16, max: FixedMax16},
+	STOperand32: {bits: 32, digits: 4, scale: FixedScale32, max: FixedMax32},
+	STOperand64: {bits: 64, digits: 8, scale: FixedScale64, max: FixedMax64},
+}
+
+// fixedFormatOf returns the format of an operand size, where 8 bit operands use the 16 bit format
+func fixedFormatOf(operandSize uint8) fixedFormat {
+	return fixedFormats[EffectiveOperandSize(operandSize, MathFixed)]
+}
+
+// fixed returns the value of r in a fixed point format, sign extended from the size of the format
+func (r GeneralRegister) fixed(f fixedFormat) *big.Int {
+	switch f.bits {
+	case 16:
+		return big.NewInt(int64(r.Int16()))
+	case 32:
+		return big.NewInt(int64(r.Int32()))
+	}
+
+	return big.NewInt(r.Int64())
+}
+
+// setFixed sets r to an exact result in a fixed point format, with the following side effects:
+// - Result is truncated to the size of the format and sign extended
+// - Carry is true if the result is outside the range of the format, meaning more bits are needed
+// - Overflow is true if the result does not fit in the size of the format as a signed value
+// - Zero is true if the stored result is zero
+// - Negative is true if the stored result is negative
+func (r *GeneralRegister) setFixed(val *big.Int, f fixedFormat, st *StatusRegister) {
+	var (
+		mask    = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), f.bits), big.NewInt(1))
+		lowBits = new(big.Int).And(val, mask).Uint64()
+		maxVal  = big.NewInt(f.max)
+		minVal  = new(big.Int).Neg(maxVal)
+	)
+
+	switch f.bits {
+	case 16:
+		r.SetUint16(uint16(lowBits))
+	case 32:
+		r.SetUint32(uint32(lowBits))
+	default:
+		r.SetUint64(lowBits)
+	}
+
+	st.Carry((val.Cmp(maxVal) > 0) || (val.Cmp(minVal) < 0))
+	st.Overflow(!val.IsInt64() || (r.Int64() != val.Int64()))
+	st.Zero(*r == 0)
+	st.Negative(r.Negative())
+}
+
+// roundQuo returns n / d rounded half away from zero
+func roundQuo(n, d *big.Int) *big.Int {
+	q, m := new(big.Int).QuoRem(n, d, new(big.Int))
+
+	// Round away from zero if the remainder is at least half of the divisor
+	if m.Abs(m).Lsh(m, 1).Cmp(new(big.Int).Abs(d)) >= 0 {
+		if n.Sign() == d.Sign() {
+			q.Add(q, big.NewInt(1))
+		} else {
+			q.Sub(q, big.NewInt(1))
+		}
+	}
+
+	return q
+}
+
+// AddFixed sets r = r + op using fixed point math. Carry is not added, since it means more bits are needed.
+// Side effects are as described by setFixed.
+func (r *GeneralRegister) AddFixed(op GeneralRegister, st *StatusRegister) {
+	f := fixedFormatOf(st.OperandSize())
+	r.setFixed(new(big.Int).Add(r.fixed(f), op.fixed(f)), f, st)
+}
+
+// SubtractFixed sets r = r - op using fixed point math. Carry is not subtracted, since it means more bits are needed.
+// Side effects are as described by setFixed.
+func (r *GeneralRegister) SubtractFixed(op GeneralRegister, st *StatusRegister) {
+	f := fixedFormatOf(st.OperandSize())
+	r.setFixed(new(big.Int).Sub(r.fixed(f), op.fixed(f)), f, st)
+}
+
+// MultiplyFixed sets r = r * op using fixed point math, rounding half away from zero to the decimal places of the
+// operand size. Unlike integer math, the result is never split across r and op, op is unchanged.
+// Side effects are as described by setFixed.
+func (r *GeneralRegister) MultiplyFixed(op GeneralRegister, st *StatusRegister) {
+	f := fixedFormatOf(st.OperandSize())
+	product := new(big.Int).Mul(r.fixed(f), op.fixed(f))
+	r.setFixed(roundQuo(product, big.NewInt(f.scale)), f, st)
+}
+
+// DivideFixed sets r = r / op using fixed point math, rounding half away from zero to the decimal places of the
+// operand size. Unlike integer math, there is no remainder, op is unchanged.
+// Side effects are as described by setFixed, except that Carry is also true if there is a remainder, so the result
+// is rounded, like the modulo of integer DIV.
+//
+// Returns ErrDivisionByZero if op = 0
+func (r *GeneralRegister) DivideFixed(op GeneralRegister, st *StatusRegister) error {
+	f := fixedFormatOf(st.OperandSize())
+	divisor := op.fixed(f)
+	if divisor.Sign() == 0 {
+		return ErrDivisionByZero
+	}
+
+	dividend := new(big.Int).Mul(r.fixed(f), big.NewInt(f.scale))
+	r.setFixed(roundQuo(dividend, divisor), f, st)
+
+	if new(big.Int).Rem(dividend, divisor).Sign() != 0 {
+		st.Carry(true)
+	}
+
+	return nil
+}
+
+// CompareFixed compares r with op using fixed point math, with the following side effects:
+// - Carry is true if r >= op
+// - Overflow is true if r >= op
+// - Zero is true if r == op
+//
+// Fixed point values are always signed, so Carry and Overflow are the same.
+func (r GeneralRegister) CompareFixed(op GeneralRegister, st *StatusRegister) {
+	var (
+		f   = fixedFormatOf(st.OperandSize())
+		cmp = r.fixed(f).Cmp(op.fixed(f))
+	)
+
+	st.Carry(cmp >= 0)
+	st.Overflow(cmp >= 0)
+	st.Zero(cmp == 0)
+}
+
+// FormatFixed returns r as a decimal string in the fixed point format of an operand size, EG -12.34.
+// All decimal places are included, even if they are zero.
+func (r GeneralRegister) FormatFixed(operandSize uint8) string {
+	var (
+		f   = fixedFormatOf(operandSize)
+		val = r.fixed(f)
+		neg = val.Sign() < 0
+		s   = val.Abs(val).String()
+	)
+
+	if len(s) <= f.digits {
+		s = strings.Repeat("0", f.digits-len(s)+1) + s
+	}
+
+	s = s[:len(s)-f.digits] + "." + s[len(s)-f.digits:]
+	if neg {
+		s = "-" + s
+	}
+
+	return s
+}
+
+// ParseFixed parses a decimal string into the fixed point format of an operand size.
+// The string has an optional sign, at least one digit, and an optional decimal point followed by digits.
+// Decimal places beyond those of the operand size are rounded half away from zero.
+// The result is sign extended to 64 bits.
+//
+// Returns ErrFixedSyntax if the string is not a decimal number, or ErrFixedRange if it is outside the range of the
+// operand size.
+func ParseFixed(s string, operandSize uint8) (GeneralRegister, error) {
+	var (
+		f        = fixedFormatOf(operandSize)
+		text     = s
+		neg      bool
+		whole    string
+		fraction string
+	)
+
+	if (text != "") && ((text[0] == '+') || (text[0] == '-')) {
+		neg, text = text[0] == '-', text[1:]
+	}
+
+	whole = text
+	if i := strings.IndexByte(text, '.'); i >= 0 {
+		whole, fraction = text[:i], text[i+1:]
+	}
+
+	if (whole+fraction == "") || strings.Trim(whole+fraction, "0123456789") != "" {
+		return 0, ErrFixedSyntax
+	}
+
+	// Scale the digits to the decimal places of the operand size, rounding any extra places
+	var (
+		val, _ = new(big.Int).SetString(whole+fraction, 10)
+		places = f.digits - len(fraction)
+	)
+
+	if places >= 0 {
+		val.Mul(val, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(places)), nil))
+	} else {
+		val = roundQuo(val, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(-places)), nil))
+	}
+
+	if neg {
+		val.Neg(val)
+	}
+
+	if val.CmpAbs(big.NewInt(f.max)) > 0 {
+		return 0, ErrFixedRange
+	}
+
+	return GeneralRegister(val.Int64()), nil
+}
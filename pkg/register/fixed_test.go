@@ -0,0 +1,205 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package register
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// mustParseFixed parses a fixed point value, failing the test if it is invalid
+func mustParseFixed(t *testing.T, s string, operandSize uint8) GeneralRegister {
+	r, err := ParseFixed(s, operandSize)
+	assert.Nil(t, err, s)
+
+	return r
+}
+
+func TestGeneralRegisterFixedFormat(t *testing.T) {
+	for _, test := range []struct {
+		in          string
+		operandSize uint8
+		out         string
+		val         int64
+	}{
+		{"0", Operand8, "0.00", 0},
+		{"1", Operand16, "1.00", 100},
+		{"-1.5", Operand16, "-1.50", -150},
+		{".05", Operand16, "0.05", 5},
+		{"+99.99", Operand16, "99.99", 9999},
+		{"-99.99", Operand8, "-99.99", -9999},
+		{"1.005", Operand16, "1.01", 101},
+		{"-1.005", Operand16, "-1.01", -101},
+		{"1.004", Operand16, "1.00", 100},
+		{"99999.9999", Operand32, "99999.9999", 999999999},
+		{"-0.00005", Operand32, "-0.0001", -1},
+		{"92233720368.54775807", Operand64, "92233720368.54775807", math.MaxInt64},
+		{"-0.00000001", Operand64, "-0.00000001", -1},
+	} {
+		r := mustParseFixed(t, test.in, test.operandSize)
+		assert.Equal(t, test.val, r.Int64(), test.in)
+		assert.Equal(t, test.out, r.FormatFixed(test.operandSize), test.in)
+	}
+
+	for _, in := range []string{"", "-", ".", "1.2.3", "1e2", "x", "1,000", " 1"} {
+		_, err := ParseFixed(in, Operand16)
+		assert.Equal(t, ErrFixedSyntax, err, in)
+	}
+
+	for _, test := range []struct {
+		in          string
+		operandSize uint8
+	}{
+		{"100", Operand16},
+		{"-99.995", Operand16},
+		{"100000", Operand32},
+		{"92233720368.54775808", Operand64},
+	} {
+		_, err := ParseFixed(test.in, test.operandSize)
+		assert.Equal(t, ErrFixedRange, err, test.in)
+	}
+}
+
+func TestGeneralRegisterAddFixed(t *testing.T) {
+	st := status(Operand16, MathFixed)
+	st.SetCarry()
+	r := mustParseFixed(t, "12.34", Operand16)
+
+	// Carry is not added
+	r.AddFixed(mustParseFixed(t, "-0.34", Operand16), &st)
+	assert.Equal(t, "12.00", r.FormatFixed(Operand16))
+	assert.False(t, st.IsCarry())
+	assert.False(t, st.IsOverflow())
+	assert.False(t, st.IsZero())
+	assert.False(t, st.IsNegative())
+
+	r.AddFixed(mustParseFixed(t, "-12", Operand16), &st)
+	assert.True(t, st.IsZero())
+
+	// Out of the decimal range but within 16 bits
+	r = mustParseFixed(t, "99.99", Operand16)
+	r.AddFixed(mustParseFixed(t, "0.01", Operand16), &st)
+	assert.Equal(t, int64(10000), r.Int64())
+	assert.True(t, st.IsCarry())
+	assert.False(t, st.IsOverflow())
+
+	// 8 bit operands are 16 bits
+	st = status(Operand8, MathFixed)
+	r = mustParseFixed(t, "-99.99", Operand8)
+	r.AddFixed(mustParseFixed(t, "-99.99", Operand8), &st)
+	assert.Equal(t, int64(-19998), r.Int64())
+	assert.True(t, st.IsCarry())
+	assert.False(t, st.IsOverflow())
+	assert.True(t, st.IsNegative())
+
+	// Out of 32 bits
+	st = status(Operand32, MathFixed)
+	r = GeneralRegister(math.MaxInt32)
+	r.AddFixed(1, &st)
+	assert.Equal(t, int64(math.MinInt32), r.Int64())
+	assert.True(t, st.IsCarry())
+	assert.True(t, st.IsOverflow())
+	assert.True(t, st.IsNegative())
+}
+
+func TestGeneralRegisterSubtractFixed(t *testing.T) {
+	st := status(Operand32, MathFixed)
+	st.SetCarry()
+	r := mustParseFixed(t, "1.5", Operand32)
+
+	r.SubtractFixed(mustParseFixed(t, "2.25", Operand32), &st)
+	assert.Equal(t, "-0.7500", r.FormatFixed(Operand32))
+	assert.False(t, st.IsCarry())
+	assert.False(t, st.IsOverflow())
+	assert.True(t, st.IsNegative())
+
+	st = status(Operand64, MathFixed)
+	r = GeneralRegister(1)
+	r.SubtractFixed(GeneralRegister(FixedMax64), &st)
+	assert.Equal(t, -FixedMax64+1, r.Int64())
+	assert.False(t, st.IsCarry())
+
+	r.SubtractFixed(3, &st)
+	assert.Equal(t, int64(math.MaxInt64), r.Int64())
+	assert.True(t, st.IsCarry())
+	assert.True(t, st.IsOverflow())
+}
+
+func TestGeneralRegisterMultiplyFixed(t *testing.T) {
+	st := status(Operand16, MathFixed)
+	r := mustParseFixed(t, "1.5", Operand16)
+	op := mustParseFixed(t, "-2.25", Operand16)
+
+	// -3.375 rounds away from zero
+	r.MultiplyFixed(op, &st)
+	assert.Equal(t, "-3.38", r.FormatFixed(Operand16))
+	assert.Equal(t, "-2.25", op.FormatFixed(Operand16))
+	assert.False(t, st.IsCarry())
+	assert.True(t, st.IsNegative())
+
+	r = mustParseFixed(t, "0.05", Operand16)
+	r.MultiplyFixed(mustParseFixed(t, "0.09", Operand16), &st)
+	assert.Equal(t, "0.00", r.FormatFixed(Operand16))
+	assert.True(t, st.IsZero())
+
+	r = mustParseFixed(t, "10", Operand16)
+	r.MultiplyFixed(mustParseFixed(t, "10", Operand16), &st)
+	assert.True(t, st.IsCarry())
+
+	// 64 bit products need 128 bits before they are scaled
+	st = status(Operand64, MathFixed)
+	r = mustParseFixed(t, "12345678901.23456789", Operand64)
+	r.MultiplyFixed(mustParseFixed(t, "2", Operand64), &st)
+	assert.Equal(t, "24691357802.46913578", r.FormatFixed(Operand64))
+	assert.False(t, st.IsCarry())
+	assert.False(t, st.IsOverflow())
+}
+
+func TestGeneralRegisterDivideFixed(t *testing.T) {
+	st := status(Operand32, MathFixed)
+	r := mustParseFixed(t, "10", Operand32)
+	op := mustParseFixed(t, "3", Operand32)
+
+	assert.Nil(t, r.DivideFixed(op, &st))
+	assert.Equal(t, "3.3333", r.FormatFixed(Operand32))
+	assert.Equal(t, "3.0000", op.FormatFixed(Operand32))
+	assert.True(t, st.IsCarry()) // rounded
+
+	r = mustParseFixed(t, "-20", Operand32)
+	assert.Nil(t, r.DivideFixed(op, &st))
+	assert.Equal(t, "-6.6667", r.FormatFixed(Operand32))
+	assert.True(t, st.IsNegative())
+
+	r = mustParseFixed(t, "1", Operand32)
+	assert.Nil(t, r.DivideFixed(mustParseFixed(t, "0.0001", Operand32), &st))
+	assert.Equal(t, "10000.0000", r.FormatFixed(Operand32))
+	assert.False(t, st.IsCarry())
+
+	r = mustParseFixed(t, "100", Operand32)
+	assert.Nil(t, r.DivideFixed(mustParseFixed(t, "0.0001", Operand32), &st))
+	assert.True(t, st.IsCarry())
+
+	assert.Equal(t, ErrDivisionByZero, r.DivideFixed(0, &st))
+}
+
+func TestGeneralRegisterCompareFixed(t *testing.T) {
+	st := status(Operand16, MathFixed)
+	a := mustParseFixed(t, "-1", Operand16)
+	b := mustParseFixed(t, "0.5", Operand16)
+
+	a.CompareFixed(b, &st)
+	assert.False(t, st.IsCarry())
+	assert.False(t, st.IsOverflow())
+	assert.False(t, st.IsZero())
+
+	b.CompareFixed(a, &st)
+	assert.True(t, st.IsCarry())
+	assert.True(t, st.IsOverflow())
+	assert.False(t, st.IsZero())
+
+	// Only the lowest 16 bits are compared
+	(a | 0x10000).CompareFixed(a, &st)
+	assert.True(t, st.IsZero())
+}
@@ -0,0 +1,287 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package register
+
+import (
+	"math"
+)
+
+const (
+	// halfInfinity is the bits of a positive binary16 infinity
+	halfInfinity uint16 = 0x7C00
+
+	// halfNaN is the bits of the quiet binary16 NaN produced by HalfBits
+	halfNaN uint16 = 0x7E00
+
+	// halfSign is the sign bit of a binary16 value
+	halfSign uint16 = 0x8000
+)
+
+// HalfBits returns the IEEE 754 binary16 (half precision) representation of f, rounding to nearest even.
+// Values too large for half precision become infinity, and values too small become subnormal or zero.
+// A NaN becomes a quiet NaN with the same sign.
+func HalfBits(f float64) uint16 {
+	var sign uint16
+	if math.Signbit(f) {
+		sign = halfSign
+	}
+
+	switch {
+	case math.IsNaN(f):
+		return sign | halfNaN
+	case math.IsInf(f, 0):
+		return sign | halfInfinity
+	}
+
+	// Subnormals are multiples of 2^-24 below the smallest normal of 2^-14, where the multiple is the bits.
+	// A multiple that rounds up to 2^10 is the bits of the smallest normal.
+	a := math.Abs(f)
+	if a < 0x1p-14 {
+		return sign | uint16(math.RoundToEven(a*0x1p24))
+	}
+
+	// Normals are a 11 bit significand m in [2^10, 2^11) times 2^(e-10), with a biased exponent of e+15.
+	// The bits are (e+15)<<10 + m-2^10 = (e+14)<<10 + m, so a significand that rounds up to 2^11 carries into the
+	// exponent, and the largest exponent that carries becomes infinity.
+	_, exp := math.Frexp(a)
+	e := exp - 1
+	if e > 15 {
+		return sign | halfInfinity
+	}
+
+	m := uint32(math.RoundToEven(math.Ldexp(a, 10-e)))
+	if bits := uint32(e+14)<<10 + m; bits < uint32(halfInfinity) {
+		return sign | uint16(bits)
+	}
+
+	return sign | halfInfinity
+}
+
+// HalfFromBits returns the value of the IEEE 754 binary16 (half precision) representation b, which is always exact
+func HalfFromBits(b uint16) float64 {
+	var (
+		exp = int(b>>10) & 0x1F
+		m   = float64(b & 0x3FF)
+		f   float64
+	)
+
+	switch exp {
+	case 0:
+		f = math.Ldexp(m, -24)
+	case 0x1F:
+		if m == 0 {
+			f = math.Inf(1)
+		} else {
+			f = math.NaN()
+		}
+	default:
+		f = math.Ldexp(1024+m, exp-25)
+	}
+
+	if b&halfSign != 0 {
+		f = -f
+	}
+
+	return f
+}
+
+// Float returns r as a floating point value of an operand size.
+// Operand sizes of 8 and 16 bits are half precision, 32 bits are single precision, and 64 bits are double precision.
+func (r GeneralRegister) Float(operandSize uint8) float64 {
+	switch EffectiveOperandSize(operandSize, MathFloat) {
+	case STOperand16:
+		return HalfFromBits(r.Uint16())
+	case STOperand32:
+		return float64(math.Float32frombits(r.Uint32()))
+	}
+
+	return math.Float64frombits(r.Uint64())
+}
+
+// SetFloat sets r to f, rounded to the floating point precision of an operand size as described by Float.
+// The result is sign extended, so that the sign of a half or single precision value is the sign of the register.
+func (r *GeneralRegister) SetFloat(f float64, operandSize uint8) {
+	switch EffectiveOperandSize(operandSize, MathFloat) {
+	case STOperand16:
+		r.SetUint16(HalfBits(f))
+	case STOperand32:
+		r.SetUint32(math.Float32bits(float32(f)))
+	default:
+		r.SetUint64(math.Float64bits(f))
+	}
+}
+
+// setFloat sets r to f in the precision of the operand size, with the following side effects:
+// - Zero is true if the rounded result is zero
+// - Negative is true if the rounded result is negative
+//
+// Returns the rounded result
+func (r *GeneralRegister) setFloat(f float64, st *StatusRegister) float64 {
+	r.SetFloat(f, st.OperandSize())
+	f = r.Float(st.OperandSize())
+
+	st.Zero(f == 0)
+	st.Negative(f < 0)
+
+	return f
+}
+
+// setFloatALU sets r to f as described by setFloat, with the following additional side effects:
+// - Carry is true if the result is NaN
+// - Overflow is true if divideByZero is true
+func (r *GeneralRegister) setFloatALU(f float64, divideByZero bool, st *StatusRegister) {
+	st.Carry(math.IsNaN(r.setFloat(f, st)))
+	st.Overflow(divideByZero)
+}
+
+// AddFloat sets r = r + op using floating point math. Carry is not added, since it means NaN.
+// Side effects are as described by setFloatALU, where Overflow is always false.
+func (r *GeneralRegister) AddFloat(op GeneralRegister, st *StatusRegister) {
+	size := st.OperandSize()
+	r.setFloatALU(r.Float(size)+op.Float(size), false, st)
+}
+
+// SubtractFloat sets r = r - op using floating point math. Carry is not subtracted, since it means NaN.
+// Side effects are as described by setFloatALU, where Overflow is always false.
+func (r *GeneralRegister) SubtractFloat(op GeneralRegister, st *StatusRegister) {
+	size := st.OperandSize()
+	r.setFloatALU(r.Float(size)-op.Float(size), false, st)
+}
+
+// MultiplyFloat sets r = r * op using floating point math. Unlike integer math, op is unchanged.
+// Side effects are as described by setFloatALU, where Overflow is always false.
+func (r *GeneralRegister) MultiplyFloat(op GeneralRegister, st *StatusRegister) {
+	size := st.OperandSize()
+	r.setFloatALU(r.Float(size)*op.Float(size), false, st)
+}
+
+// DivideFloat sets r = r / op using floating point math. Unlike integer math, there is no remainder, op is unchanged,
+// and dividing by zero is not an error: the result is an infinity, or NaN if r is also zero or NaN.
+// Side effects are as described by setFloatALU, where Overflow is true if a non-NaN value is divided by zero.
+func (r *GeneralRegister) DivideFloat(op GeneralRegister, st *StatusRegister) {
+	var (
+		size     = st.OperandSize()
+		dividend = r.Float(size)
+		divisor  = op.Float(size)
+	)
+
+	r.setFloatALU(dividend/divisor, (divisor == 0) && !math.IsNaN(dividend), st)
+}
+
+// CompareFloat compares r with op using floating point math, with the following side effects:
+// - Carry is true if r or op is NaN, in which case they are unordered and no other flag is true
+// - Overflow is true if r >= op
+// - Zero is true if r == op, where -0 == +0
+func (r GeneralRegister) CompareFloat(op GeneralRegister, st *StatusRegister) {
+	var (
+		size = st.OperandSize()
+		a    = r.Float(size)
+		b    = op.Float(size)
+	)
+
+	st.Carry(math.IsNaN(a) || math.IsNaN(b))
+	st.Overflow(a >= b)
+	st.Zero(a == b)
+}
+
+// PowerFloat sets r = r ^ op using floating point math, with the following side effects:
+// - Overflow is true if r is zero and op is negative, which is a division by zero that results in an infinity
+// - Zero and Negative are as described by setFloat
+func (r *GeneralRegister) PowerFloat(op GeneralRegister, st *StatusRegister) {
+	var (
+		size = st.OperandSize()
+		base = r.Float(size)
+		exp  = op.Float(size)
+	)
+
+	r.setFloat(math.Pow(base, exp), st)
+	st.Overflow((base == 0) && (exp < 0))
+}
+
+// FloatFunction sets r = fn(op) using floating point math, where fn is computed in double precision and rounded to
+// the precision of the operand size. The Zero and Negative side effects are as described by setFloat.
+//
+// The unary floating point instructions pass r as op, EG FABS is r.FloatFunction(r, math.Abs, st).
+func (r *GeneralRegister) FloatFunction(op GeneralRegister, fn func(float64) float64, st *StatusRegister) {
+	r.setFloat(fn(op.Float(st.OperandSize())), st)
+}
+
+// SignedToFloat converts r from a signed integer to a floating point value of the operand size, rounding to nearest
+// even. Side effects are as described by setFloat.
+func (r *GeneralRegister) SignedToFloat(st *StatusRegister) {
+	val := *r
+	val.ExtendSign(*st)
+	r.setFloat(float64(val.Int64()), st)
+}
+
+// UnsignedToFloat converts r from an unsigned integer to a floating point value of the operand size, rounding to
+// nearest even. Side effects are as described by setFloat.
+func (r *GeneralRegister) UnsignedToFloat(st *StatusRegister) {
+	val := *r
+	val.ZeroHigherBits(*st)
+	r.setFloat(float64(val.Uint64()), st)
+}
+
+// FloatToSigned converts r from a floating point value of the operand size to a signed integer, with the following
+// side effects:
+// - The value is truncated toward zero
+// - A value outside the range of the effective operand size becomes the smallest or largest signed integer
+// - NaN becomes 0
+// - Result is sign extended
+// - Zero is true if result is zero
+// - Negative is true if result is negative
+func (r *GeneralRegister) FloatToSigned(st *StatusRegister) {
+	var (
+		bits  = uint(8) << st.EffectiveOperandSize()
+		max   = int64(1)<<(bits-1) - 1
+		limit = math.Ldexp(1, int(bits)-1)
+		f     = math.Trunc(r.Float(st.OperandSize()))
+	)
+
+	switch {
+	case math.IsNaN(f):
+		*r = 0
+	case f >= limit:
+		*r = GeneralRegister(max)
+	case f < -limit:
+		*r = GeneralRegister(-max - 1)
+	default:
+		*r = GeneralRegister(int64(f))
+	}
+
+	r.ExtendSign(*st)
+
+	st.Zero(*r == 0)
+	st.Negative(r.Negative())
+}
+
+// FloatToUnsigned converts r from a floating point value of the operand size to an unsigned integer, with the
+// following side effects:
+// - The value is truncated toward zero
+// - A value outside the range of the effective operand size becomes 0 or the largest unsigned integer
+// - NaN becomes 0
+// - Result is sign extended
+// - Zero is true if result is zero
+// - Negative is true if result is negative
+func (r *GeneralRegister) FloatToUnsigned(st *StatusRegister) {
+	var (
+		bits  = uint(8) << st.EffectiveOperandSize()
+		max   = uint64(math.MaxUint64) >> (64 - bits)
+		limit = math.Ldexp(1, int(bits))
+		f     = math.Trunc(r.Float(st.OperandSize()))
+	)
+
+	switch {
+	case math.IsNaN(f), f <= 0:
+		*r = 0
+	case f >= limit:
+		*r = GeneralRegister(max)
+	default:
+		*r = GeneralRegister(uint64(f))
+	}
+
+	r.ExtendSign(*st)
+
+	st.Zero(*r == 0)
+	st.Negative(r.Negative())
+}
@@ -0,0 +1,253 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package register
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// floatRegister returns a register containing f in the precision of an operand size
+func floatRegister(f float64, operandSize uint8) GeneralRegister {
+	var r GeneralRegister
+	r.SetFloat(f, operandSize)
+
+	return r
+}
+
+func TestHalfBits(t *testing.T) {
+	for _, test := range []struct {
+		in  float64
+		out uint16
+	}{
+		{0, 0x0000},
+		{math.Copysign(0, -1), 0x8000},
+		{1, 0x3C00},
+		{-2, 0xC000},
+		{0.1, 0x2E66},
+		{65504, 0x7BFF},
+		{65519.99, 0x7BFF},
+		{65520, 0x7C00},
+		{1e10, 0x7C00},
+		{math.Inf(-1), 0xFC00},
+		{0x1p-14, 0x0400},
+		{0x1p-24, 0x0001},
+		{0x1p-25, 0x0000},
+		{0x1.8p-25, 0x0001},
+		{0x1.ffcp-15, 0x0400},
+		{1 + 0x1p-11, 0x3C00},
+		{1 + 0x3p-11, 0x3C02},
+		{-(1 + 0x1p-10 + 0x1p-11), 0xBC02},
+	} {
+		assert.Equal(t, test.out, HalfBits(test.in), "%g", test.in)
+	}
+
+	assert.Equal(t, uint16(0x7E00), HalfBits(math.NaN()))
+	assert.True(t, math.IsNaN(HalfFromBits(0x7C01)))
+	assert.True(t, math.IsInf(HalfFromBits(0xFC00), -1))
+	assert.Equal(t, 0x1p-24, HalfFromBits(0x0001))
+	assert.Equal(t, 65504.0, HalfFromBits(0x7BFF))
+
+	// Every value that is not NaN round trips
+	for b := 0; b <= math.MaxUint16; b++ {
+		if f := HalfFromBits(uint16(b)); !math.IsNaN(f) {
+			assert.Equal(t, uint16(b), HalfBits(f), "%04X", b)
+		}
+	}
+}
+
+func TestGeneralRegisterFloat(t *testing.T) {
+	r := floatRegister(-1.5, Operand8)
+	assert.Equal(t, uint64(0xFFFFFFFFFFFFBE00), uint64(r))
+	assert.Equal(t, -1.5, r.Float(Operand16))
+
+	r = floatRegister(0.1, Operand32)
+	assert.Equal(t, uint64(math.Float32bits(0.1)), uint64(r))
+	assert.Equal(t, float64(float32(0.1)), r.Float(Operand32))
+
+	r = floatRegister(0.1, Operand64)
+	assert.Equal(t, 0.1, r.Float(Operand64))
+}
+
+func TestGeneralRegisterFloatALU(t *testing.T) {
+	st := status(Operand16, MathFloat)
+	st.SetCarry()
+
+	// Carry is not added
+	r := floatRegister(1.5, Operand16)
+	r.AddFloat(floatRegister(2.25, Operand16), &st)
+	assert.Equal(t, 3.75, r.Float(Operand16))
+	assert.False(t, st.IsCarry())
+	assert.False(t, st.IsOverflow())
+	assert.False(t, st.IsZero())
+	assert.False(t, st.IsNegative())
+
+	r.SubtractFloat(floatRegister(4, Operand16), &st)
+	assert.Equal(t, -0.25, r.Float(Operand16))
+	assert.True(t, st.IsNegative())
+
+	// Half precision rounds to nearest even: 2049 is halfway between 2048 and 2050
+	r = floatRegister(2048, Operand16)
+	r.AddFloat(floatRegister(1, Operand16), &st)
+	assert.Equal(t, 2048.0, r.Float(Operand16))
+
+	r = floatRegister(2050, Operand16)
+	r.AddFloat(floatRegister(1, Operand16), &st)
+	assert.Equal(t, 2052.0, r.Float(Operand16))
+
+	// Results too large for half precision are infinite
+	r.MultiplyFloat(floatRegister(32, Operand16), &st)
+	assert.True(t, math.IsInf(r.Float(Operand16), 1))
+	assert.False(t, st.IsCarry())
+
+	r.SubtractFloat(r, &st)
+	assert.True(t, math.IsNaN(r.Float(Operand16)))
+	assert.True(t, st.IsCarry())
+	assert.False(t, st.IsZero())
+	assert.False(t, st.IsNegative())
+
+	// Division by zero is an infinity, or NaN for 0 / 0
+	st = status(Operand32, MathFloat)
+	r = floatRegister(1, Operand32)
+	r.DivideFloat(floatRegister(3, Operand32), &st)
+	assert.Equal(t, float64(float32(1)/3), r.Float(Operand32))
+	assert.False(t, st.IsOverflow())
+
+	r.DivideFloat(floatRegister(math.Copysign(0, -1), Operand32), &st)
+	assert.True(t, math.IsInf(r.Float(Operand32), -1))
+	assert.True(t, st.IsOverflow())
+	assert.False(t, st.IsCarry())
+	assert.True(t, st.IsNegative())
+
+	r = 0
+	r.DivideFloat(0, &st)
+	assert.True(t, math.IsNaN(r.Float(Operand32)))
+	assert.True(t, st.IsOverflow())
+	assert.True(t, st.IsCarry())
+
+	r.DivideFloat(0, &st)
+	assert.False(t, st.IsOverflow())
+	assert.True(t, st.IsCarry())
+
+	// Single precision
+	r = floatRegister(16777216, Operand32)
+	r.AddFloat(floatRegister(1, Operand32), &st)
+	assert.Equal(t, 16777216.0, r.Float(Operand32))
+	assert.False(t, st.IsOverflow())
+}
+
+func TestGeneralRegisterCompareFloat(t *testing.T) {
+	st := status(Operand64, MathFloat)
+	a := floatRegister(-1, Operand64)
+	b := floatRegister(0.5, Operand64)
+
+	a.CompareFloat(b, &st)
+	assert.False(t, st.IsCarry())
+	assert.False(t, st.IsOverflow())
+	assert.False(t, st.IsZero())
+
+	b.CompareFloat(a, &st)
+	assert.False(t, st.IsCarry())
+	assert.True(t, st.IsOverflow())
+	assert.False(t, st.IsZero())
+
+	floatRegister(0, Operand64).CompareFloat(floatRegister(math.Copysign(0, -1), Operand64), &st)
+	assert.True(t, st.IsOverflow())
+	assert.True(t, st.IsZero())
+
+	a.CompareFloat(floatRegister(math.NaN(), Operand64), &st)
+	assert.True(t, st.IsCarry())
+	assert.False(t, st.IsOverflow())
+	assert.False(t, st.IsZero())
+}
+
+func TestGeneralRegisterFloatFunctions(t *testing.T) {
+	st := status(Operand16, MathFloat)
+	r := floatRegister(-2.5, Operand16)
+
+	r.FloatFunction(r, math.Abs, &st)
+	assert.Equal(t, 2.5, r.Float(Operand16))
+	assert.False(t, st.IsNegative())
+
+	r.FloatFunction(floatRegister(2, Operand16), math.Sqrt, &st)
+	assert.Equal(t, HalfFromBits(HalfBits(math.Sqrt2)), r.Float(Operand16))
+
+	r.FloatFunction(r, math.Floor, &st)
+	assert.Equal(t, 1.0, r.Float(Operand16))
+
+	r.FloatFunction(floatRegister(-1, Operand16), math.Log, &st)
+	assert.True(t, math.IsNaN(r.Float(Operand16)))
+	assert.False(t, st.IsZero())
+	assert.False(t, st.IsNegative())
+
+	r = floatRegister(2, Operand16)
+	r.PowerFloat(floatRegister(10, Operand16), &st)
+	assert.Equal(t, 1024.0, r.Float(Operand16))
+	assert.False(t, st.IsOverflow())
+
+	r = 0
+	r.PowerFloat(floatRegister(-1, Operand16), &st)
+	assert.True(t, math.IsInf(r.Float(Operand16), 1))
+	assert.True(t, st.IsOverflow())
+}
+
+func TestGeneralRegisterFloatConversions(t *testing.T) {
+	// Integers of the effective operand size, where 8 bits is 16 bits
+	st := status(Operand8, MathFloat)
+	r := GeneralRegister(0x1FFFF)
+	r.SignedToFloat(&st)
+	assert.Equal(t, -1.0, r.Float(Operand8))
+	assert.True(t, st.IsNegative())
+
+	r = GeneralRegister(0x101FF)
+	r.UnsignedToFloat(&st)
+	assert.Equal(t, 511.0, r.Float(Operand8))
+	assert.False(t, st.IsNegative())
+
+	st = status(Operand16, MathFloat)
+	r = GeneralRegister(0xFFFF)
+	r.UnsignedToFloat(&st)
+	assert.True(t, math.IsInf(r.Float(Operand16), 1))
+
+	st = status(Operand64, MathFloat)
+	r = GeneralRegister(math.MaxUint64)
+	r.UnsignedToFloat(&st)
+	assert.Equal(t, 0x1p64, r.Float(Operand64))
+
+	// Truncation and saturation
+	for _, test := range []struct {
+		operandSize uint8
+		in          float64
+		signed      int64
+		unsigned    uint64
+	}{
+		{Operand8, -3.75, -3, 0},
+		{Operand8, 300, 300, 300},
+		{Operand8, 40000, math.MaxInt16, 40000},
+		{Operand8, -40000, math.MinInt16, 0},
+		{Operand16, 40000, math.MaxInt16, 40000},
+		{Operand16, math.NaN(), 0, 0},
+		{Operand32, 1e10, math.MaxInt32, math.MaxUint32},
+		{Operand32, -0.5, 0, 0},
+		{Operand64, 1e300, math.MaxInt64, math.MaxUint64},
+		{Operand64, -1e300, math.MinInt64, 0},
+		{Operand64, 0x1p63, math.MaxInt64, 0x8000000000000000},
+	} {
+		st = status(test.operandSize, MathFloat)
+		r = floatRegister(test.in, test.operandSize)
+		r.FloatToSigned(&st)
+		assert.Equal(t, test.signed, r.Int64(), "%d %g", test.operandSize, test.in)
+		assert.Equal(t, test.signed == 0, st.IsZero())
+		assert.Equal(t, test.signed < 0, st.IsNegative())
+
+		r = floatRegister(test.in, test.operandSize)
+		r.FloatToUnsigned(&st)
+		assert.Equal(t, r.Negative(), st.IsNegative())
+		assert.Equal(t, test.unsigned == 0, st.IsZero())
+
+		r.ZeroHigherBits(st)
+		assert.Equal(t, test.unsigned, r.Uint64(), "%d %g", test.operandSize, test.in)
+	}
+}
@@ -0,0 +1,182 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package register
+
+import (
+	"math/big"
+)
+
+// ErrFractionRange is returned by SetFraction if a reduced fraction does not fit in the operand size
+const ErrFractionRange = GeneralRegisterError("Fraction Out Of Range")
+
+// fractionBits returns the number of bits of a fraction of an operand size, where 8 bit operands use 16 bits.
+// The numerator is the signed upper half, and the denominator is the unsigned lower half.
+func fractionBits(operandSize uint8) uint {
+	return uint(8) << EffectiveOperandSize(operandSize, MathFractional)
+}
+
+// Fraction returns the numerator and denominator of r as a fraction of an operand size.
+// The parts are returned as is, the denominator may be 0 and the fraction may not be reduced.
+func (r GeneralRegister) Fraction(operandSize uint8) (int64, uint64) {
+	var (
+		bits = fractionBits(operandSize)
+		half = bits / 2
+		val  = uint64(r) << (64 - bits)
+	)
+
+	return int64(val) >> (64 - half), (val << half) >> (64 - half)
+}
+
+// SetFraction sets r to num / den as a fraction of an operand size, reduced to lowest terms with the sign in the
+// numerator. The result is sign extended.
+//
+// Returns ErrDivisionByZero if den = 0, or ErrFractionRange if the reduced fraction does not fit.
+// r is unchanged if an error is returned.
+func (r *GeneralRegister) SetFraction(num int64, den uint64, operandSize uint8) error {
+	if den == 0 {
+		return ErrDivisionByZero
+	}
+
+	q := new(big.Rat).SetFrac(big.NewInt(num), new(big.Int).SetUint64(den))
+	if !r.setFraction(q, operandSize) {
+		return ErrFractionRange
+	}
+
+	return nil
+}
+
+// ratio returns r as a fraction of an operand size.
+// Returns ErrDivisionByZero if the denominator is 0.
+func (r GeneralRegister) ratio(operandSize uint8) (*big.Rat, error) {
+	num, den := r.Fraction(operandSize)
+	if den == 0 {
+		return nil, ErrDivisionByZero
+	}
+
+	return new(big.Rat).SetFrac(big.NewInt(num), new(big.Int).SetUint64(den)), nil
+}
+
+// setFraction sets r to q as a fraction of an operand size, where big.Rat has already reduced q to lowest terms.
+// Returns false if q does not fit, in which case r is unchanged.
+func (r *GeneralRegister) setFraction(q *big.Rat, operandSize uint8) bool {
+	var (
+		bits   = fractionBits(operandSize)
+		half   = bits / 2
+		num    = q.Num()
+		den    = q.Denom()
+		maxNum = new(big.Int).Lsh(big.NewInt(1), half-1)
+		minNum = new(big.Int).Neg(maxNum)
+	)
+
+	if (num.Cmp(minNum) < 0) || (num.Cmp(maxNum) >= 0) || (den.BitLen() > int(half)) {
+		return false
+	}
+
+	val := uint64(num.Int64())<<half | den.Uint64()
+	switch bits {
+	case 16:
+		r.SetUint16(uint16(val))
+	case 32:
+		r.SetUint32(uint32(val))
+	default:
+		r.SetUint64(val)
+	}
+
+	return true
+}
+
+// fractionOp sets r = f(r, op) using fractional math, with the following side effects:
+// - Result is reduced to lowest terms, with the sign in the numerator, and sign extended
+// - Carry and Overflow are true if the reduced result does not fit, in which case r is unchanged
+// - Zero is true if the result is zero
+// - Negative is true if the result is negative
+//
+// Returns ErrDivisionByZero if r or op has a denominator of 0, in which case r and the flags are unchanged
+func (r *GeneralRegister) fractionOp(op GeneralRegister, st *StatusRegister, f func(z, x, y *big.Rat) *big.Rat) error {
+	size := st.OperandSize()
+
+	x, err := r.ratio(size)
+	if err != nil {
+		return err
+	}
+
+	y, err := op.ratio(size)
+	if err != nil {
+		return err
+	}
+
+	q := f(new(big.Rat), x, y)
+	fits := r.setFraction(q, size)
+
+	st.Carry(!fits)
+	st.Overflow(!fits)
+	st.Zero(q.Sign() == 0)
+	st.Negative(q.Sign() < 0)
+
+	return nil
+}
+
+// AddFraction sets r = r + op using fractional math. Carry is not added, since it means more bits are needed.
+// Side effects and errors are as described by fractionOp.
+func (r *GeneralRegister) AddFraction(op GeneralRegister, st *StatusRegister) error {
+	return r.fractionOp(op, st, (*big.Rat).Add)
+}
+
+// SubtractFraction sets r = r - op using fractional math. Carry is not subtracted, since it means more bits are needed.
+// Side effects and errors are as described by fractionOp.
+func (r *GeneralRegister) SubtractFraction(op GeneralRegister, st *StatusRegister) error {
+	return r.fractionOp(op, st, (*big.Rat).Sub)
+}
+
+// MultiplyFraction sets r = r * op using fractional math. Unlike integer math, op is unchanged.
+// Side effects and errors are as described by fractionOp.
+func (r *GeneralRegister) MultiplyFraction(op GeneralRegister, st *StatusRegister) error {
+	return r.fractionOp(op, st, (*big.Rat).Mul)
+}
+
+// DivideFraction sets r = r / op using fractional math. Unlike integer math, there is no remainder, op is unchanged.
+// Side effects and errors are as described by fractionOp.
+//
+// Returns ErrDivisionByZero if op = 0
+func (r *GeneralRegister) DivideFraction(op GeneralRegister, st *StatusRegister) error {
+	y, err := op.ratio(st.OperandSize())
+	if err != nil {
+		return err
+	}
+
+	if y.Sign() == 0 {
+		return ErrDivisionByZero
+	}
+
+	return r.fractionOp(op, st, (*big.Rat).Quo)
+}
+
+// CompareFraction compares r with op using fractional math, where fractions that are not reduced compare by value,
+// with the following side effects:
+// - Carry is true if r >= op
+// - Overflow is true if r >= op
+// - Zero is true if r == op
+//
+// Fractions are always signed, so Carry and Overflow are the same.
+//
+// Returns ErrDivisionByZero if r or op has a denominator of 0, in which case the flags are unchanged
+func (r GeneralRegister) CompareFraction(op GeneralRegister, st *StatusRegister) error {
+	size := st.OperandSize()
+
+	x, err := r.ratio(size)
+	if err != nil {
+		return err
+	}
+
+	y, err := op.ratio(size)
+	if err != nil {
+		return err
+	}
+
+	cmp := x.Cmp(y)
+	st.Carry(cmp >= 0)
+	st.Overflow(cmp >= 0)
+	st.Zero(cmp == 0)
+
+	return nil
+}
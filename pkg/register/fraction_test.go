@@ -0,0 +1,142 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package register
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fraction returns a register containing num / den as a fraction of an operand size, failing the test if it is invalid
+func fraction(t *testing.T, num int64, den uint64, operandSize uint8) GeneralRegister {
+	var r GeneralRegister
+	assert.Nil(t, r.SetFraction(num, den, operandSize))
+
+	return r
+}
+
+// assertFraction asserts that r is num / den as a fraction of an operand size
+func assertFraction(t *testing.T, num int64, den uint64, r GeneralRegister, operandSize uint8) {
+	n, d := r.Fraction(operandSize)
+	assert.Equal(t, num, n)
+	assert.Equal(t, den, d)
+}
+
+func TestGeneralRegisterSetFraction(t *testing.T) {
+	// 8 bit operands are 16 bits
+	r := fraction(t, -6, 8, Operand8)
+	assert.Equal(t, uint64(0xFFFFFFFFFFFFFD04), uint64(r))
+	assertFraction(t, -3, 4, r, Operand16)
+
+	r = fraction(t, 127, 255, Operand16)
+	assert.Equal(t, uint64(0x7FFF), uint64(r))
+
+	r = fraction(t, 0, 5, Operand32)
+	assertFraction(t, 0, 1, r, Operand32)
+
+	r = fraction(t, math.MinInt32, math.MaxUint32, Operand64)
+	assertFraction(t, math.MinInt32, math.MaxUint32, r, Operand64)
+
+	// Parts are returned as is
+	assertFraction(t, 2, 4, GeneralRegister(0x00020004), Operand32)
+	assertFraction(t, 0, 0, 0, Operand64)
+
+	// Errors leave r unchanged
+	assert.Equal(t, ErrDivisionByZero, r.SetFraction(1, 0, Operand16))
+	assert.Equal(t, ErrFractionRange, r.SetFraction(128, 1, Operand16))
+	assert.Equal(t, ErrFractionRange, r.SetFraction(1, 256, Operand16))
+	assert.Equal(t, ErrFractionRange, r.SetFraction(-32769, 2, Operand32))
+	assertFraction(t, math.MinInt32, math.MaxUint32, r, Operand64)
+
+	// Reduction brings a fraction into range
+	assert.Nil(t, r.SetFraction(256, 512, Operand16))
+	assertFraction(t, 1, 2, r, Operand16)
+}
+
+func TestGeneralRegisterFractionALU(t *testing.T) {
+	st := status(Operand32, MathFractional)
+	st.SetCarry()
+
+	// Carry is not added
+	r := fraction(t, 1, 6, Operand32)
+	assert.Nil(t, r.AddFraction(fraction(t, 1, 3, Operand32), &st))
+	assertFraction(t, 1, 2, r, Operand32)
+	assert.False(t, st.IsCarry())
+	assert.False(t, st.IsOverflow())
+	assert.False(t, st.IsZero())
+	assert.False(t, st.IsNegative())
+
+	assert.Nil(t, r.SubtractFraction(fraction(t, 3, 4, Operand32), &st))
+	assertFraction(t, -1, 4, r, Operand32)
+	assert.True(t, st.IsNegative())
+
+	assert.Nil(t, r.MultiplyFraction(fraction(t, -2, 3, Operand32), &st))
+	assertFraction(t, 1, 6, r, Operand32)
+	assert.False(t, st.IsNegative())
+
+	assert.Nil(t, r.DivideFraction(fraction(t, -1, 3, Operand32), &st))
+	assertFraction(t, -1, 2, r, Operand32)
+	assert.True(t, st.IsNegative())
+
+	assert.Nil(t, r.AddFraction(fraction(t, 1, 2, Operand32), &st))
+	assertFraction(t, 0, 1, r, Operand32)
+	assert.True(t, st.IsZero())
+
+	// Results that do not fit leave r unchanged
+	st = status(Operand8, MathFractional)
+	r = fraction(t, 1, 255, Operand8)
+	assert.Nil(t, r.MultiplyFraction(fraction(t, 1, 2, Operand8), &st))
+	assertFraction(t, 1, 255, r, Operand8)
+	assert.True(t, st.IsCarry())
+	assert.True(t, st.IsOverflow())
+	assert.False(t, st.IsZero())
+
+	r = fraction(t, -100, 1, Operand8)
+	assert.Nil(t, r.SubtractFraction(fraction(t, 29, 1, Operand8), &st))
+	assertFraction(t, -100, 1, r, Operand8)
+	assert.True(t, st.IsOverflow())
+	assert.True(t, st.IsNegative())
+
+	assert.Nil(t, r.SubtractFraction(fraction(t, 28, 1, Operand8), &st))
+	assertFraction(t, -128, 1, r, Operand8)
+	assert.False(t, st.IsOverflow())
+
+	// Zero denominators and division by zero are errors that leave r and the flags unchanged
+	st = status(Operand64, MathFractional)
+	st.SetZero()
+	r = fraction(t, 5, 7, Operand64)
+	assert.Equal(t, ErrDivisionByZero, r.DivideFraction(fraction(t, 0, 3, Operand64), &st))
+	assert.Equal(t, ErrDivisionByZero, r.AddFraction(0, &st))
+	assertFraction(t, 5, 7, r, Operand64)
+	assert.True(t, st.IsZero())
+
+	var invalid GeneralRegister
+	assert.Equal(t, ErrDivisionByZero, invalid.MultiplyFraction(r, &st))
+	assert.Equal(t, GeneralRegister(0), invalid)
+}
+
+func TestGeneralRegisterCompareFraction(t *testing.T) {
+	st := status(Operand16, MathFractional)
+	a := fraction(t, -1, 2, Operand16)
+	b := fraction(t, 1, 3, Operand16)
+
+	assert.Nil(t, a.CompareFraction(b, &st))
+	assert.False(t, st.IsCarry())
+	assert.False(t, st.IsOverflow())
+	assert.False(t, st.IsZero())
+
+	assert.Nil(t, b.CompareFraction(a, &st))
+	assert.True(t, st.IsCarry())
+	assert.True(t, st.IsOverflow())
+	assert.False(t, st.IsZero())
+
+	// Fractions that are not reduced compare by value
+	assert.Nil(t, b.CompareFraction(GeneralRegister(0x0206), &st))
+	assert.True(t, st.IsCarry())
+	assert.True(t, st.IsZero())
+
+	assert.Equal(t, ErrDivisionByZero, b.CompareFraction(GeneralRegister(0x0200), &st))
+	assert.True(t, st.IsZero())
+}
@@ -4,6 +4,7 @@ package register
 
 import (
 	"math/big"
+	"math/bits"
 )
 
 // GeneralRegisterError represents an error performing operations on general registers
@@ -172,10 +173,10 @@ func (r GeneralRegister) Negative() bool {
 	return (uint64(r) & SignBit64) == SignBit64
 }
 
-// ExtendSign extends the sign of the register, considering the current operand size
+// ExtendSign extends the sign of the register, considering the effective operand size
 func (r *GeneralRegister) ExtendSign(st StatusRegister) {
 	val := uint64(*r)
-	switch st.OperandSize() {
+	switch st.EffectiveOperandSize() {
 	case STOperand8:
 		if (val & SignBit8) == SignBit8 {
 			*r = GeneralRegister(val | SignExtend8)
@@ -201,9 +202,9 @@ func (r *GeneralRegister) ExtendSign(st StatusRegister) {
 	}
 }
 
-// ZeroHigherBits zeroes our bits higher than the current operand size
+// ZeroHigherBits zeroes our bits higher than the effective operand size
 func (r *GeneralRegister) ZeroHigherBits(st StatusRegister) {
-	switch st.OperandSize() {
+	switch st.EffectiveOperandSize() {
 	case STOperand8:
     	*r &= GeneralRegister(ZeroHigherBits8)
 
@@ -277,6 +278,13 @@ func (r *GeneralRegister) Compare(op GeneralRegister, st *StatusRegister) {
 	st.Zero(*r == op)
 }
 
+// Decrement sets r = r - 1 using integer math, regardless of the math mode and Carry.
+// Side effects are as described by SubtractInteger.
+func (r *GeneralRegister) Decrement(st *StatusRegister) {
+	st.ClearCarry()
+	r.SubtractInteger(1, st)
+}
+
 // DivideIntegerSigned sets r = r / op and op = r % op using signed integer math, with the following side effects:
 // - Zero is true if the result is zero
 // - Negative is true if the quotient is negative
@@ -306,6 +314,42 @@ func (r *GeneralRegister) DivideIntegerSigned(op *GeneralRegister, st *StatusReg
 	return nil
 }
 
+// DivideIntegerUnsigned sets r = r / op and op = r % op using unsigned integer math, with the following side effects:
+// - Only the lowest bits of the operand size of r and op are divided
+// - Quotient and remainder are sign extended
+// - Zero is true if the quotient is zero
+// - Negative is true if the highest bit of the quotient is set
+// - Overflow is true if the highest bit of the remainder is set
+//
+// Returns ErrDivisionByZero if op = 0
+func (r *GeneralRegister) DivideIntegerUnsigned(op *GeneralRegister, st *StatusRegister) error {
+	dividend, divisor := *r, *op
+	dividend.ZeroHigherBits(*st)
+	divisor.ZeroHigherBits(*st)
+
+	if divisor == 0 {
+		return ErrDivisionByZero
+	}
+
+	*r = dividend / divisor
+	*op = dividend % divisor
+	r.ExtendSign(*st)
+	op.ExtendSign(*st)
+
+	st.Zero(*r == 0)
+	st.Negative(r.Negative())
+	st.Overflow(op.Negative())
+
+	return nil
+}
+
+// Increment sets r = r + 1 using integer math, regardless of the math mode and Carry.
+// Side effects are as described by AddInteger.
+func (r *GeneralRegister) Increment(st *StatusRegister) {
+	st.ClearCarry()
+	r.AddInteger(1, st)
+}
+
 // MultiplyIntegerSigned sets r = r * op, with the following side effects:
 // - Sign is extended
 // - Zero is true if the result is zero
@@ -395,12 +439,56 @@ func (r *GeneralRegister) MultiplyIntegerSigned(op *GeneralRegister, st *StatusR
 	}
 }
 
-// Or r and op, with the following side effects:
+// MultiplyIntegerUnsigned sets r = r * op using unsigned integer math, with the following side effects:
+// - Only the lowest bits of the operand size of r and op are multiplied
+// - Product is twice as many bits as the operand size, and is not sign extended
+// - Zero is true if the product is zero
+// - Negative is true if the highest bit of the product is set
+//
+// If the operand size is 64 bits, r contains the lower 64 bits of the product,
+// and op contains the upper 64 bits. Otherwise, only r contains the complete product.
+func (r *GeneralRegister) MultiplyIntegerUnsigned(op *GeneralRegister, st *StatusRegister) {
+	a, b := *r, *op
+	a.ZeroHigherBits(*st)
+	b.ZeroHigherBits(*st)
+
+	if st.OperandSize() <= STOperand32 {
+		// The product fits in 64 bits, only r is affected
+		productBits := uint(16) << st.OperandSize()
+		*r = a * b
+
+		st.Zero(*r == 0)
+		st.Negative((*r>>(productBits-1))&1 == 1)
+		return
+	}
+
+	hi, lo := bits.Mul64(uint64(a), uint64(b))
+	*r = GeneralRegister(lo)
+	*op = GeneralRegister(hi)
+
+	st.Zero((hi == 0) && (lo == 0))
+	st.Negative(hi >= SignBit64)
+}
+
+// Negate sets r = -r using integer math, with the following side effects:
+// - Result is sign extended, where the most negative value of the operand size is unchanged
+// - Zero is true if result is zero
+// - Negative is true if result is negative
+func (r *GeneralRegister) Negate(st *StatusRegister) {
+	*r = -*r
+
+	r.ExtendSign(*st)
+
+	st.Zero(*r == 0)
+	st.Negative(r.Negative())
+}
+
+// Not sets r = ^r, with the following side effects:
 // - Result is sign extended
 // - Zero is true if result is zero
 // - Negative is true if result is negative
-func (r *GeneralRegister) Or(op GeneralRegister, st *StatusRegister) {
-	*r |= op
+func (r *GeneralRegister) Not(st *StatusRegister) {
+	*r = ^*r
 
 	r.ExtendSign(*st)
 
@@ -408,56 +496,177 @@ func (r *GeneralRegister) Or(op GeneralRegister, st *StatusRegister) {
 	st.Negative(r.Negative())
 }
 
-// Shift right arithmetic r and op, with the following side effects:
+// Or r and op, with the following side effects:
 // - Result is sign extended
 // - Zero is true if result is zero
 // - Negative is true if result is negative
-func (r *GeneralRegister) ShiftRightArithmetic(op GeneralRegister, st *StatusRegister) {
-    if (r.Negative()) {
-        // Go doesn't have arithmetic shift right, so manually shift, setting sign bit to 1 after each step
-        // As an optimization, if the number of shifts is >= 64, the result must be -1
-        numShift := uint64(op)
-        if (numShift >= 64) {
-            *r = GeneralRegister(MaxUint64) 
-        } else {
-            for ; numShift > 0; numShift-- {
-                *r >>= 1
-                *r |= GeneralRegister(SignBit64)
-            }
-        }
-    } else {
-        *r >>= op
-    }
+func (r *GeneralRegister) Or(op GeneralRegister, st *StatusRegister) {
+	*r |= op
+
+	r.ExtendSign(*st)
 
 	st.Zero(*r == 0)
 	st.Negative(r.Negative())
 }
 
-// Shift left r and op, with the following side effects:
+// operandBits returns the number of bits of the current operand size, and a mask of those bits
+func operandBits(st StatusRegister) (uint64, uint64) {
+	width := uint64(8) << st.OperandSize()
+	return width, MaxUint64 >> (64 - width)
+}
+
+// lowBitsSet returns true if any of the lowest n bits of val are set
+func lowBitsSet(val, n uint64) bool {
+	return (n > 0) && ((n >= 64) || (val&(MaxUint64>>(64-n)) != 0))
+}
+
+// setShifted sets r to a shifted or rotated value of the current operand size, with the following side effects:
 // - Result is sign extended
+// - Carry is true if carry is true
 // - Zero is true if result is zero
 // - Negative is true if result is negative
-func (r *GeneralRegister) ShiftLeft(op GeneralRegister, st *StatusRegister) {
-    *r <<= op
-    r.ExtendSign(*st)
+func (r *GeneralRegister) setShifted(val uint64, carry bool, st *StatusRegister) {
+	*r = GeneralRegister(val)
+	r.ExtendSign(*st)
 
+	st.Carry(carry)
 	st.Zero(*r == 0)
 	st.Negative(r.Negative())
 }
 
-// Shift right r and op, with the following side effects:
+// RotateLeft rotates the current operand size of r left by op bits, where op is reduced modulo the operand size.
+// Side effects are as described by setShifted, where Carry is the lowest bit of the result, which is the last bit
+// rotated, or false if op is 0.
+func (r *GeneralRegister) RotateLeft(op GeneralRegister, st *StatusRegister) {
+	var (
+		width, mask = operandBits(*st)
+		val         = uint64(*r) & mask
+		n           = uint64(op) % width
+	)
+
+	if n > 0 {
+		val = ((val << n) | (val >> (width - n))) & mask
+	}
+
+	r.setShifted(val, (op != 0) && (val&1 == 1), st)
+}
+
+// RotateRight rotates the current operand size of r right by op bits, where op is reduced modulo the operand size.
+// Side effects are as described by setShifted, where Carry is the highest bit of the result, which is the last bit
+// rotated, or false if op is 0.
+func (r *GeneralRegister) RotateRight(op GeneralRegister, st *StatusRegister) {
+	var (
+		width, mask = operandBits(*st)
+		val         = uint64(*r) & mask
+		n           = uint64(op) % width
+	)
+
+	if n > 0 {
+		val = ((val >> n) | (val << (width - n))) & mask
+	}
+
+	r.setShifted(val, (op != 0) && (val>>(width-1) == 1), st)
+}
+
+// RotateLeftThroughCarry rotates the current operand size of r and Carry left by op bits, as if Carry were an extra
+// highest bit, where op is reduced modulo the operand size + 1: each bit rotated out of r goes into Carry, and Carry
+// goes into the lowest bit of r. Side effects are as described by setShifted.
+func (r *GeneralRegister) RotateLeftThroughCarry(op GeneralRegister, st *StatusRegister) {
+	width, _ := operandBits(*st)
+	r.rotateThroughCarry(uint64(op)%(width+1), st)
+}
+
+// RotateRightThroughCarry rotates the current operand size of r and Carry right by op bits, as if Carry were an extra
+// lowest bit, where op is reduced modulo the operand size + 1: each bit rotated out of r goes into Carry, and Carry
+// goes into the highest bit of r. Side effects are as described by setShifted.
+func (r *GeneralRegister) RotateRightThroughCarry(op GeneralRegister, st *StatusRegister) {
+	width, _ := operandBits(*st)
+	r.rotateThroughCarry((width+1-uint64(op)%(width+1))%(width+1), st)
+}
+
+// rotateThroughCarry rotates the width + 1 bits of Carry and the current operand size of r left by n <= width bits
+func (r *GeneralRegister) rotateThroughCarry(n uint64, st *StatusRegister) {
+	var (
+		width, mask = operandBits(*st)
+		val         = uint64(*r) & mask
+		carry       = st.IsCarry()
+	)
+
+	if n > 0 {
+		var c uint64
+		if carry {
+			c = 1
+		}
+
+		// Go shifts of 64 or more bits are 0, so a rotation of width bits does not need special handling
+		carry = (val>>(width-n))&1 == 1
+		val = ((val << n) | (c << (n - 1)) | (val >> (width + 1 - n))) & mask
+	}
+
+	r.setShifted(val, carry, st)
+}
+
+// SetInteger sets r = val using integer math, regardless of the math mode, for the ZRO, ONE, and NG1 instructions,
+// with the following side effects:
 // - Result is sign extended
 // - Zero is true if result is zero
 // - Negative is true if result is negative
-func (r *GeneralRegister) ShiftRight(op GeneralRegister, st *StatusRegister) {
-    r.ZeroHigherBits(*st)
-    *r >>= op
-    r.ExtendSign(*st)
+func (r *GeneralRegister) SetInteger(val int64, st *StatusRegister) {
+	*r = GeneralRegister(val)
+
+	r.ExtendSign(*st)
 
 	st.Zero(*r == 0)
 	st.Negative(r.Negative())
 }
 
+// ShiftLeft shifts the current operand size of r left by op bits, where op >= the operand size results in 0.
+// Side effects are as described by setShifted, where Carry is true if any bit shifted out is set.
+func (r *GeneralRegister) ShiftLeft(op GeneralRegister, st *StatusRegister) {
+	var (
+		width, mask = operandBits(*st)
+		val         = uint64(*r) & mask
+		n           = uint64(op)
+		kept        uint64
+	)
+
+	// The bits shifted out are the highest n bits, or all bits if n >= width
+	if n < width {
+		kept = width - n
+	}
+
+	// Go shifts of 64 or more bits are 0
+	r.setShifted((val<<n)&mask, (n > 0) && (val>>kept != 0), st)
+}
+
+// ShiftRight shifts the current operand size of r right by op bits, filling with 0 bits, where op >= the operand size
+// results in 0. Side effects are as described by setShifted, where Carry is true if any bit shifted out is set.
+func (r *GeneralRegister) ShiftRight(op GeneralRegister, st *StatusRegister) {
+	var (
+		_, mask = operandBits(*st)
+		val     = uint64(*r) & mask
+		n       = uint64(op)
+	)
+
+	// Go shifts of 64 or more bits are 0
+	r.setShifted(val>>n, lowBitsSet(val, n), st)
+}
+
+// ShiftRightArithmetic shifts the current operand size of r right by op bits, filling with copies of the sign bit,
+// where op >= the operand size results in 0 or -1. Side effects are as described by setShifted, where Carry is true if
+// any bit shifted out is set.
+func (r *GeneralRegister) ShiftRightArithmetic(op GeneralRegister, st *StatusRegister) {
+	var (
+		_, mask = operandBits(*st)
+		val     = *r
+		n       = uint64(op)
+	)
+
+	// Go shifts signed values arithmetically, and shifts of 64 or more bits are 0 or -1
+	val.ExtendSign(*st)
+	r.setShifted(uint64(val.Int64()>>n), lowBitsSet(uint64(val)&mask, n), st)
+}
+
 // SubtractInteger sets r = r - op - Carry using integer math, with the following side effects:
 // - Result is sign extended
 // - Carry is true if unsigned result > original value
@@ -476,6 +476,88 @@ func TestGeneralRegisterDivideInteger(t *testing.T) {
 	assert.False(t, st.IsOverflow())
 }
 
+func TestGeneralRegisterDivideIntegerUnsigned(t *testing.T) {
+	var (
+		r0 = new(GeneralRegister)
+		r1 = new(GeneralRegister)
+		st = new(StatusRegister)
+	)
+
+	// 7 / 0 = division by zero, where higher bits are ignored
+	r0.SetUint8(0x07)
+	r1.SetUint64(0x0000000000000100)
+	st.SelectOperandSize(Operand8)
+	assert.Equal(t, ErrDivisionByZero, r0.DivideIntegerUnsigned(r1, st))
+	assert.Equal(t, uint64(0x0000000000000007), r0.Uint64())
+
+	// 0xF9 / 2 = 0x7C remainder 1, where 0xF9 is 249 not -7
+	r0.SetUint8(0xF9)
+	r1.SetUint8(0x02)
+	st.SelectOperandSize(Operand8)
+	st.SetZero()
+	st.SetNegative()
+	st.SetOverflow()
+	assert.Nil(t, r0.DivideIntegerUnsigned(r1, st))
+
+	assert.Equal(t, uint64(0x000000000000007C), r0.Uint64())
+	assert.Equal(t, uint64(0x0000000000000001), r1.Uint64())
+	assert.False(t, st.IsZero())
+	assert.False(t, st.IsNegative())
+	assert.False(t, st.IsOverflow())
+
+	// 0xFFFF / 0xFFFE = 1 remainder 1
+	r0.SetUint16(0xFFFF)
+	r1.SetUint16(0xFFFE)
+	st.SelectOperandSize(Operand16)
+	assert.Nil(t, r0.DivideIntegerUnsigned(r1, st))
+
+	assert.Equal(t, uint64(0x0000000000000001), r0.Uint64())
+	assert.Equal(t, uint64(0x0000000000000001), r1.Uint64())
+
+	// 0xFFFFFFFF / 0x10 = 0x0FFFFFFF remainder 0xF
+	r0.SetUint32(0xFFFFFFFF)
+	r1.SetUint32(0x10)
+	st.SelectOperandSize(Operand32)
+	assert.Nil(t, r0.DivideIntegerUnsigned(r1, st))
+
+	assert.Equal(t, uint64(0x000000000FFFFFFF), r0.Uint64())
+	assert.Equal(t, uint64(0x000000000000000F), r1.Uint64())
+
+	// 3 / 0x80000000 = 0 remainder 3
+	r0.SetUint32(0x03)
+	r1.SetUint32(0x80000000)
+	st.SelectOperandSize(Operand32)
+	assert.Nil(t, r0.DivideIntegerUnsigned(r1, st))
+
+	assert.Equal(t, uint64(0x0000000000000000), r0.Uint64())
+	assert.Equal(t, uint64(0x0000000000000003), r1.Uint64())
+	assert.True(t, st.IsZero())
+
+	// 0xFFFFFFFFFFFFFFFF / 1 = 0xFFFFFFFFFFFFFFFF remainder 0
+	r0.SetUint64(0xFFFFFFFFFFFFFFFF)
+	r1.SetUint64(0x01)
+	st.SelectOperandSize(Operand64)
+	assert.Nil(t, r0.DivideIntegerUnsigned(r1, st))
+
+	assert.Equal(t, uint64(0xFFFFFFFFFFFFFFFF), r0.Uint64())
+	assert.Equal(t, uint64(0x0000000000000000), r1.Uint64())
+	assert.False(t, st.IsZero())
+	assert.True(t, st.IsNegative())
+	assert.False(t, st.IsOverflow())
+
+	// 0xFFFFFFFFFFFFFFFE / 0xFFFFFFFFFFFFFFFF = 0 remainder 0xFFFFFFFFFFFFFFFE
+	r0.SetUint64(0xFFFFFFFFFFFFFFFE)
+	r1.SetUint64(0xFFFFFFFFFFFFFFFF)
+	st.SelectOperandSize(Operand64)
+	assert.Nil(t, r0.DivideIntegerUnsigned(r1, st))
+
+	assert.Equal(t, uint64(0x0000000000000000), r0.Uint64())
+	assert.Equal(t, uint64(0xFFFFFFFFFFFFFFFE), r1.Uint64())
+	assert.True(t, st.IsZero())
+	assert.False(t, st.IsNegative())
+	assert.True(t, st.IsOverflow())
+}
+
 func TestGeneralRegisterMultiplyInteger(t *testing.T) {
 	var (
 		r0 = new(GeneralRegister)
@@ -579,6 +661,92 @@ func TestGeneralRegisterMultiplyInteger(t *testing.T) {
 	assert.False(t, st.IsNegative())
 }
 
+func TestGeneralRegisterMultiplyIntegerUnsigned(t *testing.T) {
+	var (
+		r0 = new(GeneralRegister)
+		r1 = new(GeneralRegister)
+		st = new(StatusRegister)
+	)
+
+	// 7 * 0 = 0
+	r0.SetUint8(0x07)
+	r1.SetUint8(0x00)
+	st.SelectOperandSize(Operand8)
+	st.ClearZero()
+	st.SetNegative()
+	r0.MultiplyIntegerUnsigned(r1, st)
+
+	assert.Equal(t, uint64(0x0000000000000000), r0.Uint64())
+	assert.True(t, st.IsZero())
+	assert.False(t, st.IsNegative())
+
+	// 0xFF * 0xFF = 0xFE01, where 0xFF is 255 not -1
+	r0.SetUint8(0xFF)
+	r1.SetUint8(0xFF)
+	st.SelectOperandSize(Operand8)
+	r0.MultiplyIntegerUnsigned(r1, st)
+
+	assert.Equal(t, uint64(0x000000000000FE01), r0.Uint64())
+	assert.Equal(t, uint64(0xFFFFFFFFFFFFFFFF), r1.Uint64())
+	assert.False(t, st.IsZero())
+	assert.True(t, st.IsNegative())
+
+	// 0x7F * 0x02 = 0xFE
+	r0.SetUint8(0x7F)
+	r1.SetUint8(0x02)
+	st.SelectOperandSize(Operand8)
+	r0.MultiplyIntegerUnsigned(r1, st)
+
+	assert.Equal(t, uint64(0x00000000000000FE), r0.Uint64())
+	assert.False(t, st.IsNegative())
+
+	// 0xFFFF * 0x0002 = 0x0001FFFE
+	r0.SetUint16(0xFFFF)
+	r1.SetUint16(0x0002)
+	st.SelectOperandSize(Operand16)
+	r0.MultiplyIntegerUnsigned(r1, st)
+
+	assert.Equal(t, uint64(0x000000000001FFFE), r0.Uint64())
+	assert.False(t, st.IsNegative())
+
+	// 0xFFFFFFFF * 0xFFFFFFFF = 0xFFFFFFFE00000001
+	r0.SetUint32(0xFFFFFFFF)
+	r1.SetUint32(0xFFFFFFFF)
+	st.SelectOperandSize(Operand32)
+	r0.MultiplyIntegerUnsigned(r1, st)
+
+	assert.Equal(t, uint64(0xFFFFFFFE00000001), r0.Uint64())
+	assert.True(t, st.IsNegative())
+
+	// 0xFFFFFFFFFFFFFFFF * 0xFFFFFFFFFFFFFFFF = 0xFFFFFFFFFFFFFFFE_0000000000000001
+	r0.SetUint64(0xFFFFFFFFFFFFFFFF)
+	r1.SetUint64(0xFFFFFFFFFFFFFFFF)
+	st.SelectOperandSize(Operand64)
+	r0.MultiplyIntegerUnsigned(r1, st)
+
+	assert.Equal(t, uint64(0x0000000000000001), r0.Uint64())
+	assert.Equal(t, uint64(0xFFFFFFFFFFFFFFFE), r1.Uint64())
+	assert.False(t, st.IsZero())
+	assert.True(t, st.IsNegative())
+
+	// 0x8000000000000000 * 2 = 0x0000000000000001_0000000000000000
+	r0.SetUint64(0x8000000000000000)
+	r1.SetUint64(0x02)
+	st.SelectOperandSize(Operand64)
+	r0.MultiplyIntegerUnsigned(r1, st)
+
+	assert.Equal(t, uint64(0x0000000000000000), r0.Uint64())
+	assert.Equal(t, uint64(0x0000000000000001), r1.Uint64())
+	assert.False(t, st.IsZero())
+	assert.False(t, st.IsNegative())
+
+	// 0x100000000 * 0x100000000 = 0x0000000000000001_0000000000000000 without an allocation
+	r0.SetUint64(0x100000000)
+	r1.SetUint64(0x100000000)
+	st.SelectOperandSize(Operand64)
+	assert.Equal(t, 0.0, testing.AllocsPerRun(10, func() { r0.MultiplyIntegerUnsigned(r1, st) }))
+}
+
 func TestGeneralRegisterOr(t *testing.T) {
 	var (
 		st = new(StatusRegister)
@@ -906,3 +1074,205 @@ func TestGeneralRegisterXor(t *testing.T) {
 	assert.False(t, st.IsZero())
 	assert.False(t, st.IsNegative())
 }
+
+func TestGeneralRegisterShiftCarry(t *testing.T) {
+	for _, test := range []struct {
+		name        string
+		shift       func(*GeneralRegister, GeneralRegister, *StatusRegister)
+		operandSize uint8
+		in          uint64
+		count       uint64
+		out         uint64
+		carry       bool
+	}{
+		{"SHL", (*GeneralRegister).ShiftLeft, Operand8, 0x85, 1, 0x0A, true},
+		{"SHL", (*GeneralRegister).ShiftLeft, Operand8, 0x45, 1, 0xFFFFFFFFFFFFFF8A, false},
+		{"SHL", (*GeneralRegister).ShiftLeft, Operand8, 0x01, 8, 0x00, true},
+		{"SHL", (*GeneralRegister).ShiftLeft, Operand16, 0x8001, 0, 0xFFFFFFFFFFFF8001, false},
+		{"SHL", (*GeneralRegister).ShiftLeft, Operand32, 0x00010000, 16, 0x00000000, true},
+		{"SHL", (*GeneralRegister).ShiftLeft, Operand64, 1, 64, 0, true},
+		{"SHL", (*GeneralRegister).ShiftLeft, Operand64, 0, 1000, 0, false},
+		{"SHR", (*GeneralRegister).ShiftRight, Operand8, 0x85, 1, 0x42, true},
+		{"SHR", (*GeneralRegister).ShiftRight, Operand8, 0x84, 2, 0x21, false},
+		{"SHR", (*GeneralRegister).ShiftRight, Operand16, 0xFFFF8000, 15, 0x01, false},
+		{"SHR", (*GeneralRegister).ShiftRight, Operand16, 0x8000, 16, 0x00, true},
+		{"SHR", (*GeneralRegister).ShiftRight, Operand64, 0x8000000000000000, 63, 0x01, false},
+		{"SHA", (*GeneralRegister).ShiftRightArithmetic, Operand8, 0x85, 1, 0xFFFFFFFFFFFFFFC2, true},
+		{"SHA", (*GeneralRegister).ShiftRightArithmetic, Operand16, 0x8000, 15, 0xFFFFFFFFFFFFFFFF, false},
+		{"SHA", (*GeneralRegister).ShiftRightArithmetic, Operand16, 0x8000, 16, 0xFFFFFFFFFFFFFFFF, true},
+		{"SHA", (*GeneralRegister).ShiftRightArithmetic, Operand32, 0x7FFFFFFF, 40, 0x00, true},
+		{"SHA", (*GeneralRegister).ShiftRightArithmetic, Operand64, 0x10, 4, 0x01, false},
+	} {
+		st := new(StatusRegister)
+		st.SelectOperandSize(test.operandSize)
+
+		r := GeneralRegister(test.in)
+		test.shift(&r, GeneralRegister(test.count), st)
+
+		assert.Equal(t, test.out, r.Uint64(), "%s %X %d", test.name, test.in, test.count)
+		assert.Equal(t, test.carry, st.IsCarry(), "%s %X %d", test.name, test.in, test.count)
+		assert.Equal(t, test.out == 0, st.IsZero())
+		assert.Equal(t, r.Negative(), st.IsNegative())
+	}
+}
+
+func TestGeneralRegisterRotate(t *testing.T) {
+	st := new(StatusRegister)
+
+	// 85 rol 1 = 1000 0101 rol 1 = 0000 1011 = 0B, the last bit rotated is the lowest bit
+	r := GeneralRegister(0x85)
+	r.RotateLeft(1, st)
+	assert.Equal(t, uint64(0x0B), r.Uint64())
+	assert.True(t, st.IsCarry())
+	assert.False(t, st.IsNegative())
+
+	// 85 ror 1 = 1000 0101 ror 1 = 1100 0010 = C2, the last bit rotated is the highest bit
+	r = GeneralRegister(0x85)
+	r.RotateRight(1, st)
+	assert.Equal(t, uint64(0xFFFFFFFFFFFFFFC2), r.Uint64())
+	assert.True(t, st.IsCarry())
+	assert.True(t, st.IsNegative())
+
+	// Counts are modulo the operand size, and a count of 0 clears Carry
+	r = GeneralRegister(0x85)
+	r.RotateLeft(9, st)
+	assert.Equal(t, uint64(0x0B), r.Uint64())
+
+	r.RotateRight(0, st)
+	assert.Equal(t, uint64(0x0B), r.Uint64())
+	assert.False(t, st.IsCarry())
+
+	r.RotateRight(8, st)
+	assert.Equal(t, uint64(0x0B), r.Uint64())
+	assert.False(t, st.IsCarry())
+
+	st.SelectOperandSize(Operand64)
+	r = GeneralRegister(0x8000000000000001)
+	r.RotateLeft(4, st)
+	assert.Equal(t, uint64(0x18), r.Uint64())
+	assert.False(t, st.IsCarry())
+
+	r.RotateRight(68, st)
+	assert.Equal(t, uint64(0x8000000000000001), r.Uint64())
+	assert.True(t, st.IsCarry())
+	assert.False(t, st.IsZero())
+}
+
+func TestGeneralRegisterRotateThroughCarry(t *testing.T) {
+	st := new(StatusRegister)
+
+	// C:85 rcl 1 = 0:1000 0101 rcl 1 = 1:0000 1010
+	r := GeneralRegister(0x85)
+	r.RotateLeftThroughCarry(1, st)
+	assert.Equal(t, uint64(0x0A), r.Uint64())
+	assert.True(t, st.IsCarry())
+
+	// 1:0000 1010 rcl 2 = 0:0010 1010
+	r.RotateLeftThroughCarry(2, st)
+	assert.Equal(t, uint64(0x2A), r.Uint64())
+	assert.False(t, st.IsCarry())
+
+	// 0:0010 1010 rcr 2 = 1:0000 1010
+	r.RotateRightThroughCarry(2, st)
+	assert.Equal(t, uint64(0x0A), r.Uint64())
+	assert.True(t, st.IsCarry())
+
+	// 1:0000 1010 rcr 1 = 0:1000 0101
+	r.RotateRightThroughCarry(1, st)
+	assert.Equal(t, uint64(0xFFFFFFFFFFFFFF85), r.Uint64())
+	assert.False(t, st.IsCarry())
+	assert.True(t, st.IsNegative())
+
+	// Counts are modulo the operand size + 1, so rotating 9 bits is unchanged
+	r.RotateLeftThroughCarry(9, st)
+	assert.Equal(t, uint64(0xFFFFFFFFFFFFFF85), r.Uint64())
+	assert.False(t, st.IsCarry())
+
+	// Rotating 8 bits swaps Carry into the lowest bit, and the rest rotate right by 1
+	r.RotateLeftThroughCarry(8, st)
+	assert.Equal(t, uint64(0x42), r.Uint64())
+	assert.True(t, st.IsCarry())
+
+	// Multi word shifts carry between the words, EG a 128 bit shift left by 1
+	st.SelectOperandSize(Operand64)
+	st.ClearCarry()
+
+	lo, hi := GeneralRegister(0x8000000000000000), GeneralRegister(0x1)
+	lo.RotateLeftThroughCarry(1, st)
+	hi.RotateLeftThroughCarry(1, st)
+	assert.Equal(t, uint64(0), lo.Uint64())
+	assert.Equal(t, uint64(0x3), hi.Uint64())
+	assert.False(t, st.IsCarry())
+
+	hi.RotateRightThroughCarry(1, st)
+	lo.RotateRightThroughCarry(1, st)
+	assert.Equal(t, uint64(0x1), hi.Uint64())
+	assert.Equal(t, uint64(0x8000000000000000), lo.Uint64())
+	assert.False(t, st.IsCarry())
+}
+
+func TestGeneralRegisterUnary(t *testing.T) {
+	st := new(StatusRegister)
+	st.SetCarry()
+
+	// Carry is not added or subtracted
+	r := GeneralRegister(0x7F)
+	r.Increment(st)
+	assert.Equal(t, uint64(0xFFFFFFFFFFFFFF80), r.Uint64())
+	assert.False(t, st.IsCarry())
+	assert.True(t, st.IsOverflow())
+	assert.True(t, st.IsNegative())
+
+	st.SetCarry()
+	r.Decrement(st)
+	assert.Equal(t, uint64(0x7F), r.Uint64())
+	assert.False(t, st.IsCarry())
+	assert.True(t, st.IsOverflow())
+	assert.False(t, st.IsNegative())
+
+	r = 0
+	r.Decrement(st)
+	assert.Equal(t, uint64(0xFFFFFFFFFFFFFFFF), r.Uint64())
+	assert.True(t, st.IsCarry())
+	assert.False(t, st.IsOverflow())
+
+	r.Increment(st)
+	assert.Equal(t, uint64(0), r.Uint64())
+	assert.True(t, st.IsCarry())
+	assert.True(t, st.IsZero())
+
+	// The most negative value cannot be negated
+	r = GeneralRegister(0x05)
+	r.Negate(st)
+	assert.Equal(t, uint64(0xFFFFFFFFFFFFFFFB), r.Uint64())
+	assert.True(t, st.IsNegative())
+
+	r = GeneralRegister(0x80)
+	r.Negate(st)
+	assert.Equal(t, uint64(0xFFFFFFFFFFFFFF80), r.Uint64())
+	assert.True(t, st.IsNegative())
+
+	r = GeneralRegister(0x0F)
+	r.Not(st)
+	assert.Equal(t, uint64(0xFFFFFFFFFFFFFFF0), r.Uint64())
+	assert.True(t, st.IsNegative())
+
+	st.SelectOperandSize(Operand16)
+	r.Not(st)
+	assert.Equal(t, uint64(0x0F), r.Uint64())
+	assert.False(t, st.IsZero())
+	assert.False(t, st.IsNegative())
+
+	r.SetInteger(-1, st)
+	assert.Equal(t, uint64(0xFFFFFFFFFFFFFFFF), r.Uint64())
+	assert.False(t, st.IsZero())
+	assert.True(t, st.IsNegative())
+
+	r.SetInteger(1, st)
+	assert.Equal(t, uint64(1), r.Uint64())
+	assert.False(t, st.IsNegative())
+
+	r.SetInteger(0, st)
+	assert.Equal(t, uint64(0), r.Uint64())
+	assert.True(t, st.IsZero())
+}
@@ -0,0 +1,428 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package register
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/bantling/gofuncs"
+)
+
+const (
+	// GeneralRegisterErr if a general register is invalid
+	GeneralRegisterErr = "General register must be <= 3"
+
+	// RegisterSetErr if a pointer or counter register set is invalid
+	RegisterSetErr = "Register set must be <= 1"
+
+	// TimerRegisterErr if a timer register is invalid
+	TimerRegisterErr = "Timer register must be <= 3"
+)
+
+// Registers contains the processor registers.
+//
+// The general registers R0 thru R3 are the instruction set registers R0, R0c, R1, R1c, where R0 and R1 are a
+// complementary pair, and R2 and R3 are a complementary pair. ST.Register() selects one of them.
+//
+// The pointer registers DP, PTR, OFS, IX, and IS come in two sets, selected by ST.IsPointerRegisterSet0().
+// The counter registers CTR and CS come in two sets, selected by ST.IsCounterRegisterSet0().
+// Instructions that name a register of a specific set use the indexed accessors, EG DP(1).
+type Registers struct {
+	// General Purpose
+	R0 uint64
+	R1 uint64
+	R2 uint64
+	R3 uint64
+
+	// Data pointer
+	DP0 uint32
+	DP1 uint32
+
+	// Ptr
+	PTR0 uint32
+	PTR1 uint32
+
+	// Offset
+	OFS0 uint16
+	OFS1 uint16
+
+	// Index
+	IX0 uint16
+	IX1 uint16
+
+	// Index Step
+	IS0 uint16
+	IS1 uint16
+
+	// Counter
+	CTR0 uint32
+	CTR1 uint32
+
+	// Counter Step
+	CS0 uint16
+	CS1 uint16
+
+	// Timer, which counts down milliseconds to 0
+	TMR0 uint32
+	TMR1 uint32
+	TMR2 uint32
+	TMR3 uint32
+
+	// Timer pointer, the absolute address of the routine executed when the timer reaches 0, which becomes its CP
+	TPTR0 uint32
+	TPTR1 uint32
+	TPTR2 uint32
+	TPTR3 uint32
+
+	// Code pointer
+	CP uint32
+
+	// Program counter
+	PC uint32
+
+	// Status
+	st StatusRegister
+
+	// Stack base
+	SB uint32
+
+	// Stack limit, the highest offset from SB that the stack uses
+	SL uint16
+
+	// Stack pointer
+	SP uint16
+}
+
+// OfRegisters creates a new Registers where all registers = 0 except:
+// SB = DefaultSB
+// SL = DefaultSL
+// SP = DefaultSP
+func OfRegisters() Registers {
+	var reg Registers
+	reg.SB = DefaultSB
+	reg.SL = DefaultSL
+	reg.SP = DefaultSP
+
+	return reg
+}
+
+// ST returns current status register value
+func (r Registers) ST() StatusRegister {
+	return r.st
+}
+
+// SetST sets the status register value
+func (r *Registers) SetST(st StatusRegister) {
+	r.st = st
+}
+
+// fields returns pointers to every register in declaration order, including ST
+func (r *Registers) fields() []interface{} {
+	return []interface{}{
+		&r.R0, &r.R1, &r.R2, &r.R3,
+		&r.DP0, &r.DP1,
+		&r.PTR0, &r.PTR1,
+		&r.OFS0, &r.OFS1,
+		&r.IX0, &r.IX1,
+		&r.IS0, &r.IS1,
+		&r.CTR0, &r.CTR1,
+		&r.CS0, &r.CS1,
+		&r.TMR0, &r.TMR1, &r.TMR2, &r.TMR3,
+		&r.TPTR0, &r.TPTR1, &r.TPTR2, &r.TPTR3,
+		&r.CP,
+		&r.PC,
+		&r.st,
+		&r.SB,
+		&r.SL,
+		&r.SP,
+	}
+}
+
+// RegistersSize is the number of bytes encoded by MarshalBinary
+var RegistersSize = func() int {
+	var (
+		regs Registers
+		size int
+	)
+
+	for _, f := range regs.fields() {
+		size += binary.Size(f)
+	}
+
+	return size
+}()
+
+// MarshalBinary encodes every register including ST in declaration order, big endian
+func (r Registers) MarshalBinary() ([]byte, error) {
+	buf := bytes.NewBuffer(make([]byte, 0, RegistersSize))
+	for _, f := range r.fields() {
+		binary.Write(buf, binary.BigEndian, f)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary decodes registers encoded by MarshalBinary.
+// Returns an error and leaves the registers unchanged if data is not RegistersSize bytes.
+func (r *Registers) UnmarshalBinary(data []byte) error {
+	if len(data) != RegistersSize {
+		return fmt.Errorf("registers must be %d bytes, not %d", RegistersSize, len(data))
+	}
+
+	var (
+		regs Registers
+		rd   = bytes.NewReader(data)
+	)
+
+	for _, f := range regs.fields() {
+		binary.Read(rd, binary.BigEndian, f)
+	}
+
+	*r = regs
+	return nil
+}
+
+// General returns general register R0 thru R3.
+// Panics if n > 3.
+func (r *Registers) General(n uint8) *GeneralRegister {
+	gofuncs.PanicBM(n <= RegisterR3, GeneralRegisterErr)
+
+	switch n {
+	case RegisterR0:
+		return (*GeneralRegister)(&r.R0)
+	case RegisterR1:
+		return (*GeneralRegister)(&r.R1)
+	case RegisterR2:
+		return (*GeneralRegister)(&r.R2)
+	}
+
+	return (*GeneralRegister)(&r.R3)
+}
+
+// Complement returns the complement of general register R0 thru R3: R0 and R1 are complements, as are R2 and R3.
+// Panics if n > 3.
+func (r *Registers) Complement(n uint8) *GeneralRegister {
+	gofuncs.PanicBM(n <= RegisterR3, GeneralRegisterErr)
+
+	return r.General(n ^ 1)
+}
+
+// SelectedGeneral returns the general register selected by ST
+func (r *Registers) SelectedGeneral() *GeneralRegister {
+	return r.General(r.st.Register())
+}
+
+// SelectedComplement returns the complement of the general register selected by ST
+func (r *Registers) SelectedComplement() *GeneralRegister {
+	return r.Complement(r.st.Register())
+}
+
+// PointerSet returns the pointer register set selected by ST, 0 or 1
+func (r Registers) PointerSet() uint8 {
+	if r.st.IsPointerRegisterSet0() {
+		return 0
+	}
+
+	return 1
+}
+
+// CounterSet returns the counter register set selected by ST, 0 or 1
+func (r Registers) CounterSet() uint8 {
+	if r.st.IsCounterRegisterSet0() {
+		return 0
+	}
+
+	return 1
+}
+
+// DP returns DP0 or DP1.
+// Panics if set > 1.
+func (r *Registers) DP(set uint8) *uint32 {
+	gofuncs.PanicBM(set <= 1, RegisterSetErr)
+
+	if set == 0 {
+		return &r.DP0
+	}
+
+	return &r.DP1
+}
+
+// PTR returns PTR0 or PTR1.
+// Panics if set > 1.
+func (r *Registers) PTR(set uint8) *uint32 {
+	gofuncs.PanicBM(set <= 1, RegisterSetErr)
+
+	if set == 0 {
+		return &r.PTR0
+	}
+
+	return &r.PTR1
+}
+
+// OFS returns OFS0 or OFS1.
+// Panics if set > 1.
+func (r *Registers) OFS(set uint8) *uint16 {
+	gofuncs.PanicBM(set <= 1, RegisterSetErr)
+
+	if set == 0 {
+		return &r.OFS0
+	}
+
+	return &r.OFS1
+}
+
+// IX returns IX0 or IX1.
+// Panics if set > 1.
+func (r *Registers) IX(set uint8) *uint16 {
+	gofuncs.PanicBM(set <= 1, RegisterSetErr)
+
+	if set == 0 {
+		return &r.IX0
+	}
+
+	return &r.IX1
+}
+
+// IS returns IS0 or IS1.
+// Panics if set > 1.
+func (r *Registers) IS(set uint8) *uint16 {
+	gofuncs.PanicBM(set <= 1, RegisterSetErr)
+
+	if set == 0 {
+		return &r.IS0
+	}
+
+	return &r.IS1
+}
+
+// CTR returns CTR0 or CTR1.
+// Panics if set > 1.
+func (r *Registers) CTR(set uint8) *uint32 {
+	gofuncs.PanicBM(set <= 1, RegisterSetErr)
+
+	if set == 0 {
+		return &r.CTR0
+	}
+
+	return &r.CTR1
+}
+
+// CS returns CS0 or CS1.
+// Panics if set > 1.
+func (r *Registers) CS(set uint8) *uint16 {
+	gofuncs.PanicBM(set <= 1, RegisterSetErr)
+
+	if set == 0 {
+		return &r.CS0
+	}
+
+	return &r.CS1
+}
+
+// SelectedDP returns the DP register of the pointer register set selected by ST
+func (r *Registers) SelectedDP() *uint32 {
+	return r.DP(r.PointerSet())
+}
+
+// SelectedPTR returns the PTR register of the pointer register set selected by ST
+func (r *Registers) SelectedPTR() *uint32 {
+	return r.PTR(r.PointerSet())
+}
+
+// SelectedOFS returns the OFS register of the pointer register set selected by ST
+func (r *Registers) SelectedOFS() *uint16 {
+	return r.OFS(r.PointerSet())
+}
+
+// SelectedIX returns the IX register of the pointer register set selected by ST
+func (r *Registers) SelectedIX() *uint16 {
+	return r.IX(r.PointerSet())
+}
+
+// SelectedIS returns the IS register of the pointer register set selected by ST
+func (r *Registers) SelectedIS() *uint16 {
+	return r.IS(r.PointerSet())
+}
+
+// SelectedCTR returns the CTR register of the counter register set selected by ST
+func (r *Registers) SelectedCTR() *uint32 {
+	return r.CTR(r.CounterSet())
+}
+
+// SelectedCS returns the CS register of the counter register set selected by ST
+func (r *Registers) SelectedCS() *uint16 {
+	return r.CS(r.CounterSet())
+}
+
+// StepCounter adds the signed step CS to CTR of a counter register set, with the following side effects:
+// - Zero is true if CTR becomes zero
+// - Negative is true if bit 31 of CTR is set, EG when a counter stepping down crosses zero
+// Panics if set > 1.
+func (r *Registers) StepCounter(set uint8) {
+	ctr := r.CTR(set)
+	*ctr += uint32(int32(int16(*r.CS(set))))
+
+	r.st.Zero(*ctr == 0)
+	r.st.Negative(int32(*ctr) < 0)
+}
+
+// DecrementCounter subtracts 1 from CTR of a counter register set, with side effects as described by StepCounter.
+// Returns true if CTR is not zero, which is when LOOP branches.
+// Panics if set > 1.
+func (r *Registers) DecrementCounter(set uint8) bool {
+	ctr := r.CTR(set)
+	*ctr--
+
+	r.st.Zero(*ctr == 0)
+	r.st.Negative(int32(*ctr) < 0)
+
+	return *ctr != 0
+}
+
+// StepIndex adds the signed step IS to IX of a pointer register set, with the following side effects:
+// - Zero is true if IX becomes zero
+// - Negative is true if bit 15 of IX is set, EG when an index stepping down crosses zero
+// Panics if set > 1.
+func (r *Registers) StepIndex(set uint8) {
+	ix := r.IX(set)
+	*ix += *r.IS(set)
+
+	r.st.Zero(*ix == 0)
+	r.st.Negative(int16(*ix) < 0)
+}
+
+// TMR returns timer register TMR0 thru TMR3.
+// Panics if n > 3.
+func (r *Registers) TMR(n uint8) *uint32 {
+	gofuncs.PanicBM(n <= 3, TimerRegisterErr)
+
+	switch n {
+	case 0:
+		return &r.TMR0
+	case 1:
+		return &r.TMR1
+	case 2:
+		return &r.TMR2
+	}
+
+	return &r.TMR3
+}
+
+// TPTR returns timer pointer register TPTR0 thru TPTR3.
+// Panics if n > 3.
+func (r *Registers) TPTR(n uint8) *uint32 {
+	gofuncs.PanicBM(n <= 3, TimerRegisterErr)
+
+	switch n {
+	case 0:
+		return &r.TPTR0
+	case 1:
+		return &r.TPTR1
+	case 2:
+		return &r.TPTR2
+	}
+
+	return &r.TPTR3
+}
@@ -0,0 +1,179 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package register
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistersOf(t *testing.T) {
+	regs := OfRegisters()
+	assert.Equal(t, DefaultSB, regs.SB)
+	assert.Equal(t, DefaultSL, regs.SL)
+	assert.Equal(t, DefaultSP, regs.SP)
+	assert.Equal(t, StatusRegister(0), regs.ST())
+
+	regs.SetST(StatusRegister(STCarrySet))
+	assert.True(t, regs.ST().IsCarry())
+}
+
+func TestRegistersGeneral(t *testing.T) {
+	regs := OfRegisters()
+	regs.R0, regs.R1, regs.R2, regs.R3 = 10, 11, 12, 13
+
+	for n := uint8(0); n <= RegisterR3; n++ {
+		assert.Equal(t, GeneralRegister(10+uint64(n)), *regs.General(n))
+		assert.Equal(t, GeneralRegister(10+uint64(n^1)), *regs.Complement(n))
+	}
+
+	regs.General(RegisterR2).SetUint64(22)
+	assert.Equal(t, uint64(22), regs.R2)
+
+	// ST selects the register
+	assert.Equal(t, &regs.R0, (*uint64)(regs.SelectedGeneral()))
+	assert.Equal(t, &regs.R1, (*uint64)(regs.SelectedComplement()))
+
+	st := regs.ST()
+	st.SelectRegister(RegisterR3)
+	regs.SetST(st)
+	assert.Equal(t, &regs.R3, (*uint64)(regs.SelectedGeneral()))
+	assert.Equal(t, &regs.R2, (*uint64)(regs.SelectedComplement()))
+
+	assert.PanicsWithValue(t, GeneralRegisterErr, func() { regs.General(4) })
+	assert.PanicsWithValue(t, GeneralRegisterErr, func() { regs.Complement(4) })
+}
+
+func TestRegistersSets(t *testing.T) {
+	regs := OfRegisters()
+
+	assert.Equal(t, &regs.DP0, regs.DP(0))
+	assert.Equal(t, &regs.DP1, regs.DP(1))
+	assert.Equal(t, &regs.PTR0, regs.PTR(0))
+	assert.Equal(t, &regs.PTR1, regs.PTR(1))
+	assert.Equal(t, &regs.OFS0, regs.OFS(0))
+	assert.Equal(t, &regs.OFS1, regs.OFS(1))
+	assert.Equal(t, &regs.IX0, regs.IX(0))
+	assert.Equal(t, &regs.IX1, regs.IX(1))
+	assert.Equal(t, &regs.IS0, regs.IS(0))
+	assert.Equal(t, &regs.IS1, regs.IS(1))
+	assert.Equal(t, &regs.CTR0, regs.CTR(0))
+	assert.Equal(t, &regs.CTR1, regs.CTR(1))
+	assert.Equal(t, &regs.CS0, regs.CS(0))
+	assert.Equal(t, &regs.CS1, regs.CS(1))
+
+	// Set 0 is selected by default
+	assert.Equal(t, uint8(0), regs.PointerSet())
+	assert.Equal(t, uint8(0), regs.CounterSet())
+	assert.Equal(t, &regs.DP0, regs.SelectedDP())
+	assert.Equal(t, &regs.CTR0, regs.SelectedCTR())
+
+	// Pointer and counter sets are selected independently
+	st := regs.ST()
+	st.SelectPointerRegisterSet1()
+	regs.SetST(st)
+	assert.Equal(t, uint8(1), regs.PointerSet())
+	assert.Equal(t, uint8(0), regs.CounterSet())
+	assert.Equal(t, &regs.DP1, regs.SelectedDP())
+	assert.Equal(t, &regs.PTR1, regs.SelectedPTR())
+	assert.Equal(t, &regs.OFS1, regs.SelectedOFS())
+	assert.Equal(t, &regs.IX1, regs.SelectedIX())
+	assert.Equal(t, &regs.IS1, regs.SelectedIS())
+	assert.Equal(t, &regs.CTR0, regs.SelectedCTR())
+	assert.Equal(t, &regs.CS0, regs.SelectedCS())
+
+	st.SelectPointerRegisterSet0()
+	st.SelectCounterRegisterSet1()
+	regs.SetST(st)
+	assert.Equal(t, &regs.PTR0, regs.SelectedPTR())
+	assert.Equal(t, &regs.CTR1, regs.SelectedCTR())
+	assert.Equal(t, &regs.CS1, regs.SelectedCS())
+
+	for _, f := range []func(){
+		func() { regs.DP(2) },
+		func() { regs.PTR(2) },
+		func() { regs.OFS(2) },
+		func() { regs.IX(2) },
+		func() { regs.IS(2) },
+		func() { regs.CTR(2) },
+		func() { regs.CS(2) },
+	} {
+		assert.PanicsWithValue(t, RegisterSetErr, f)
+	}
+}
+
+func TestRegistersStep(t *testing.T) {
+	regs := OfRegisters()
+
+	// Steps are signed
+	regs.CTR1, regs.CS1 = 1, 0xFFFF
+	regs.StepCounter(1)
+	assert.Equal(t, uint32(0), regs.CTR1)
+	assert.True(t, regs.ST().IsZero())
+	assert.False(t, regs.ST().IsNegative())
+
+	regs.StepCounter(1)
+	assert.Equal(t, uint32(0xFFFFFFFF), regs.CTR1)
+	assert.False(t, regs.ST().IsZero())
+	assert.True(t, regs.ST().IsNegative())
+
+	regs.IX0, regs.IS0 = 0xFFFC, 4
+	regs.StepIndex(0)
+	assert.Equal(t, uint16(0), regs.IX0)
+	assert.True(t, regs.ST().IsZero())
+
+	regs.IS0 = 0xFFF8
+	regs.StepIndex(0)
+	assert.Equal(t, uint16(0xFFF8), regs.IX0)
+	assert.True(t, regs.ST().IsNegative())
+
+	// Decrementing ignores the step, and is true until the counter reaches zero
+	regs.CTR0, regs.CS0 = 2, 5
+	assert.True(t, regs.DecrementCounter(0))
+	assert.Equal(t, uint32(1), regs.CTR0)
+	assert.False(t, regs.ST().IsZero())
+
+	assert.False(t, regs.DecrementCounter(0))
+	assert.Equal(t, uint32(0), regs.CTR0)
+	assert.True(t, regs.ST().IsZero())
+
+	assert.PanicsWithValue(t, RegisterSetErr, func() { regs.StepCounter(2) })
+	assert.PanicsWithValue(t, RegisterSetErr, func() { regs.StepIndex(2) })
+}
+
+func TestRegistersTimers(t *testing.T) {
+	regs := OfRegisters()
+
+	assert.Equal(t, []*uint32{&regs.TMR0, &regs.TMR1, &regs.TMR2, &regs.TMR3}, []*uint32{
+		regs.TMR(0), regs.TMR(1), regs.TMR(2), regs.TMR(3),
+	})
+	assert.Equal(t, []*uint32{&regs.TPTR0, &regs.TPTR1, &regs.TPTR2, &regs.TPTR3}, []*uint32{
+		regs.TPTR(0), regs.TPTR(1), regs.TPTR(2), regs.TPTR(3),
+	})
+
+	assert.PanicsWithValue(t, TimerRegisterErr, func() { regs.TMR(4) })
+	assert.PanicsWithValue(t, TimerRegisterErr, func() { regs.TPTR(4) })
+}
+
+func TestRegistersBinary(t *testing.T) {
+	regs := OfRegisters()
+	regs.R0, regs.R3 = 0x0102030405060708, 0xFFFFFFFFFFFFFFFF
+	regs.DP1, regs.OFS0, regs.CP, regs.PC = 0x11223344, 0x5566, 0x1000, 0x20
+	regs.SetST(StatusRegister(STCarrySet))
+
+	data, err := regs.MarshalBinary()
+	assert.Nil(t, err)
+	assert.Equal(t, 124, RegistersSize)
+	assert.Equal(t, RegistersSize, len(data))
+	assert.Equal(t, []byte{1, 2, 3, 4, 5, 6, 7, 8}, data[0:8])
+
+	var decoded Registers
+	assert.Nil(t, decoded.UnmarshalBinary(data))
+	assert.Equal(t, regs, decoded)
+	assert.True(t, decoded.ST().IsCarry())
+
+	// The wrong size is an error, and leaves the registers unchanged
+	assert.EqualError(t, decoded.UnmarshalBinary(data[1:]), "registers must be 124 bytes, not 123")
+	assert.Equal(t, regs, decoded)
+}
@@ -2,23 +2,30 @@
 
 package register
 
+import (
+	"github.com/bantling/goprocessor/pkg/memory"
+)
+
 const (
 	// DefaultSB is the default stack base
 	DefaultSB uint32 = 0xFFFE0000
 
+	// DefaultSL is the default stack limit
+	DefaultSL uint16 = 0xFFFF
+
 	// DefaultSP is the default stack pointer
 	DefaultSP uint16 = 0xFFFF
-	
-	// Bottom of stack for pushing an 8 bit value
+
+	// StackBottom8 is the bottom of stack for pushing an 8 bit value
 	StackBottom8 int32 = 0x0000
-	
-	// Bottom of stack for pushing a 16 bit value
+
+	// StackBottom16 is the bottom of stack for pushing a 16 bit value
 	StackBottom16 int32 = 0x0001
-	
-	// Bottom of stack for pushing a 32 bit value
+
+	// StackBottom32 is the bottom of stack for pushing a 32 bit value
 	StackBottom32 int32 = 0x0003
-	
-	// Bottom of stack for pushing a 64 bit value
+
+	// StackBottom64 is the bottom of stack for pushing a 64 bit value
 	StackBottom64 int32 = 0x0007
 )
 
@@ -26,95 +33,218 @@ const (
 type StackError string
 
 func (e StackError) Error() string {
-    return string(e)
+	return string(e)
 }
 
 var (
-    ErrStackOverflow = StackError("Stack Overflow")
-    ErrStackUnderflow = StackError("Staack Underflow") 
+	// ErrStackOverflow is returned when a push does not fit in the stack
+	ErrStackOverflow = StackError("Stack Overflow")
+
+	// ErrStackUnderflow is returned when a pull needs more bytes than the stack contains
+	ErrStackUnderflow = StackError("Stack Underflow")
 )
 
-// Stack represents a 64K block of memory that is the current stack space.
-// Stack does not allocate space, it manages an allocated space.
+// Stack is a view of the 64K block of memory that is the current stack space, as described by Registers SB, SL, and SP.
+// Stack does not allocate space, it manages a block of a memory.Bus that starts at SB.
+// SP is the offset from SB of the next free byte, and counts backwards as items are pushed.
+// SL is the highest offset from SB the stack uses, so the stack is empty when SP = SL.
+// Every operation reads and writes the Registers directly, so the view never goes stale.
+//
+// A push of n bytes requires SP >= n, so the byte at offset 0 is never used.
+// A pull of n bytes requires SP + n <= SL.
 type Stack struct {
-    stack []uint8
-    ptr int32
+	bus  memory.Bus
+	regs *Registers
 }
 
-// NewStack constructs a Stack from allocated space and initial pointer value.
-// 
+// NewStack constructs a Stack from a bus and the registers that contain SB, SL, and SP.
 func NewStack(
-    stack []uint8,
-    ptr uint16,
+	bus memory.Bus,
+	regs *Registers,
 ) *Stack {
-    return &Stack{
-        stack: stack,
-        ptr: int32(ptr),
-    }
+	return &Stack{
+		bus:  bus,
+		regs: regs,
+	}
+}
+
+// Pointer returns the current stack pointer
+func (s *Stack) Pointer() uint16 {
+	return s.regs.SP
+}
+
+// addr returns the address of the given offset from SP
+func (s *Stack) addr(offset int32) uint32 {
+	return s.regs.SB + uint32(int32(s.regs.SP)+offset)
+}
+
+// contains returns true if the stack contains at least n bytes
+func (s *Stack) contains(n int32) bool {
+	return int32(s.regs.SP)+n <= int32(s.regs.SL)
 }
 
 // Push8 pushes an 8 bit value to the stack.
-// Throws ErrStackOverflow is the stack does not have at least 8 bits left.
-func (s *Stack) Push8(op uint8) {
-    if (s.ptr == StackBottom8) {
-        panic(ErrStackOverflow)
-    }
-    
-    s.stack[s.ptr] = op
-    s.ptr--
+// Returns ErrStackOverflow if the stack does not have at least 8 bits left.
+func (s *Stack) Push8(op uint8) error {
+	if int32(s.regs.SP) == StackBottom8 {
+		return ErrStackOverflow
+	}
+
+	if err := s.bus.Write8(s.addr(0), op); err != nil {
+		return err
+	}
+
+	s.regs.SP--
+	return nil
 }
 
 // Push16 pushes a 16 bit value to the stack.
-// Throws ErrStackOverflow is the stack does not have at least 16 bits left.
-func (s *Stack) Push16(op uint16) {
-    if (s.ptr <= StackBottom16) {
-        panic(ErrStackOverflow)
-    }
-    
-    s.stack[s.ptr] = (uint8)(op & 0x00FF)
-    s.ptr--
-    s.stack[s.ptr] = (uint8)(op & 0xFF00)
-    s.ptr--
+// Returns ErrStackOverflow if the stack does not have at least 16 bits left.
+func (s *Stack) Push16(op uint16) error {
+	if int32(s.regs.SP) <= StackBottom16 {
+		return ErrStackOverflow
+	}
+
+	if err := s.bus.Write16(s.addr(-1), op); err != nil {
+		return err
+	}
+
+	s.regs.SP -= 2
+	return nil
 }
 
 // Push32 pushes a 32 bit value to the stack.
-// Throws ErrStackOverflow is the stack does not have at least 32 bits left.
-func (s *Stack) Push32(op uint32) {
-    if (s.ptr <= StackBottom32) {
-        panic(ErrStackOverflow)
-    }
-    
-    s.stack[s.ptr] = (uint8)(op & 0x000000FF)
-    s.ptr--
-    s.stack[s.ptr] = (uint8)(op & 0x0000FF00)
-    s.ptr--
-    s.stack[s.ptr] = (uint8)(op & 0x00FF0000)
-    s.ptr--
-    s.stack[s.ptr] = (uint8)(op & 0xFF000000)
-    s.ptr--
+// Returns ErrStackOverflow if the stack does not have at least 32 bits left.
+func (s *Stack) Push32(op uint32) error {
+	if int32(s.regs.SP) <= StackBottom32 {
+		return ErrStackOverflow
+	}
+
+	if err := s.bus.Write32(s.addr(-3), op); err != nil {
+		return err
+	}
+
+	s.regs.SP -= 4
+	return nil
 }
 
 // Push64 pushes a 64 bit value to the stack.
-// Throws ErrStackOverflow is the stack does not have at least 64 bits left.
-func (s *Stack) Push64(op uint64) {
-    if (s.ptr <= StackBottom64) {
-        panic(ErrStackOverflow)
-    }
-    
-    s.stack[s.ptr] = (uint8)(op & 0x00000000000000FF)
-    s.ptr--
-    s.stack[s.ptr] = (uint8)(op & 0x000000000000FF00)
-    s.ptr--
-    s.stack[s.ptr] = (uint8)(op & 0x0000000000FF0000)
-    s.ptr--
-    s.stack[s.ptr] = (uint8)(op & 0x00000000FF000000)
-    s.ptr--
-    s.stack[s.ptr] = (uint8)(op & 0x000000FF00000000)
-    s.ptr--
-    s.stack[s.ptr] = (uint8)(op & 0x0000FF0000000000)
-    s.ptr--
-    s.stack[s.ptr] = (uint8)(op & 0x00FF000000000000)
-    s.ptr--
-    s.stack[s.ptr] = (uint8)(op & 0xFF00000000000000)
-    s.ptr--
+// Returns ErrStackOverflow if the stack does not have at least 64 bits left.
+func (s *Stack) Push64(op uint64) error {
+	if int32(s.regs.SP) <= StackBottom64 {
+		return ErrStackOverflow
+	}
+
+	if err := s.bus.Write64(s.addr(-7), op); err != nil {
+		return err
+	}
+
+	s.regs.SP -= 8
+	return nil
+}
+
+// Peek8 reads the 8 bit value on top of the stack without pulling it.
+// Returns ErrStackUnderflow if the stack does not contain at least 8 bits.
+func (s *Stack) Peek8() (uint8, error) {
+	if !s.contains(1) {
+		return 0, ErrStackUnderflow
+	}
+
+	return s.bus.Read8(s.addr(1))
+}
+
+// Peek16 reads the 16 bit value on top of the stack without pulling it.
+// Returns ErrStackUnderflow if the stack does not contain at least 16 bits.
+func (s *Stack) Peek16() (uint16, error) {
+	if !s.contains(2) {
+		return 0, ErrStackUnderflow
+	}
+
+	return s.bus.Read16(s.addr(1))
+}
+
+// Peek32 reads the 32 bit value on top of the stack without pulling it.
+// Returns ErrStackUnderflow if the stack does not contain at least 32 bits.
+func (s *Stack) Peek32() (uint32, error) {
+	if !s.contains(4) {
+		return 0, ErrStackUnderflow
+	}
+
+	return s.bus.Read32(s.addr(1))
+}
+
+// Peek64 reads the 64 bit value on top of the stack without pulling it.
+// Returns ErrStackUnderflow if the stack does not contain at least 64 bits.
+func (s *Stack) Peek64() (uint64, error) {
+	if !s.contains(8) {
+		return 0, ErrStackUnderflow
+	}
+
+	return s.bus.Read64(s.addr(1))
+}
+
+// Pull8 pulls an 8 bit value from the stack.
+// Returns ErrStackUnderflow if the stack does not contain at least 8 bits.
+func (s *Stack) Pull8() (uint8, error) {
+	val, err := s.Peek8()
+	if err == nil {
+		s.regs.SP++
+	}
+
+	return val, err
+}
+
+// Pull16 pulls a 16 bit value from the stack.
+// Returns ErrStackUnderflow if the stack does not contain at least 16 bits.
+func (s *Stack) Pull16() (uint16, error) {
+	val, err := s.Peek16()
+	if err == nil {
+		s.regs.SP += 2
+	}
+
+	return val, err
+}
+
+// Pull32 pulls a 32 bit value from the stack.
+// Returns ErrStackUnderflow if the stack does not contain at least 32 bits.
+func (s *Stack) Pull32() (uint32, error) {
+	val, err := s.Peek32()
+	if err == nil {
+		s.regs.SP += 4
+	}
+
+	return val, err
+}
+
+// Pull64 pulls a 64 bit value from the stack.
+// Returns ErrStackUnderflow if the stack does not contain at least 64 bits.
+func (s *Stack) Pull64() (uint64, error) {
+	val, err := s.Peek64()
+	if err == nil {
+		s.regs.SP += 8
+	}
+
+	return val, err
+}
+
+// Reserve subtracts n from SP, reserving n bytes on the stack, as SSP does.
+// Returns ErrStackOverflow if the stack does not have at least n bytes left.
+func (s *Stack) Reserve(n uint16) error {
+	if s.regs.SP < n {
+		return ErrStackOverflow
+	}
+
+	s.regs.SP -= n
+	return nil
+}
+
+// Release adds n to SP, discarding n bytes from the stack, as RTS U8 does.
+// Returns ErrStackUnderflow if the stack does not contain at least n bytes.
+func (s *Stack) Release(n uint16) error {
+	if !s.contains(int32(n)) {
+		return ErrStackUnderflow
+	}
+
+	s.regs.SP += n
+	return nil
 }
@@ -0,0 +1,176 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package register
+
+import (
+	"testing"
+
+	"github.com/bantling/goprocessor/pkg/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestStack returns a stack bound to registers with the default SB and the given SP
+func newTestStack(bus memory.Bus, sp uint16) (*Stack, *Registers) {
+	regs := OfRegisters()
+	regs.SP = sp
+
+	return NewStack(bus, &regs), &regs
+}
+
+func TestStackPush(t *testing.T) {
+	var (
+		ram      = memory.NewRAM()
+		stack, _ = newTestStack(ram, DefaultSP)
+	)
+
+	assert.Nil(t, stack.Push8(0x12))
+	assert.Equal(t, uint16(0xFFFE), stack.Pointer())
+	val8, _ := ram.Read8(DefaultSB + 0xFFFF)
+	assert.Equal(t, uint8(0x12), val8)
+
+	assert.Nil(t, stack.Push16(0x3456))
+	assert.Equal(t, uint16(0xFFFC), stack.Pointer())
+	val16, _ := ram.Read16(DefaultSB + 0xFFFD)
+	assert.Equal(t, uint16(0x3456), val16)
+
+	assert.Nil(t, stack.Push32(0x789ABCDE))
+	assert.Equal(t, uint16(0xFFF8), stack.Pointer())
+	val32, _ := ram.Read32(DefaultSB + 0xFFF9)
+	assert.Equal(t, uint32(0x789ABCDE), val32)
+
+	assert.Nil(t, stack.Push64(0x0123456789ABCDEF))
+	assert.Equal(t, uint16(0xFFF0), stack.Pointer())
+	val64, _ := ram.Read64(DefaultSB + 0xFFF1)
+	assert.Equal(t, uint64(0x0123456789ABCDEF), val64)
+}
+
+func TestStackOverflow(t *testing.T) {
+	ram := memory.NewRAM()
+
+	stack, _ := newTestStack(ram, 0)
+	assert.Equal(t, ErrStackOverflow, stack.Push8(1))
+	assert.Equal(t, uint16(0), stack.Pointer())
+
+	stack, _ = newTestStack(ram, 1)
+	assert.Equal(t, ErrStackOverflow, stack.Push16(1))
+	assert.Nil(t, stack.Push8(1))
+
+	stack, _ = newTestStack(ram, 3)
+	assert.Equal(t, ErrStackOverflow, stack.Push32(1))
+	assert.Nil(t, stack.Push16(1))
+
+	stack, _ = newTestStack(ram, 7)
+	assert.Equal(t, ErrStackOverflow, stack.Push64(1))
+	assert.Nil(t, stack.Push32(1))
+	assert.Equal(t, uint16(3), stack.Pointer())
+
+	// Bus faults are passed through
+	ram.Protect(DefaultSB, 0x10000)
+	stack, _ = newTestStack(ram, DefaultSP)
+	assert.Equal(t, &memory.Fault{Addr: DefaultSB + 0xFFFF, Write: true}, stack.Push8(1))
+	assert.Equal(t, DefaultSP, stack.Pointer())
+}
+
+func TestStackPull(t *testing.T) {
+	var (
+		ram         = memory.NewRAM()
+		stack, regs = newTestStack(ram, DefaultSP)
+	)
+
+	assert.Nil(t, stack.Push64(0x0123456789ABCDEF))
+	assert.Nil(t, stack.Push32(0x789ABCDE))
+	assert.Nil(t, stack.Push16(0x3456))
+	assert.Nil(t, stack.Push8(0x12))
+	assert.Equal(t, uint16(0xFFF0), regs.SP)
+
+	val8, err := stack.Peek8()
+	assert.Equal(t, uint8(0x12), val8)
+	assert.Nil(t, err)
+	assert.Equal(t, uint16(0xFFF0), regs.SP)
+
+	val8, err = stack.Pull8()
+	assert.Equal(t, uint8(0x12), val8)
+	assert.Nil(t, err)
+	assert.Equal(t, uint16(0xFFF1), regs.SP)
+
+	val16, err := stack.Pull16()
+	assert.Equal(t, uint16(0x3456), val16)
+	assert.Nil(t, err)
+	assert.Equal(t, uint16(0xFFF3), regs.SP)
+
+	val32, err := stack.Pull32()
+	assert.Equal(t, uint32(0x789ABCDE), val32)
+	assert.Nil(t, err)
+	assert.Equal(t, uint16(0xFFF7), regs.SP)
+
+	val64, err := stack.Peek64()
+	assert.Equal(t, uint64(0x0123456789ABCDEF), val64)
+	assert.Nil(t, err)
+
+	val64, err = stack.Pull64()
+	assert.Equal(t, uint64(0x0123456789ABCDEF), val64)
+	assert.Nil(t, err)
+	assert.Equal(t, DefaultSP, regs.SP)
+
+	// The stack follows changes made to the registers
+	regs.SB, regs.SP = 0x00010000, 0x1000
+	assert.Nil(t, stack.Push16(0xBEEF))
+	val16, _ = ram.Read16(0x00010FFF)
+	assert.Equal(t, uint16(0xBEEF), val16)
+	assert.Equal(t, uint16(0x0FFE), stack.Pointer())
+}
+
+func TestStackUnderflow(t *testing.T) {
+	ram := memory.NewRAM()
+
+	stack, regs := newTestStack(ram, DefaultSP)
+	_, err := stack.Pull8()
+	assert.Equal(t, ErrStackUnderflow, err)
+	_, err = stack.Peek8()
+	assert.Equal(t, ErrStackUnderflow, err)
+	assert.Equal(t, DefaultSP, regs.SP)
+
+	stack, regs = newTestStack(ram, 0xFFFE)
+	_, err = stack.Pull16()
+	assert.Equal(t, ErrStackUnderflow, err)
+	_, err = stack.Pull8()
+	assert.Nil(t, err)
+
+	stack, regs = newTestStack(ram, 0xFFFC)
+	_, err = stack.Pull32()
+	assert.Equal(t, ErrStackUnderflow, err)
+	_, err = stack.Pull16()
+	assert.Nil(t, err)
+
+	stack, regs = newTestStack(ram, 0xFFF8)
+	_, err = stack.Pull64()
+	assert.Equal(t, ErrStackUnderflow, err)
+	_, err = stack.Pull32()
+	assert.Nil(t, err)
+	assert.Equal(t, uint16(0xFFFC), regs.SP)
+
+	// SL is the top of the stack
+	stack, regs = newTestStack(ram, 0x7FF0)
+	regs.SL = 0x7FF4
+	_, err = stack.Pull64()
+	assert.Equal(t, ErrStackUnderflow, err)
+	_, err = stack.Pull32()
+	assert.Nil(t, err)
+	assert.Equal(t, ErrStackUnderflow, stack.Release(1))
+}
+
+func TestStackReserveRelease(t *testing.T) {
+	stack, regs := newTestStack(memory.NewRAM(), DefaultSP)
+
+	assert.Nil(t, stack.Reserve(0x10))
+	assert.Equal(t, uint16(0xFFEF), regs.SP)
+
+	assert.Equal(t, ErrStackUnderflow, stack.Release(0x11))
+	assert.Nil(t, stack.Release(0x10))
+	assert.Equal(t, DefaultSP, regs.SP)
+
+	regs.SP = 0x10
+	assert.Equal(t, ErrStackOverflow, stack.Reserve(0x11))
+	assert.Nil(t, stack.Reserve(0x10))
+	assert.Equal(t, uint16(0), regs.SP)
+}
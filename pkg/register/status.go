@@ -3,6 +3,8 @@
 package register
 
 import (
+	"fmt"
+
 	"github.com/bantling/gofuncs"
 )
 
@@ -106,6 +108,12 @@ const (
 	// STSystemShift is the ST shift for system bits
 	STSystemShift int = 8
 
+	// STCodeAddressSet is ST filter for selecting the code address modes (a system bit)
+	STCodeAddressSet uint32 = 0x00008000
+
+	// STCodeAddressClear is ST filter for selecting the data address modes
+	STCodeAddressClear uint32 = 0xFFFFFFFF - STCodeAddressSet
+
 	// STUserRead is ST filter for reading user bits
 	STUserRead uint32 = 0x000000FF
 
@@ -166,6 +174,7 @@ const STMathModeErr = "Math mode must be <= 3"
 // Carry, oVerflow, Zero, Negative, Address mode, Interrupt Disable,
 // Register, Pointer register set, counTer register set, Operand size, Math mode.
 // 8 bits reserved for system use, and 8 bits reserved for users.
+// The highest system bit selects code (CP relative) rather than data (DP relative) address modes.
 type StatusRegister uint32
 
 // IsCarry returns true if the carry flag is set
@@ -276,6 +285,30 @@ func (st *StatusRegister) SelectAddressMode(am uint8) {
 	*st = StatusRegister((uint32(*st) & STAddressSet) + (uint32(am) << STAddressShift))
 }
 
+// IsCodeAddressMode returns true if the address mode is relative to CP rather than DP
+func (st StatusRegister) IsCodeAddressMode() bool {
+	return (uint32(st) & STCodeAddressSet) == STCodeAddressSet
+}
+
+// CodeAddressMode selects code address modes if the value is true, else selects data address modes
+func (st *StatusRegister) CodeAddressMode(val bool) {
+	if val {
+		st.SelectCodeAddressMode()
+	} else {
+		st.SelectDataAddressMode()
+	}
+}
+
+// SelectCodeAddressMode selects address modes relative to CP
+func (st *StatusRegister) SelectCodeAddressMode() {
+	*st |= StatusRegister(STCodeAddressSet)
+}
+
+// SelectDataAddressMode selects address modes relative to DP
+func (st *StatusRegister) SelectDataAddressMode() {
+	*st &= StatusRegister(STCodeAddressClear)
+}
+
 // IsInterruptDisable returns true if the interrupt disable flag is set
 func (st StatusRegister) IsInterruptDisable() bool {
 	return (uint32(st) & STInterruptDisableSet) == STInterruptDisableSet
@@ -297,7 +330,7 @@ func (st *StatusRegister) SetInterruptDisable() {
 
 // ClearInterruptDisable clears the interrupt disable flag
 func (st *StatusRegister) ClearInterruptDisable() {
-	*st = StatusRegister(STInterruptDisableClear)
+	*st &= StatusRegister(STInterruptDisableClear)
 }
 
 // Register returns the selected register
@@ -384,6 +417,38 @@ func (st *StatusRegister) SelectMathMode(mm uint8) {
 	*st = StatusRegister((uint32(*st) & STMathSet) + (uint32(mm) << STMathShift))
 }
 
+// EffectiveOperandSize returns the operand size of values in registers, memory, and O immediates, given the selected
+// operand size and math mode. It is the operand size, except that 8 bits is promoted to 16 bits in the fractional,
+// fixed point, and floating point math modes, which have no 8 bit format.
+func EffectiveOperandSize(operandSize, mathMode uint8) uint8 {
+	if (operandSize == Operand8) && (mathMode != MathInteger) {
+		return Operand16
+	}
+
+	return operandSize
+}
+
+// OperandSizeOfBits returns the operand size of 8, 16, 32, or 64 bits, and false for any other number of bits
+func OperandSizeOfBits(bits int) (uint8, bool) {
+	switch bits {
+	case 8:
+		return Operand8, true
+	case 16:
+		return Operand16, true
+	case 32:
+		return Operand32, true
+	case 64:
+		return Operand64, true
+	}
+
+	return 0, false
+}
+
+// EffectiveOperandSize returns the effective operand size of the selected operand size and math mode
+func (st StatusRegister) EffectiveOperandSize() uint8 {
+	return EffectiveOperandSize(st.OperandSize(), st.MathMode())
+}
+
 // System returns the system defined ST bits
 func (st StatusRegister) System() uint8 {
 	return uint8((uint32(st) & STSystemRead) >> STSystemShift)
@@ -404,63 +469,59 @@ func (st *StatusRegister) SetUser(sb uint8) {
 	*st = StatusRegister((uint32(*st) & STUserSet) + uint32(sb))
 }
 
-// Registers contains the processor registers
-type Registers struct {
-	// General Purpose
-	R0 uint64
-	R1 uint64
-	R2 uint64
-	R3 uint64
-
-	// Ptr
-	PTR0 uint32
-	PTR1 uint32
-
-	// Offset
-	OFS0 uint16
-	OFS1 uint16
-
-	// Index
-	IX0 uint16
-	IX1 uint16
-
-	// Index Step
-	IS0 uint16
-	IS1 uint16
-
-	// Counter
-	CTR0 uint32
-	CTR1 uint32
-
-	// Counter Step
-	CS0 uint16
-	CS1 uint16
-
-	// Program counter
-	PC uint32
-
-	// Status
-	st StatusRegister
+// addressModeNames are the names of the address modes used by the instruction set
+var addressModeNames = [...]string{"PTR", "(PTR)", "[PTR]", "([PTR])", "*PTR", "(*PTR)", "[*PTR]", "([*PTR])"}
+
+// mathModeNames are the names of the math modes
+var mathModeNames = [...]string{"Integer", "Fractional", "Fixed", "Float"}
+
+// String returns a decoded view of the status register:
+// the CVZN flags with - for each clear flag, the address mode relative to DP or CP, the interrupt disable bit,
+// the selected general register, the pointer and counter register sets, the operand size in bits, the math mode,
+// and the system and user bits in hex.
+func (st StatusRegister) String() string {
+	var (
+		flags = []byte("CVZN")
+		base  = "DP"
+		id    = 0
+		ps    = 0
+		cs    = 0
+	)
+
+	for i, set := range []bool{st.IsCarry(), st.IsOverflow(), st.IsZero(), st.IsNegative()} {
+		if !set {
+			flags[i] = '-'
+		}
+	}
 
-	// Stack base
-	SB uint32
+	if st.IsCodeAddressMode() {
+		base = "CP"
+	}
 
-	// Stack pointer
-	SP uint16
-}
+	if st.IsInterruptDisable() {
+		id = 1
+	}
 
-// OfRegisters creates a new Registers where all registers = 0 except:
-// SB = DefaultSB
-// SP = DefaultSP
-func OfRegisters() Registers {
-	var reg Registers
-	reg.SB = DefaultSB
-	reg.SP = DefaultSP
+	if !st.IsPointerRegisterSet0() {
+		ps = 1
+	}
 
-	return reg
-}
+	if !st.IsCounterRegisterSet0() {
+		cs = 1
+	}
 
-// ST returns current status register value
-func (r Registers) ST() StatusRegister {
-	return r.st
+	return fmt.Sprintf(
+		"CVZN=%s AM=%s:%s ID=%d R=R%d PS=%d CS=%d OS=%d MM=%s SYS=%02X USR=%02X",
+		flags,
+		base,
+		addressModeNames[st.AddressMode()],
+		id,
+		st.Register(),
+		ps,
+		cs,
+		8<<st.OperandSize(),
+		mathModeNames[st.MathMode()],
+		st.System(),
+		st.User(),
+	)
 }
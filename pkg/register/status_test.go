@@ -8,6 +8,15 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
+// status returns a status register with the given operand size and math mode
+func status(operandSize, mathMode uint8) StatusRegister {
+	var st StatusRegister
+	st.SelectOperandSize(operandSize)
+	st.SelectMathMode(mathMode)
+
+	return st
+}
+
 func TestStatusRegister(t *testing.T) {
 	var st = new(StatusRegister)
 
@@ -147,6 +156,15 @@ func TestStatusRegister(t *testing.T) {
 	assert.Equal(t, MathFloat, st.MathMode())
 	assert.Equal(t, StatusRegister(0xFFFFFFFF), *st)
 
+	// Code address mode
+	assert.True(t, st.IsCodeAddressMode())
+	st.SelectDataAddressMode()
+	assert.False(t, st.IsCodeAddressMode())
+	assert.Equal(t, StatusRegister(0xFFFF7FFF), *st)
+	st.CodeAddressMode(true)
+	assert.True(t, st.IsCodeAddressMode())
+	assert.Equal(t, StatusRegister(0xFFFFFFFF), *st)
+
 	// System bits
 	assert.Equal(t, uint8(0xFF), st.System())
 	st.SetSystem(0x00)
@@ -165,3 +183,52 @@ func TestStatusRegister(t *testing.T) {
 	assert.Equal(t, uint8(0xFF), st.User())
 	assert.Equal(t, StatusRegister(0xFFFFFFFF), *st)
 }
+
+func TestEffectiveOperandSize(t *testing.T) {
+	var st StatusRegister
+	for _, mm := range []uint8{MathInteger, MathFractional, MathFixed, MathFloat} {
+		st.SelectMathMode(mm)
+		for _, size := range []uint8{Operand8, Operand16, Operand32, Operand64} {
+			st.SelectOperandSize(size)
+
+			want := size
+			if (size == Operand8) && (mm != MathInteger) {
+				want = Operand16
+			}
+
+			assert.Equal(t, want, st.EffectiveOperandSize())
+			assert.Equal(t, want, EffectiveOperandSize(size, mm))
+		}
+	}
+}
+
+func TestOperandSizeOfBits(t *testing.T) {
+	for bits, want := range map[int]uint8{8: Operand8, 16: Operand16, 32: Operand32, 64: Operand64} {
+		size, ok := OperandSizeOfBits(bits)
+		assert.True(t, ok)
+		assert.Equal(t, want, size)
+	}
+
+	for _, bits := range []int{0, 4, 24, 128} {
+		_, ok := OperandSizeOfBits(bits)
+		assert.False(t, ok)
+	}
+}
+
+func TestStatusRegisterString(t *testing.T) {
+	var st StatusRegister
+	assert.Equal(t, "CVZN=---- AM=DP:PTR ID=0 R=R0 PS=0 CS=0 OS=8 MM=Integer SYS=00 USR=00", st.String())
+
+	st.SetCarry()
+	st.SetZero()
+	st.SelectAddressMode(PtrPtrIxOfs)
+	st.SelectCodeAddressMode()
+	st.SetInterruptDisable()
+	st.SelectRegister(RegisterR3)
+	st.SelectPointerRegisterSet1()
+	st.SelectCounterRegisterSet1()
+	st.SelectOperandSize(Operand64)
+	st.SelectMathMode(MathFixed)
+	st.SetUser(0x5A)
+	assert.Equal(t, "CVZN=C-Z- AM=CP:([*PTR]) ID=1 R=R3 PS=1 CS=1 OS=64 MM=Fixed SYS=80 USR=5A", st.String())
+}
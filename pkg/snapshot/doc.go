@@ -0,0 +1,10 @@
+// Package snapshot saves the complete state of a machine to a versioned file, and restores it later.
+//
+// A snapshot contains every register including ST, the execution state of the processor (cycle and instruction
+// counts, pending IRQ lines, expired timers, and the time of the last timer tick), the pages of RAM that have been
+// written and which pages are read only, and the state of each named Device.
+//
+// Snapshots begin with the magic bytes GPSN and a 16 bit version. The clock of the processor is not saved: the
+// default VirtualClock derives time from the cycle count, so restored timers continue as if execution never stopped.
+// SPDX-License-Identifier: Apache-2.0
+package snapshot
@@ -0,0 +1,305 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package snapshot
+
+import (
+	"bufio"
+	"bytes"
+	"encoding"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/bantling/goprocessor/pkg/cpu"
+	"github.com/bantling/goprocessor/pkg/memory"
+	"github.com/bantling/goprocessor/pkg/register"
+)
+
+const (
+	// Magic begins a snapshot
+	Magic = "GPSN"
+
+	// Version is the version of the snapshot format
+	Version uint16 = 1
+
+	// maxPages is the number of pages in the 32 bit address space
+	maxPages uint32 = 1 << (32 - memory.PageShift)
+)
+
+// SnapshotError represents an error restoring a snapshot
+type SnapshotError string
+
+func (e SnapshotError) Error() string {
+	return string(e)
+}
+
+const (
+	// ErrVersion is returned if a snapshot has a version that cannot be read
+	ErrVersion = SnapshotError("Unsupported Version")
+
+	// ErrCorrupt is returned if a snapshot cannot be decoded
+	ErrCorrupt = SnapshotError("Corrupt Snapshot")
+
+	// ErrDevice is returned if a snapshot has a device that the machine does not
+	ErrDevice = SnapshotError("Unknown Device")
+)
+
+// Device is a device whose state is saved in a snapshot, such as one that raises interrupts or is mapped onto the bus
+type Device interface {
+	encoding.BinaryMarshaler
+	encoding.BinaryUnmarshaler
+}
+
+// Machine is the state that a snapshot saves and restores
+type Machine struct {
+	Processor *cpu.Processor
+	RAM       *memory.RAM
+	Devices   map[string]Device // may be nil if there are no devices
+}
+
+// Save writes a snapshot of a machine:
+//
+//	magic and version  GPSN, u16
+//	registers          register.RegistersSize bytes of register.Registers.MarshalBinary
+//	state              cpu.State, big endian
+//	pages              u32 count, then for each the u32 page number and memory.PageSize bytes
+//	protected pages    u32 count, then each u32 page number
+//	devices            u32 count, then for each the u8 length and name, and the u32 length and state
+//
+// Pages that are all zeroes are not written, and devices are written in order of name.
+func Save(w io.Writer, m Machine) error {
+	regs, err := m.Processor.Registers().MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	var (
+		bw    = &writer{w: bufio.NewWriter(w)}
+		pages = m.RAM.Pages()
+		buf   = make([]byte, memory.PageSize)
+		names = make([]string, 0, len(m.Devices))
+	)
+
+	bw.write([]byte(Magic))
+	bw.write(Version)
+	bw.write(regs)
+	bw.write(m.Processor.State())
+
+	// Only pages that are not all zeroes are written
+	var written []uint32
+	for _, pg := range pages {
+		memory.ReadBytes(m.RAM, pg<<memory.PageShift, buf)
+		if !isZero(buf) {
+			written = append(written, pg)
+		}
+	}
+
+	bw.write(uint32(len(written)))
+	for _, pg := range written {
+		memory.ReadBytes(m.RAM, pg<<memory.PageShift, buf)
+		bw.write(pg)
+		bw.write(buf)
+	}
+
+	protected := m.RAM.ProtectedPages()
+	bw.write(uint32(len(protected)))
+	bw.write(protected)
+
+	for name := range m.Devices {
+		if len(name) > 0xFF {
+			return fmt.Errorf("device name %q is longer than 255 bytes", name)
+		}
+
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	bw.write(uint32(len(names)))
+	for _, name := range names {
+		state, err := m.Devices[name].MarshalBinary()
+		if err != nil {
+			return fmt.Errorf("device %s: %w", name, err)
+		}
+
+		bw.write(uint8(len(name)))
+		bw.write([]byte(name))
+		bw.write(uint32(len(state)))
+		bw.write(state)
+	}
+
+	if bw.err != nil {
+		return bw.err
+	}
+
+	return bw.w.Flush()
+}
+
+// Restore reads a snapshot into a machine, replacing all of its registers, execution state, and memory.
+//
+// The snapshot is read entirely before the machine is modified, so an error reading it leaves the machine unchanged.
+// Returns an error wrapping ErrDevice if the snapshot has a device that is not in m.Devices; devices of the machine
+// that are not in the snapshot are unchanged. If a device fails to restore its state, the processor and memory are
+// unchanged, but devices restored before it are not.
+func Restore(r io.Reader, m Machine) error {
+	br := &reader{r: bufio.NewReader(r)}
+	if magic := br.bytes(len(Magic)); (br.err != nil) || (string(magic) != Magic) {
+		return ErrCorrupt
+	}
+
+	var version uint16
+	if br.read(&version); br.err != nil {
+		return ErrCorrupt
+	}
+
+	if version != Version {
+		return ErrVersion
+	}
+
+	var (
+		regs    register.Registers
+		state   cpu.State
+		count   uint32
+		pages   = map[uint32][]byte{}
+		devices = map[string][]byte{}
+	)
+
+	if err := regs.UnmarshalBinary(br.bytes(register.RegistersSize)); (br.err == nil) && (err != nil) {
+		br.err = err
+	}
+
+	br.read(&state)
+
+	if br.read(&count); count > maxPages {
+		br.fail("%d pages", count)
+	}
+
+	seen := map[uint32]bool{}
+	for i := uint32(0); (br.err == nil) && (i < count); i++ {
+		pg := br.page(seen)
+		pages[pg] = br.bytes(int(memory.PageSize))
+	}
+
+	if br.read(&count); count > maxPages {
+		br.fail("%d protected pages", count)
+	}
+
+	protected := make([]uint32, 0, count)
+	seen = map[uint32]bool{}
+	for i := uint32(0); (br.err == nil) && (i < count); i++ {
+		protected = append(protected, br.page(seen))
+	}
+
+	br.read(&count)
+	for i := uint32(0); (br.err == nil) && (i < count); i++ {
+		var (
+			nameLen  uint8
+			stateLen uint32
+		)
+
+		br.read(&nameLen)
+		name := string(br.bytes(int(nameLen)))
+		br.read(&stateLen)
+		devices[name] = br.bytes(int(stateLen))
+	}
+
+	if br.err != nil {
+		return fmt.Errorf("%w: %s", ErrCorrupt, br.err)
+	}
+
+	for name := range devices {
+		if _, ok := m.Devices[name]; !ok {
+			return fmt.Errorf("%w: %s", ErrDevice, name)
+		}
+	}
+
+	for name, data := range devices {
+		if err := m.Devices[name].UnmarshalBinary(data); err != nil {
+			return fmt.Errorf("device %s: %w", name, err)
+		}
+	}
+
+	*m.Processor.Registers() = regs
+	m.Processor.SetState(state)
+
+	m.RAM.Reset()
+	for pg, data := range pages {
+		memory.WriteBytes(m.RAM, pg<<memory.PageShift, data)
+	}
+
+	for _, pg := range protected {
+		m.RAM.Protect(pg<<memory.PageShift, memory.PageSize)
+	}
+
+	return nil
+}
+
+// writer writes big endian values, keeping the first error
+type writer struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (w *writer) write(val interface{}) {
+	if w.err == nil {
+		w.err = binary.Write(w.w, binary.BigEndian, val)
+	}
+}
+
+// reader reads big endian values, keeping the first error
+type reader struct {
+	r   *bufio.Reader
+	err error
+}
+
+func (r *reader) read(val interface{}) {
+	if r.err == nil {
+		r.err = binary.Read(r.r, binary.BigEndian, val)
+	}
+}
+
+// bytes reads n bytes, which are only allocated as they are read so that a corrupt length cannot exhaust memory
+func (r *reader) bytes(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	if _, err := io.CopyN(&buf, r.r, int64(n)); err != nil {
+		r.err = io.ErrUnexpectedEOF
+		return nil
+	}
+
+	return buf.Bytes()
+}
+
+// page reads a page number and adds it to seen, failing if it is outside the address space or already seen
+func (r *reader) page(seen map[uint32]bool) uint32 {
+	var pg uint32
+	if r.read(&pg); pg >= maxPages {
+		r.fail("page %d", pg)
+	} else if seen[pg] {
+		r.fail("duplicate page %d", pg)
+	}
+
+	seen[pg] = true
+	return pg
+}
+
+// fail sets an error if there is none
+func (r *reader) fail(format string, args ...interface{}) {
+	if r.err == nil {
+		r.err = fmt.Errorf(format, args...)
+	}
+}
+
+// isZero is true if every byte is 0
+func isZero(buf []byte) bool {
+	for _, b := range buf {
+		if b != 0 {
+			return false
+		}
+	}
+
+	return true
+}
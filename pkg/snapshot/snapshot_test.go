@@ -0,0 +1,162 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package snapshot
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bantling/goprocessor/pkg/cpu"
+	"github.com/bantling/goprocessor/pkg/internal/testprog"
+	"github.com/bantling/goprocessor/pkg/memory"
+	"github.com/bantling/goprocessor/pkg/register"
+	"github.com/stretchr/testify/assert"
+)
+
+// program counts R0 up forever, storing it after each increment
+const program = `
+loop:   INC     R0
+        MOV     M[0x100], R0
+        JMP     loop
+`
+
+// counter is a device that counts the interrupts it raised
+type counter struct {
+	raised uint32
+}
+
+func (c *counter) MarshalBinary() ([]byte, error) {
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], c.raised)
+	return buf[:], nil
+}
+
+func (c *counter) UnmarshalBinary(data []byte) error {
+	if len(data) != 4 {
+		return errors.New("counter must be 4 bytes")
+	}
+
+	c.raised = binary.BigEndian.Uint32(data)
+	return nil
+}
+
+// machine assembles program at CP 0x1000, with data at DP0 0x2000
+func machine(t *testing.T) Machine {
+	p := testprog.Processor(t, program, 0x1000)
+	p.Registers().DP0 = 0x2000
+	return Machine{Processor: p, RAM: p.Bus().(*memory.RAM), Devices: map[string]Device{"counter": &counter{}}}
+}
+
+func TestSaveRestore(t *testing.T) {
+	m := machine(t)
+	testprog.Steps(t, m.Processor, 7)
+
+	// Interrupts are disabled, so a raised line stays pending
+	m.Processor.Raise(2)
+	m.Devices["counter"].(*counter).raised = 1
+	m.RAM.Protect(0x1000, 1)
+	m.RAM.Write8(0x3000, 0)
+	m.Processor.Registers().SetST(m.Processor.Registers().ST() | register.StatusRegister(register.STCarrySet))
+
+	var buf bytes.Buffer
+	assert.Nil(t, Save(&buf, m))
+	assert.Equal(t, Magic, buf.String()[:4])
+
+	// Restoring replaces everything, including memory written after the snapshot
+	r := machine(t)
+	r.RAM.Write8(0x5000, 1)
+	assert.Nil(t, Restore(bytes.NewReader(buf.Bytes()), r))
+
+	assert.Equal(t, *m.Processor.Registers(), *r.Processor.Registers())
+	assert.Equal(t, m.Processor.State(), r.Processor.State())
+	assert.True(t, r.Processor.Pending(2))
+	assert.Equal(t, uint32(1), r.Devices["counter"].(*counter).raised)
+	assert.Equal(t, []uint32{0x1}, r.RAM.ProtectedPages())
+
+	// The zero page at 0x3000 and the page at 0x5000 are not in the snapshot
+	assert.Equal(t, []uint32{0x1, 0x2}, r.RAM.Pages())
+	val8, _ := r.RAM.Read8(0x2100)
+	assert.Equal(t, uint8(2), val8)
+
+	// Both machines continue identically
+	testprog.Steps(t, m.Processor, 5)
+	testprog.Steps(t, r.Processor, 5)
+	assert.Equal(t, *m.Processor.Registers(), *r.Processor.Registers())
+	assert.Equal(t, m.Processor.State(), r.Processor.State())
+}
+
+func TestRestoreErrors(t *testing.T) {
+	var (
+		m   = machine(t)
+		buf bytes.Buffer
+	)
+
+	testprog.Steps(t, m.Processor, 2)
+	assert.Nil(t, Save(&buf, m))
+	data := buf.Bytes()
+
+	// Errors leave the machine unchanged
+	r := machine(t)
+	assert.Equal(t, ErrCorrupt, Restore(strings.NewReader("GPTR\x00\x01"), r))
+
+	version := append([]byte(nil), data...)
+	version[5] = 2
+	assert.Equal(t, ErrVersion, Restore(bytes.NewReader(version), r))
+
+	err := Restore(bytes.NewReader(data[:len(data)-1]), r)
+	assert.True(t, errors.Is(err, ErrCorrupt))
+	assert.EqualError(t, err, "Corrupt Snapshot: unexpected EOF")
+
+	r.Devices = nil
+	err = Restore(bytes.NewReader(data), r)
+	assert.True(t, errors.Is(err, ErrDevice))
+	assert.EqualError(t, err, "Unknown Device: counter")
+
+	r.Devices = map[string]Device{"counter": &counter{}}
+	assert.Equal(t, uint64(0), r.Processor.Cycles())
+	assert.Equal(t, uint64(0), r.Processor.Registers().R0)
+
+	// A machine with devices restores a snapshot without them
+	var empty bytes.Buffer
+	assert.Nil(t, Save(&empty, Machine{Processor: cpu.New(memory.NewRAM()), RAM: memory.NewRAM()}))
+	assert.Nil(t, Restore(&empty, r))
+	assert.Equal(t, []uint32{}, r.RAM.Pages())
+}
+
+func TestRestorePageErrors(t *testing.T) {
+	// The snapshot of an empty machine ends with 0 pages, 0 protected pages, and 0 devices
+	var empty bytes.Buffer
+	assert.Nil(t, Save(&empty, Machine{Processor: cpu.New(memory.NewRAM()), RAM: memory.NewRAM()}))
+	header := empty.Bytes()[:empty.Len()-12]
+
+	// snapshot returns the header followed by big endian values
+	snapshot := func(vals ...interface{}) []byte {
+		buf := bytes.NewBuffer(append([]byte(nil), header...))
+		for _, val := range vals {
+			assert.Nil(t, binary.Write(buf, binary.BigEndian, val))
+		}
+
+		return buf.Bytes()
+	}
+
+	page := make([]byte, memory.PageSize)
+	for data, msg := range map[string]string{
+		string(snapshot(uint32(1), maxPages)):                         "page 1048576",
+		string(snapshot(uint32(2), uint32(3), page, uint32(3), page)): "duplicate page 3",
+		string(snapshot(uint32(0), uint32(1), maxPages)):              "page 1048576",
+		string(snapshot(uint32(0), uint32(2), uint32(5), uint32(5))):  "duplicate page 5",
+	} {
+		r := Machine{Processor: cpu.New(memory.NewRAM()), RAM: memory.NewRAM()}
+		assert.Nil(t, r.RAM.Write8(0, 1))
+
+		err := Restore(strings.NewReader(data), r)
+		assert.True(t, errors.Is(err, ErrCorrupt), msg)
+		assert.EqualError(t, err, "Corrupt Snapshot: "+msg)
+
+		val, _ := r.RAM.Read8(0)
+		assert.Equal(t, uint8(1), val, msg)
+	}
+}
@@ -0,0 +1,11 @@
+// Package trace records every instruction a cpu.Processor executes, and reads and compares the recorded traces.
+//
+// A Record describes one instruction: the cycle it began at, CP and PC, the encoded bytes, the disassembly, the
+// registers it changed, and the status register before and after it. Entering an interrupt routine is recorded
+// separately, with no bytes and the text INTERRUPT.
+//
+// Traces are written as JSON Lines, one object per record, or a compact binary format that begins with the magic
+// bytes GPTR and a 16 bit version. NewReader reads either format. The binary format stores the cycle and registers
+// as differences, and does not store the disassembly, which is decoded from the bytes when read.
+// SPDX-License-Identifier: Apache-2.0
+package trace
@@ -0,0 +1,396 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package trace
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/bantling/goprocessor/pkg/debug"
+	"github.com/bantling/goprocessor/pkg/disasm"
+	"github.com/bantling/goprocessor/pkg/register"
+)
+
+const (
+	// Magic begins a binary trace
+	Magic = "GPTR"
+
+	// Version is the version of the binary format
+	Version uint16 = 1
+
+	// flagCP means a binary record has a CP, which is otherwise the CP of the previous record
+	flagCP = 1 << 0
+
+	// flagST means a binary record has a new ST, which is otherwise the old ST
+	flagST = 1 << 1
+)
+
+// TraceError represents an error reading a trace
+type TraceError string
+
+func (e TraceError) Error() string {
+	return string(e)
+}
+
+const (
+	// ErrVersion is returned if a binary trace has a version that cannot be read
+	ErrVersion = TraceError("Unsupported Version")
+
+	// ErrCorrupt is returned if a trace cannot be decoded
+	ErrCorrupt = TraceError("Corrupt Trace")
+)
+
+// registerNames are the names of the exported fields of register.Registers, which binary records refer to by index
+var registerNames = func() []string {
+	var (
+		typ   = reflect.TypeOf(register.Registers{})
+		names []string
+	)
+
+	for i := 0; i < typ.NumField(); i++ {
+		if f := typ.Field(i); f.PkgPath == "" {
+			names = append(names, f.Name)
+		}
+	}
+
+	return names
+}()
+
+// registerIndexes maps the names of registerNames to their index
+var registerIndexes = func() map[string]int {
+	indexes := map[string]int{}
+	for i, name := range registerNames {
+		indexes[name] = i
+	}
+
+	return indexes
+}()
+
+// ==== JSON Lines
+
+// jsonChange is a debug.RegisterChange in JSON
+type jsonChange struct {
+	Name string `json:"name"`
+	Old  uint64 `json:"old"`
+	New  uint64 `json:"new"`
+}
+
+// jsonRecord is a Record in JSON, where the bytes are hex
+type jsonRecord struct {
+	Cycle     uint64       `json:"cycle"`
+	CP        uint32       `json:"cp"`
+	PC        uint32       `json:"pc"`
+	Bytes     string       `json:"bytes"`
+	Text      string       `json:"text"`
+	Registers []jsonChange `json:"regs,omitempty"`
+	OldST     uint32       `json:"oldST"`
+	NewST     uint32       `json:"newST"`
+}
+
+// JSONWriter writes records as JSON Lines
+type JSONWriter struct {
+	w   *bufio.Writer
+	enc *json.Encoder
+}
+
+// NewJSONWriter constructs a JSONWriter
+func NewJSONWriter(w io.Writer) *JSONWriter {
+	bw := bufio.NewWriter(w)
+	return &JSONWriter{w: bw, enc: json.NewEncoder(bw)}
+}
+
+// Write writes a record as one line
+func (j *JSONWriter) Write(r Record) error {
+	jr := jsonRecord{
+		Cycle: r.Cycle,
+		CP:    r.CP,
+		PC:    r.PC,
+		Bytes: fmt.Sprintf("%X", r.Bytes),
+		Text:  r.Text,
+		OldST: uint32(r.OldST),
+		NewST: uint32(r.NewST),
+	}
+
+	for _, c := range r.Registers {
+		jr.Registers = append(jr.Registers, jsonChange(c))
+	}
+
+	return j.enc.Encode(jr)
+}
+
+// Flush writes any buffered records
+func (j *JSONWriter) Flush() error {
+	return j.w.Flush()
+}
+
+// jsonReader reads records from JSON Lines
+type jsonReader struct {
+	s    *bufio.Scanner
+	line int
+}
+
+func (j *jsonReader) Read() (Record, error) {
+	for j.s.Scan() {
+		j.line++
+		if len(j.s.Bytes()) == 0 {
+			continue
+		}
+
+		var jr jsonRecord
+		if err := json.Unmarshal(j.s.Bytes(), &jr); err != nil {
+			return Record{}, fmt.Errorf("line %d: %w: %s", j.line, ErrCorrupt, err)
+		}
+
+		code, err := hex.DecodeString(jr.Bytes)
+		if err != nil {
+			return Record{}, fmt.Errorf("line %d: %w: invalid bytes %s", j.line, ErrCorrupt, jr.Bytes)
+		}
+
+		r := Record{
+			Cycle: jr.Cycle,
+			CP:    jr.CP,
+			PC:    jr.PC,
+			Bytes: code,
+			Text:  jr.Text,
+			OldST: register.StatusRegister(jr.OldST),
+			NewST: register.StatusRegister(jr.NewST),
+		}
+
+		for _, c := range jr.Registers {
+			r.Registers = append(r.Registers, debug.RegisterChange(c))
+		}
+
+		return r, nil
+	}
+
+	if err := j.s.Err(); err != nil {
+		return Record{}, err
+	}
+
+	return Record{}, io.EOF
+}
+
+// ==== Binary
+
+// BinaryWriter writes records in the binary format
+type BinaryWriter struct {
+	w     *bufio.Writer
+	cycle uint64
+	cp    uint32
+	buf   []byte
+}
+
+// NewBinaryWriter constructs a BinaryWriter, and writes the header
+func NewBinaryWriter(w io.Writer) (*BinaryWriter, error) {
+	bw := bufio.NewWriter(w)
+	if _, err := bw.WriteString(Magic); err != nil {
+		return nil, err
+	}
+
+	if err := binary.Write(bw, binary.BigEndian, Version); err != nil {
+		return nil, err
+	}
+
+	return &BinaryWriter{w: bw}, nil
+}
+
+// Write writes a record:
+//
+//	flags              u8, flagCP and flagST
+//	cycle              uvarint, the difference from the cycle of the previous record
+//	CP                 u32, if flagCP
+//	PC                 uvarint
+//	bytes              u8 length and bytes
+//	old ST             u32
+//	new ST             u32, if flagST
+//	registers          u8 count, then for each the u8 index of its field, and the uvarint old and new values
+func (b *BinaryWriter) Write(r Record) error {
+	if (r.Cycle < b.cycle) || (len(r.Bytes) > 0xFF) || (len(r.Registers) > len(registerNames)) {
+		return fmt.Errorf("%w: record at cycle %d cannot be written", ErrCorrupt, r.Cycle)
+	}
+
+	var flags byte
+	if r.CP != b.cp {
+		flags |= flagCP
+	}
+
+	if r.NewST != r.OldST {
+		flags |= flagST
+	}
+
+	buf := append(b.buf[:0], flags)
+	buf = appendUvarint(buf, r.Cycle-b.cycle)
+	if flags&flagCP != 0 {
+		buf = appendUint32(buf, r.CP)
+	}
+
+	buf = appendUvarint(buf, uint64(r.PC))
+	buf = append(buf, byte(len(r.Bytes)))
+	buf = append(buf, r.Bytes...)
+	buf = appendUint32(buf, uint32(r.OldST))
+	if flags&flagST != 0 {
+		buf = appendUint32(buf, uint32(r.NewST))
+	}
+
+	buf = append(buf, byte(len(r.Registers)))
+	for _, c := range r.Registers {
+		i, ok := registerIndexes[c.Name]
+		if !ok {
+			return fmt.Errorf("%w: unknown register %s", ErrCorrupt, c.Name)
+		}
+
+		buf = append(buf, byte(i))
+		buf = appendUvarint(buf, c.Old)
+		buf = appendUvarint(buf, c.New)
+	}
+
+	b.buf, b.cycle, b.cp = buf, r.Cycle, r.CP
+	_, err := b.w.Write(buf)
+	return err
+}
+
+// Flush writes any buffered records
+func (b *BinaryWriter) Flush() error {
+	return b.w.Flush()
+}
+
+// appendUvarint appends a uvarint
+func appendUvarint(buf []byte, val uint64) []byte {
+	var v [binary.MaxVarintLen64]byte
+	return append(buf, v[:binary.PutUvarint(v[:], val)]...)
+}
+
+// appendUint32 appends a big endian uint32
+func appendUint32(buf []byte, val uint32) []byte {
+	var v [4]byte
+	binary.BigEndian.PutUint32(v[:], val)
+	return append(buf, v[:]...)
+}
+
+// binaryReader reads records in the binary format
+type binaryReader struct {
+	r     *bufio.Reader
+	cycle uint64
+	cp    uint32
+}
+
+func (b *binaryReader) Read() (Record, error) {
+	flags, err := b.r.ReadByte()
+	if err != nil {
+		return Record{}, err
+	}
+
+	var (
+		r     Record
+		delta uint64
+		pc    uint64
+		n     byte
+		st    uint32
+	)
+
+	// Any error after the flags means the record is truncated or invalid
+	corrupt := func(err error) (Record, error) {
+		return Record{}, fmt.Errorf("record after cycle %d: %w: %s", b.cycle, ErrCorrupt, err)
+	}
+
+	if delta, err = binary.ReadUvarint(b.r); err != nil {
+		return corrupt(err)
+	}
+
+	r.Cycle, r.CP = b.cycle+delta, b.cp
+	if flags&flagCP != 0 {
+		if err = binary.Read(b.r, binary.BigEndian, &r.CP); err != nil {
+			return corrupt(err)
+		}
+	}
+
+	if pc, err = binary.ReadUvarint(b.r); err != nil {
+		return corrupt(err)
+	}
+
+	r.PC = uint32(pc)
+	if n, err = b.r.ReadByte(); err != nil {
+		return corrupt(err)
+	}
+
+	r.Bytes = make([]byte, n)
+	if _, err = io.ReadFull(b.r, r.Bytes); err != nil {
+		return corrupt(err)
+	}
+
+	if err = binary.Read(b.r, binary.BigEndian, &st); err != nil {
+		return corrupt(err)
+	}
+
+	r.OldST, r.NewST = register.StatusRegister(st), register.StatusRegister(st)
+	if flags&flagST != 0 {
+		if err = binary.Read(b.r, binary.BigEndian, &st); err != nil {
+			return corrupt(err)
+		}
+
+		r.NewST = register.StatusRegister(st)
+	}
+
+	if n, err = b.r.ReadByte(); err != nil {
+		return corrupt(err)
+	}
+
+	for ; n > 0; n-- {
+		var c debug.RegisterChange
+		i, err := b.r.ReadByte()
+		if err != nil {
+			return corrupt(err)
+		}
+
+		if int(i) >= len(registerNames) {
+			return corrupt(fmt.Errorf("register index %d", i))
+		}
+
+		c.Name = registerNames[i]
+		if c.Old, err = binary.ReadUvarint(b.r); err != nil {
+			return corrupt(err)
+		}
+
+		if c.New, err = binary.ReadUvarint(b.r); err != nil {
+			return corrupt(err)
+		}
+
+		r.Registers = append(r.Registers, c)
+	}
+
+	r.Text = InterruptText
+	if len(r.Bytes) > 0 {
+		r.Text = disasm.Decode(r.Bytes, r.PC, r.OldST.EffectiveOperandSize()).Text(nil)
+	}
+
+	b.cycle, b.cp = r.Cycle, r.CP
+	return r, nil
+}
+
+// NewReader constructs a Reader of a trace in either format, which is detected by the magic bytes of the binary
+// format
+func NewReader(r io.Reader) (Reader, error) {
+	br := bufio.NewReader(r)
+	if magic, _ := br.Peek(len(Magic)); string(magic) != Magic {
+		s := bufio.NewScanner(br)
+		s.Buffer(nil, 1<<20)
+		return &jsonReader{s: s}, nil
+	}
+
+	br.Discard(len(Magic))
+	var version uint16
+	if err := binary.Read(br, binary.BigEndian, &version); err != nil {
+		return nil, ErrCorrupt
+	}
+
+	if version != Version {
+		return nil, ErrVersion
+	}
+
+	return &binaryReader{r: br}, nil
+}
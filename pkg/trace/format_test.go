@@ -0,0 +1,128 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package trace
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/bantling/goprocessor/pkg/debug"
+	"github.com/bantling/goprocessor/pkg/register"
+	"github.com/stretchr/testify/assert"
+)
+
+// testRecords change CP, ST, and registers
+var testRecords = []Record{
+	{
+		Cycle:     0,
+		CP:        0x1000,
+		PC:        0,
+		Bytes:     []byte{0xFF, 0x43, 0x02},
+		Text:      "MOV    R0,0x02",
+		Registers: []debug.RegisterChange{{Name: "R0", Old: 0, New: 2}, {Name: "PC", Old: 0, New: 3}},
+		OldST:     register.StatusRegister(0x80),
+		NewST:     register.StatusRegister(0x80),
+	},
+	{
+		Cycle:     300,
+		CP:        0x2000,
+		PC:        0x10,
+		Bytes:     []byte{0xDA},
+		Text:      "DEC    R0",
+		Registers: []debug.RegisterChange{{Name: "R0", Old: 1, New: 0}, {Name: "PC", Old: 0x10, New: 0x11}},
+		OldST:     register.StatusRegister(0x80),
+		NewST:     register.StatusRegister(0x82),
+	},
+	{
+		Cycle: 301,
+		CP:    0x2000,
+		PC:    0x11,
+		Bytes: []byte{0xFE},
+		Text:  "NOP",
+	},
+}
+
+// readAll reads all records
+func readAll(t *testing.T, r io.Reader) []Record {
+	tr, err := NewReader(r)
+	assert.Nil(t, err)
+
+	var recs []Record
+	for {
+		rec, err := tr.Read()
+		if err == io.EOF {
+			return recs
+		}
+
+		assert.Nil(t, err)
+		recs = append(recs, rec)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewJSONWriter(&buf)
+	for _, r := range testRecords {
+		assert.Nil(t, w.Write(r))
+	}
+	assert.Nil(t, w.Flush())
+
+	line := strings.SplitN(buf.String(), "\n", 2)[0]
+	assert.Equal(
+		t,
+		`{"cycle":0,"cp":4096,"pc":0,"bytes":"FF4302","text":"MOV    R0,0x02",`+
+			`"regs":[{"name":"R0","old":0,"new":2},{"name":"PC","old":0,"new":3}],"oldST":128,"newST":128}`,
+		line,
+	)
+	assert.Equal(t, testRecords, readAll(t, &buf))
+
+	// Blank lines are skipped, and errors have line numbers
+	assert.Equal(t, []Record(nil), readAll(t, strings.NewReader("\n\n")))
+
+	tr, _ := NewReader(strings.NewReader("\n{\"bytes\":\"F\"}"))
+	_, err := tr.Read()
+	assert.True(t, errors.Is(err, ErrCorrupt))
+	assert.Equal(t, "line 2: Corrupt Trace: invalid bytes F", err.Error())
+
+	tr, _ = NewReader(strings.NewReader("{"))
+	_, err = tr.Read()
+	assert.True(t, errors.Is(err, ErrCorrupt))
+}
+
+func TestBinary(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewBinaryWriter(&buf)
+	assert.Nil(t, err)
+	for _, r := range testRecords {
+		assert.Nil(t, w.Write(r))
+	}
+	assert.Nil(t, w.Flush())
+
+	data := buf.Bytes()
+	assert.Equal(t, []byte("GPTR\x00\x01"), data[:6])
+
+	// The NOP record is the flags, cycle difference, PC, 1 byte, ST, and no registers
+	assert.Equal(t, []byte{0x00, 0x01, 0x11, 0x01, 0xFE, 0x00, 0x00, 0x00, 0x00, 0x00}, data[len(data)-10:])
+	assert.Equal(t, testRecords, readAll(t, bytes.NewReader(data)))
+
+	// Every truncation of a record is corrupt
+	for n := len(data) - 9; n < len(data); n++ {
+		tr, err := NewReader(bytes.NewReader(data[:n]))
+		assert.Nil(t, err)
+		tr.Read()
+		tr.Read()
+		_, err = tr.Read()
+		assert.True(t, errors.Is(err, ErrCorrupt), n)
+	}
+
+	_, err = NewReader(strings.NewReader("GPTR\x00\x02"))
+	assert.Equal(t, ErrVersion, err)
+	_, err = NewReader(strings.NewReader("GPTR\x00"))
+	assert.Equal(t, ErrCorrupt, err)
+
+	// Cycles must not go backwards
+	assert.True(t, errors.Is(w.Write(testRecords[0]), ErrCorrupt))
+}
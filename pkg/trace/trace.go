@@ -0,0 +1,180 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package trace
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"reflect"
+
+	"github.com/bantling/goprocessor/pkg/cpu"
+	"github.com/bantling/goprocessor/pkg/debug"
+	"github.com/bantling/goprocessor/pkg/register"
+)
+
+// InterruptText is the Text of a record of entering an interrupt routine
+const InterruptText = "INTERRUPT"
+
+// Record describes one executed instruction, or entering an interrupt routine.
+// A record of entering a routine has no Bytes, its CP and PC are the interrupted instruction, and its Registers are
+// those changed by entering the routine.
+type Record struct {
+	Cycle     uint64
+	CP        uint32
+	PC        uint32
+	Bytes     []byte
+	Text      string
+	Registers []debug.RegisterChange // nil if no registers other than ST changed
+	OldST     register.StatusRegister
+	NewST     register.StatusRegister
+}
+
+// Addr returns the absolute address of the instruction, CP + PC
+func (r Record) Addr() uint32 {
+	return r.CP + r.PC
+}
+
+// Equal is true if two records are the same
+func (r Record) Equal(o Record) bool {
+	return (r.Cycle == o.Cycle) &&
+		(r.CP == o.CP) &&
+		(r.PC == o.PC) &&
+		bytes.Equal(r.Bytes, o.Bytes) &&
+		(r.Text == o.Text) &&
+		(len(r.Registers) == len(o.Registers)) &&
+		((len(r.Registers) == 0) || reflect.DeepEqual(r.Registers, o.Registers)) &&
+		(r.OldST == o.OldST) &&
+		(r.NewST == o.NewST)
+}
+
+// Writer writes records
+type Writer interface {
+	// Write writes a record
+	Write(r Record) error
+
+	// Flush writes any buffered records
+	Flush() error
+}
+
+// Reader reads records
+type Reader interface {
+	// Read reads the next record, returning io.EOF if there are no more records
+	Read() (Record, error)
+}
+
+// Tracer steps a processor, writing a Record of each instruction it executes
+type Tracer struct {
+	proc *cpu.Processor
+	w    Writer
+}
+
+// NewTracer constructs a Tracer of a processor that writes to w
+func NewTracer(proc *cpu.Processor, w Writer) *Tracer {
+	return &Tracer{proc: proc, w: w}
+}
+
+// Step executes one instruction, and writes a Record of it if it succeeds.
+// If the processor enters an interrupt routine first, a Record of entering it is written before the Record of the
+// first instruction of the routine.
+func (t *Tracer) Step() error {
+	var (
+		regs   = t.proc.Registers()
+		before = *regs
+		cycle  = t.proc.Cycles()
+	)
+
+	entered, err := t.proc.Interrupt()
+	if err != nil {
+		return err
+	}
+
+	if entered {
+		if err := t.w.Write(Record{
+			Cycle:     cycle,
+			CP:        before.CP,
+			PC:        before.PC,
+			Text:      InterruptText,
+			Registers: debug.RegisterChanges(&before, regs),
+			OldST:     before.ST(),
+			NewST:     regs.ST(),
+		}); err != nil {
+			return err
+		}
+
+		before = *regs
+	}
+
+	ins := debug.Fetch(t.proc.Bus(), regs)
+	if err := t.proc.Execute(); err != nil {
+		return err
+	}
+
+	return t.w.Write(Record{
+		Cycle:     cycle,
+		CP:        before.CP,
+		PC:        before.PC,
+		Bytes:     ins.Bytes,
+		Text:      ins.Text(nil),
+		Registers: debug.RegisterChanges(&before, regs),
+		OldST:     before.ST(),
+		NewST:     regs.ST(),
+	})
+}
+
+// Run steps until an instruction fails, writing a record fails, or the context is done.
+// The records are flushed before returning.
+func (t *Tracer) Run(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			if err := t.w.Flush(); err != nil {
+				return err
+			}
+
+			return ctx.Err()
+		default:
+		}
+
+		if err := t.Step(); err != nil {
+			t.w.Flush()
+			return err
+		}
+	}
+}
+
+// Divergence is the first difference between two traces
+type Divergence struct {
+	// Index is the number of records that are the same before the difference
+	Index int
+
+	// A and B are the records that differ, where a nil record means the trace ended
+	A *Record
+	B *Record
+}
+
+// Diff compares two traces, and returns the first difference, or nil if they are the same
+func Diff(a, b Reader) (*Divergence, error) {
+	for i := 0; ; i++ {
+		ra, erra := a.Read()
+		if (erra != nil) && (erra != io.EOF) {
+			return nil, erra
+		}
+
+		rb, errb := b.Read()
+		if (errb != nil) && (errb != io.EOF) {
+			return nil, errb
+		}
+
+		switch {
+		case (erra == io.EOF) && (errb == io.EOF):
+			return nil, nil
+		case erra == io.EOF:
+			return &Divergence{Index: i, B: &rb}, nil
+		case errb == io.EOF:
+			return &Divergence{Index: i, A: &ra}, nil
+		case !ra.Equal(rb):
+			return &Divergence{Index: i, A: &ra, B: &rb}, nil
+		}
+	}
+}
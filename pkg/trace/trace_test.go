@@ -0,0 +1,182 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package trace
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/bantling/goprocessor/pkg/cpu"
+	"github.com/bantling/goprocessor/pkg/debug"
+	"github.com/bantling/goprocessor/pkg/internal/testprog"
+	"github.com/bantling/goprocessor/pkg/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+// program counts R0 down from 2 to 0, then executes an illegal instruction
+const program = `
+        MOV     R0, 2
+loop:   DEC     R0
+        BNE     loop
+        .u8     0xFF, 0xFF
+`
+
+// memWriter collects records, and reads them back
+type memWriter struct {
+	records []Record
+	flushed bool
+}
+
+func (m *memWriter) Write(r Record) error {
+	m.records = append(m.records, r)
+	return nil
+}
+
+func (m *memWriter) Flush() error {
+	m.flushed = true
+	return nil
+}
+
+func (m *memWriter) Read() (Record, error) {
+	if len(m.records) == 0 {
+		return Record{}, io.EOF
+	}
+
+	r := m.records[0]
+	m.records = m.records[1:]
+	return r, nil
+}
+
+// records traces program until the illegal instruction
+func records(t *testing.T, src string) []Record {
+	w := &memWriter{}
+	err := NewTracer(testprog.Processor(t, src, 0x1000), w).Run(context.Background())
+	assert.True(t, errors.Is(err, cpu.ErrIllegalOpcode))
+	assert.True(t, w.flushed)
+
+	return w.records
+}
+
+func TestTracer(t *testing.T) {
+	recs := records(t, program)
+	assert.Equal(t, 5, len(recs))
+
+	assert.Equal(
+		t,
+		Record{
+			Cycle: 0,
+			CP:    0x1000,
+			PC:    0,
+			Bytes: []byte{0xFF, 0x43, 0x02},
+			Text:  "MOV    R0,0x02",
+			Registers: []debug.RegisterChange{
+				{Name: "R0", Old: 0, New: 2},
+				{Name: "PC", Old: 0, New: 3},
+			},
+		},
+		recs[0],
+	)
+	assert.Equal(t, uint32(0x1000), recs[0].Addr())
+
+	// The second DEC sets Zero, and the BNE falls through
+	dec := recs[3]
+	assert.Equal(t, uint64(6), dec.Cycle)
+	assert.Equal(t, "DEC    R0", dec.Text)
+	assert.False(t, dec.OldST.IsZero())
+	assert.True(t, dec.NewST.IsZero())
+	assert.Equal(t, uint64(7), recs[4].Cycle)
+	assert.Equal(t, []debug.RegisterChange{{Name: "PC", Old: 4, New: 6}}, recs[4].Registers)
+
+	// The context stops running
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	w := &memWriter{}
+	assert.Equal(t, context.Canceled, NewTracer(testprog.Processor(t, program, 0x1000), w).Run(ctx))
+	assert.True(t, w.flushed)
+	assert.Equal(t, 0, len(w.records))
+}
+
+func TestTracerInterrupt(t *testing.T) {
+	// The IRQ is entered before the MOV, and the routine runs into an illegal instruction
+	p := testprog.Processor(t, program, 0x1000)
+	assert.Nil(t, p.Bus().Write32(cpu.IRQVector(0), 0x30000))
+	assert.Nil(t, memory.WriteBytes(p.Bus(), 0x30000, []byte{0xFE, 0xFF, 0xFF}))
+	p.Raise(0)
+
+	w := &memWriter{}
+	assert.True(t, errors.Is(NewTracer(p, w).Run(context.Background()), cpu.ErrIllegalOpcode))
+	assert.Equal(t, 2, len(w.records))
+
+	entry := w.records[0]
+	assert.Equal(t, InterruptText, entry.Text)
+	assert.Equal(t, 0, len(entry.Bytes))
+	assert.Equal(t, uint32(0x1000), entry.CP)
+	assert.Equal(t, uint32(0), entry.PC)
+	assert.Contains(t, entry.Registers, debug.RegisterChange{Name: "CP", Old: 0x1000, New: 0x30000})
+
+	nop := w.records[1]
+	assert.Equal(t, "NOP", nop.Text)
+	assert.Equal(t, uint32(0x30000), nop.CP)
+	assert.Equal(t, uint32(0), nop.PC)
+
+	// The binary format reads the entry back with its text
+	var buf bytes.Buffer
+	bw, err := NewBinaryWriter(&buf)
+	assert.Nil(t, err)
+	assert.Nil(t, bw.Write(entry))
+	assert.Nil(t, bw.Flush())
+
+	r, err := NewReader(&buf)
+	assert.Nil(t, err)
+	rec, err := r.Read()
+	assert.Nil(t, err)
+	assert.True(t, entry.Equal(rec))
+}
+
+func TestDiff(t *testing.T) {
+	var (
+		a = records(t, program)
+		b = records(t, strings.Replace(program, "R0, 2", "R0, 3", 1))
+	)
+
+	// The same trace in different formats
+	var jbuf, bbuf bytes.Buffer
+	jw := NewJSONWriter(&jbuf)
+	bw, err := NewBinaryWriter(&bbuf)
+	assert.Nil(t, err)
+	for _, r := range a {
+		assert.Nil(t, jw.Write(r))
+		assert.Nil(t, bw.Write(r))
+	}
+	assert.Nil(t, jw.Flush())
+	assert.Nil(t, bw.Flush())
+
+	jr, err := NewReader(&jbuf)
+	assert.Nil(t, err)
+	br, err := NewReader(&bbuf)
+	assert.Nil(t, err)
+
+	d, err := Diff(jr, br)
+	assert.Nil(t, err)
+	assert.Nil(t, d)
+
+	// Different immediates diverge at the first instruction
+	d, err = Diff(&memWriter{records: a}, &memWriter{records: b})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, d.Index)
+	assert.Equal(t, "MOV    R0,0x02", d.A.Text)
+	assert.Equal(t, "MOV    R0,0x03", d.B.Text)
+
+	// A trace that ends early diverges after its last record
+	d, err = Diff(&memWriter{records: a[:2]}, &memWriter{records: a})
+	assert.Nil(t, err)
+	assert.Equal(t, &Divergence{Index: 2, B: &a[2]}, d)
+
+	d, err = Diff(&memWriter{records: a}, &memWriter{records: a[:4]})
+	assert.Nil(t, err)
+	assert.Equal(t, &Divergence{Index: 4, A: &a[4]}, d)
+}